@@ -2,13 +2,11 @@ package app
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
-	"net"
+	"io"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/azahir21/go-backend-boilerplate/cmd/service"
 	"github.com/azahir21/go-backend-boilerplate/ent"
@@ -16,14 +14,22 @@ import (
 	"github.com/azahir21/go-backend-boilerplate/infrastructure/db"
 	"github.com/azahir21/go-backend-boilerplate/infrastructure/db/mongo"
 	"github.com/azahir21/go-backend-boilerplate/infrastructure/external"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/lock"
 	"github.com/azahir21/go-backend-boilerplate/infrastructure/storage"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/helper"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/jobs"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/outbox"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/scheduler"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/health"
+	"github.com/azahir21/go-backend-boilerplate/pkg/lifecycle"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
 )
 
@@ -31,11 +37,17 @@ import (
 type Application struct {
 	Log            *logrus.Logger
 	Config         *config.Config
+	TracerProvider *sdktrace.TracerProvider
+	Lifecycle      *lifecycle.Manager
+	Health         *health.Registry
 	DBClient       *ent.Client
 	MongoClient    *mongo.Client
 	Cache          cache.Cache
+	Locks          *lock.Locker
 	Storage        storage.Storage
 	EmailClient    external.EmailClient
+	Jobs           *jobs.Client
+	Scheduler      *scheduler.Scheduler
 	Dependencies   *module.Dependencies
 	HTTPModules    []module.HTTPModule
 	GRPCModules    []module.GRPCModule
@@ -43,15 +55,110 @@ type Application struct {
 	HTTPServer     *http.Server
 	GRPCServer     *grpc.Server
 	GraphQLServer  *http.Server
+	MetricsServer  *http.Server
+
+	transports transports
+	worker     bool
+}
+
+// transports are the delivery layers selected when building the application.
+type transports struct {
+	rest, grpc, graphql bool
+}
+
+// Option selects a delivery layer to serve.
+type Option func(*Application)
+
+// WithREST serves the REST API when server.http_server is enabled.
+func WithREST() Option {
+	return func(app *Application) { app.transports.rest = true }
+}
+
+// WithGRPC serves the gRPC API when server.grpc_server is enabled.
+func WithGRPC() Option {
+	return func(app *Application) { app.transports.grpc = true }
 }
 
-// NewApplication initializes and returns a new Application instance.
-func NewApplication(log *logrus.Logger) (*Application, error) {
+// WithGraphQL serves the GraphQL API when server.graphql_server is enabled.
+func WithGraphQL() Option {
+	return func(app *Application) { app.transports.graphql = true }
+}
+
+// WithWorker runs a job worker. Without a delivery layer option the application runs only
+// the worker, and the metrics server when server.metrics.port is set.
+func WithWorker() Option {
+	return func(app *Application) { app.worker = true }
+}
+
+// New loads the configuration, initializes the infrastructure and creates the servers of the
+// selected delivery layers; all of them when no option is given. A job worker runs with
+// WithWorker, or with jobs.embedded_worker when serving. Every component registers
+// a stop hook on the lifecycle manager as it is created, so a failure part-way through
+// releases everything created before it.
+func New(log *logrus.Logger, opts ...Option) (_ *Application, err error) {
+	app := &Application{Log: log, Lifecycle: lifecycle.NewManager(log)}
+	for _, opt := range opts {
+		opt(app)
+	}
+	if app.transports == (transports{}) && !app.worker {
+		app.transports = transports{rest: true, grpc: true, graphql: true}
+	}
+
+	defer func() {
+		if err != nil {
+			if stopErr := app.Lifecycle.Stop(context.Background()); stopErr != nil {
+				log.WithError(stopErr).Warn("Failed to release components after initialization failure")
+			}
+		}
+	}()
+
+	if err := app.initInfrastructure(); err != nil {
+		return nil, err
+	}
+
+	// Modules are imported in module_registery.go
+	if err := app.registerModules(); err != nil {
+		return nil, err
+	}
+
+	if err := app.setupWorker(); err != nil {
+		return nil, err
+	}
+
+	if app.transports == (transports{}) {
+		app.setupMetricsServer(false)
+		return app, nil
+	}
+	if err := app.setupServers(); err != nil {
+		return nil, fmt.Errorf("failed to setup servers: %w", err)
+	}
+	return app, nil
+}
+
+// Run builds the application with the given options and serves until shutdown.
+func Run(log *logrus.Logger, opts ...Option) error {
+	app, err := New(log, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to initialize application: %w", err)
+	}
+	return app.Run()
+}
+
+// Run starts the servers and blocks until the application has shut down.
+func (app *Application) Run() error {
+	return service.Run(app.Log, app.Config.Server.Shutdown, app.Lifecycle, app.Health)
+}
+
+// initInfrastructure loads the configuration and creates the clients shared by all modules.
+func (app *Application) initInfrastructure() error {
+	log := app.Log
+
 	// Load configuration
 	cfg, err := config.LoadConfig(log)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
+		return fmt.Errorf("failed to load config: %w", err)
 	}
+	app.Config = cfg
 
 	// Set Gin mode based on environment
 	if cfg.Server.Env == "production" || cfg.Server.Env == "staging" {
@@ -61,235 +168,266 @@ func NewApplication(log *logrus.Logger) (*Application, error) {
 	// Initialize JWT helper
 	helper.InitJWT(cfg.JWT.Secret, cfg.JWT.ExpiryHours)
 
+	// Initialize tracing before the infrastructure clients
+	app.TracerProvider, err = tracing.NewTracerProvider(context.Background(), log, cfg.Server.Tracing)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	if app.TracerProvider != nil {
+		// Registered first so that spans are flushed after everything else has stopped
+		app.Lifecycle.Append(lifecycle.Hook{Name: "tracing", OnStop: app.TracerProvider.Shutdown})
+	}
+
 	// Initialize database (optional)
-	var dbClient *ent.Client
 	var uow unitofwork.UnitOfWork
+	var sqlDB *sql.DB
 	if cfg.DB.Enable {
-		var err error
-		dbClient, err = db.NewEntClient(log, cfg)
+		app.DBClient, sqlDB, err = db.NewEntClient(log, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		app.Lifecycle.Append(lifecycle.CloseHook("database", app.DBClient))
+		isolation, err := unitofwork.ParseIsolation(cfg.DB.Transaction.Isolation)
 		if err != nil {
-			return nil, fmt.Errorf("failed to initialize database: %w", err)
+			return fmt.Errorf("invalid database.transaction.isolation: %w", err)
 		}
-		// Initialize unit of work
-		uow = unitofwork.NewUnitOfWork(dbClient)
+		uow = unitofwork.NewUnitOfWork(app.DBClient, log,
+			unitofwork.WithIsolation(isolation),
+			unitofwork.WithMaxRetries(cfg.DB.Transaction.MaxRetries),
+		)
 	} else {
 		log.Info("SQL Database is disabled, skipping initialization")
 	}
 
 	// Initialize MongoDB (optional)
-	var mongoClient *mongo.Client
 	if cfg.Mongo.Enable {
-		var err error
-		mongoClient, err = mongo.NewClient(log, cfg.Mongo)
+		app.MongoClient, err = mongo.NewClient(log, cfg.Mongo)
 		if err != nil {
-			if dbClient != nil {
-				dbClient.Close()
-			}
-			return nil, fmt.Errorf("failed to initialize MongoDB: %w", err)
+			return fmt.Errorf("failed to initialize MongoDB: %w", err)
 		}
+		app.Lifecycle.Append(lifecycle.CloseHook("MongoDB", app.MongoClient))
 	} else {
 		log.Info("MongoDB is disabled, skipping initialization")
 	}
 
 	// Initialize cache (optional)
-	var appCache cache.Cache
 	if cfg.Cache.Enable {
-		var err error
-		appCache, err = cache.NewCache(log, cfg.Cache)
+		app.Cache, err = cache.NewCache(log, cfg.Cache)
 		if err != nil {
-			if dbClient != nil {
-				dbClient.Close()
-			}
-			if mongoClient != nil {
-				mongoClient.Close()
-			}
-			return nil, fmt.Errorf("failed to initialize cache: %w", err)
+			return fmt.Errorf("failed to initialize cache: %w", err)
+		}
+		if closer, ok := app.Cache.(io.Closer); ok {
+			app.Lifecycle.Append(lifecycle.CloseHook("cache", closer))
 		}
 	} else {
 		log.Info("Cache is disabled, skipping initialization")
 	}
 
+	// Invalidate tagged cache entries (e.g. cached HTTP responses) on every SQL write
+	var cacheTags cache.Invalidator
+	if app.Cache != nil {
+		cacheTags = cache.NewTagStore(app.Cache)
+		if app.DBClient != nil {
+			app.DBClient.Use(db.CacheInvalidationHook(log, cacheTags))
+		}
+	}
+
+	// Initialize distributed locks on the redis cache or the SQL database, when available
+	lockBackend := lock.NewBackend(log, app.Cache, app.DBClient, sqlDB, cfg.DB.Driver)
+	if closer, ok := lockBackend.(io.Closer); ok {
+		app.Lifecycle.Append(lifecycle.CloseHook("locks", closer))
+	}
+	app.Locks = lock.New(lockBackend)
+
 	// Initialize storage (optional)
-	var appStorage storage.Storage
 	if cfg.Storage.Enable {
-		var err error
-		appStorage, err = storage.NewStorage(context.Background(), log, cfg.Storage)
+		app.Storage, err = storage.NewStorage(context.Background(), log, cfg.Storage)
 		if err != nil {
-			if dbClient != nil {
-				dbClient.Close()
-			}
-			if mongoClient != nil {
-				mongoClient.Close()
-			}
-			return nil, fmt.Errorf("failed to initialize storage: %w", err)
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+		if closer, ok := app.Storage.(io.Closer); ok {
+			app.Lifecycle.Append(lifecycle.CloseHook("storage", closer))
 		}
 	} else {
 		log.Info("Storage is disabled, skipping initialization")
 	}
 
 	// Initialize email client (optional)
-	var emailClient external.EmailClient
 	if cfg.Email.Enable {
-		var err error
-		emailClient, err = external.NewEmailClient(log, cfg.Email)
+		app.EmailClient, err = external.NewEmailClient(log, cfg.Email)
 		if err != nil {
-			if dbClient != nil {
-				dbClient.Close()
-			}
-			if mongoClient != nil {
-				mongoClient.Close()
-			}
-			return nil, fmt.Errorf("failed to initialize email client: %w", err)
+			return fmt.Errorf("failed to initialize email client: %w", err)
 		}
 	} else {
 		log.Info("Email client is disabled, skipping initialization")
 	}
 
+	// Initialize the outbox dispatcher (optional, needs the SQL database)
+	events := outbox.NewBus()
+	if cfg.Outbox.Enable && !cfg.Outbox.DisableDispatcher && app.DBClient != nil {
+		if cfg.Outbox.Webhook.URL != "" {
+			sink, err := outbox.NewWebhookSink(cfg.Outbox.Webhook)
+			if err != nil {
+				return err
+			}
+			events.AddSink(sink)
+		}
+		dispatcher, err := outbox.NewDispatcher(app.DBClient, events, log, cfg.Outbox)
+		if err != nil {
+			return fmt.Errorf("failed to initialize outbox dispatcher: %w", err)
+		}
+		app.Lifecycle.Append(lifecycle.Hook{Name: "outbox dispatcher", OnStart: dispatcher.Start, OnStop: dispatcher.Stop})
+	} else {
+		log.Info("Outbox dispatcher is disabled, skipping initialization")
+	}
+
+	// Initialize the job queue (optional, needs the SQL database or a redis cache)
+	if cfg.Jobs.Enable {
+		queue, err := jobs.NewQueue(log, app.DBClient, app.Cache)
+		if err != nil {
+			return fmt.Errorf("failed to initialize job queue: %w", err)
+		}
+		app.Jobs = jobs.NewClient(queue, cfg.Jobs.MaxAttempts)
+	} else {
+		log.Info("Jobs are disabled, skipping initialization")
+	}
+
+	// Initialize the scheduler (optional, needs the SQL database for its run history)
+	if cfg.Scheduler.Enable && app.DBClient != nil {
+		app.Scheduler, err = scheduler.New(app.DBClient, app.Locks, log, cfg.Scheduler)
+		if err != nil {
+			return fmt.Errorf("failed to initialize scheduler: %w", err)
+		}
+		app.Lifecycle.Append(lifecycle.Hook{Name: "scheduler", OnStart: app.Scheduler.Start, OnStop: app.Scheduler.Stop})
+	} else {
+		log.Info("Scheduler is disabled, skipping initialization")
+	}
+
 	// Create shared dependencies for all modules
-	deps := &module.Dependencies{
+	app.Dependencies = &module.Dependencies{
 		Log:         log,
-		DBClient:    dbClient,
-		MongoClient: mongoClient,
-		Cache:       appCache,
-		Storage:     appStorage,
-		EmailClient: emailClient,
+		DBClient:    app.DBClient,
+		MongoClient: app.MongoClient,
+		Cache:       app.Cache,
+		CacheTags:   cacheTags,
+		Locks:       app.Locks,
+		Storage:     app.Storage,
+		EmailClient: app.EmailClient,
 		UoW:         uow,
+		Publisher:   outbox.NewPublisher(cfg.Outbox.Enable),
+		Events:      events,
+		Jobs:        app.Jobs,
+		Scheduler:   app.Scheduler,
 	}
-
-	// Register modules - add new modules here
-	httpModules, grpcModules, graphqlModules := registerModules(deps)
-
-	return &Application{
-		Log:            log,
-		Config:         cfg,
-		DBClient:       dbClient,
-		MongoClient:    mongoClient,
-		Cache:          appCache,
-		Storage:        appStorage,
-		EmailClient:    emailClient,
-		Dependencies:   deps,
-		HTTPModules:    httpModules,
-		GRPCModules:    grpcModules,
-		GraphQLModules: graphqlModules,
-	}, nil
+	return nil
 }
 
-func Run(log *logrus.Logger) error {
-	app, err := NewApplication(log)
+// setupWorker creates the job worker when it runs in this process. Modules have registered
+// their job handlers by now.
+func (app *Application) setupWorker() error {
+	if !app.worker && !(app.Config.Jobs.EmbeddedWorker && app.Jobs != nil) {
+		return nil
+	}
+	if app.Jobs == nil {
+		return errors.New("cannot run a job worker: jobs are disabled (jobs.enable)")
+	}
+	worker, err := jobs.NewWorker(app.Jobs, app.Log, app.Config.Jobs)
 	if err != nil {
-		return fmt.Errorf("failed to initialize application: %w", err)
+		return fmt.Errorf("failed to initialize job worker: %w", err)
 	}
-	// Ensure DB clients are closed
-	defer func() {
-		if app.DBClient != nil {
-			app.DBClient.Close()
-		}
-		if app.MongoClient != nil {
-			app.MongoClient.Close()
-		}
-	}()
-
-	// Create a context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	app.Lifecycle.Append(lifecycle.Hook{Name: "job worker", OnStart: worker.Start, OnStop: worker.Stop})
+	return nil
+}
 
-	// Setup servers
-	if err := app.setupServers(); err != nil {
-		return fmt.Errorf("failed to setup servers: %w", err)
+// setupMetricsServer creates the dedicated metrics server when server.metrics.port is set.
+// served tells whether an HTTP server of the application serves the metrics otherwise.
+func (app *Application) setupMetricsServer(served bool) {
+	metricsCfg := app.Config.Server.Metrics
+	app.MetricsServer = service.NewMetricsServer(app.Log, metricsCfg)
+	if app.MetricsServer != nil {
+		app.Lifecycle.Append(service.HTTPServerHook(app.Log, "metrics server", app.MetricsServer))
+	} else if metricsCfg.Enable && !served {
+		app.Log.Warn("Metrics are only served by HTTP servers; set server.metrics.port to expose them")
 	}
+}
 
-	// Start servers
-	app.startServers(ctx)
-
-	// Graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
-
-	app.Log.Info("Shutting down servers...")
-	if app.HTTPServer != nil {
-		app.HTTPServer.Shutdown(ctx)
+// setupServers creates the servers of the selected delivery layers that are enabled in the
+// configuration.
+func (app *Application) setupServers() error {
+	serverCfg := app.Config.Server
+	restEnabled := app.transports.rest && serverCfg.HTTP.Enable
+	grpcEnabled := app.transports.grpc && serverCfg.GRPC.Enable
+	graphqlEnabled := app.transports.graphql && serverCfg.GraphQL.Enable
+	if graphqlEnabled && len(app.GraphQLModules) == 0 {
+		// A GraphQL schema needs at least one query field
+		app.Log.Warn("No enabled module provides GraphQL fields; the GraphQL server is not started")
+		graphqlEnabled = false
 	}
-	if app.GRPCServer != nil {
-		app.GRPCServer.GracefulStop()
+	if !restEnabled && !grpcEnabled && !graphqlEnabled {
+		return errors.New("no server enabled. Please enable at least one of the selected HTTP, gRPC or GraphQL servers")
 	}
-	if app.GraphQLServer != nil {
-		app.GraphQLServer.Shutdown(ctx)
+
+	// In single-port mode every server is created for the shared port and TLS settings and
+	// served by one listener
+	singlePort := serverCfg.SinglePort.Enable
+	if singlePort {
+		if serverCfg.SinglePort.Port == "" {
+			return errors.New("server.single_port.port is required when single-port mode is enabled")
+		}
+		serverCfg.HTTP.Port = serverCfg.SinglePort.Port
+		serverCfg.GRPC.Port = serverCfg.SinglePort.Port
+		serverCfg.GraphQL.Port = serverCfg.SinglePort.Port
+		serverCfg.HTTP.TLS = serverCfg.SinglePort.TLS
+		serverCfg.GRPC.TLS = serverCfg.SinglePort.TLS
+		serverCfg.GraphQL.TLS = serverCfg.SinglePort.TLS
 	}
-	app.Log.Info("Servers stopped.")
-	return nil
-}
 
-// setupServers creates HTTP, gRPC, and GraphQL servers based on configuration.
-func (app *Application) setupServers() error {
-	if app.Config.Server.HTTP.Enable {
-		srv, err := service.NewRestServer(app.Log, app.Config.Server.HTTP, app.HTTPModules)
+	opts := service.NewOptions(app.Log, app.Config, app.Cache)
+	healthRegistry, err := service.NewHealthRegistry(serverCfg.Health, app.Dependencies)
+	if err != nil {
+		return fmt.Errorf("failed to configure health checks: %w", err)
+	}
+	opts.Health = healthRegistry
+	app.Health = healthRegistry
+	app.setupMetricsServer(restEnabled || graphqlEnabled)
+
+	if restEnabled {
+		srv, err := service.NewRestServer(app.Log, serverCfg.HTTP, opts, app.HTTPModules)
 		if err != nil {
 			return fmt.Errorf("failed to create HTTP server: %w", err)
 		}
 		app.HTTPServer = srv
 	}
 
-	if app.Config.Server.GRPC.Enable {
-		grpcServer, err := service.NewGrpcServer(app.Log, app.Config.Server.GRPC, app.GRPCModules)
+	if grpcEnabled {
+		grpcServer, err := service.NewGrpcServer(app.Log, serverCfg.GRPC, opts, app.GRPCModules)
 		if err != nil {
 			return fmt.Errorf("failed to create gRPC server: %w", err)
 		}
 		app.GRPCServer = grpcServer
 	}
 
-	if app.Config.Server.GraphQL.Enable {
-		srv, err := service.NewGraphQLServer(app.Log, app.Config.Server.GraphQL, app.GraphQLModules)
+	if graphqlEnabled {
+		srv, err := service.NewGraphQLServer(app.Log, serverCfg.GraphQL, opts, app.GraphQLModules)
 		if err != nil {
 			return fmt.Errorf("failed to create GraphQL server: %w", err)
 		}
 		app.GraphQLServer = srv
 	}
 
-	if !app.Config.Server.HTTP.Enable && !app.Config.Server.GRPC.Enable && !app.Config.Server.GraphQL.Enable {
-		return errors.New("no server enabled. Please enable at least one of HTTP, gRPC or GraphQL")
+	if singlePort {
+		httpServer := service.NewMultiplexedHTTPServer(app.HTTPServer, app.GraphQLServer, app.GRPCServer)
+		app.Lifecycle.Append(service.MultiplexedServerHook(app.Log, ":"+serverCfg.SinglePort.Port, app.GRPCServer, httpServer))
+		return nil
 	}
-	return nil
-}
-
-// startServers starts the enabled servers in goroutines.
-func (app *Application) startServers(ctx context.Context) {
-	if app.Config.Server.HTTP.Enable {
-		httpLis, err := net.Listen("tcp", ":"+app.Config.Server.HTTP.Port)
-		if err != nil {
-			app.Log.Errorf("failed to listen on HTTP port %s: %v", app.Config.Server.HTTP.Port, err)
-		} else {
-			go func() {
-				if err := app.HTTPServer.Serve(httpLis); err != nil && err != http.ErrServerClosed {
-					app.Log.Errorf("failed to start HTTP server: %v", err)
-				}
-			}()
-		}
+	if app.HTTPServer != nil {
+		app.Lifecycle.Append(service.HTTPServerHook(app.Log, "HTTP server", app.HTTPServer))
 	}
-
-	if app.Config.Server.GRPC.Enable {
-		grpcLis, err := net.Listen("tcp", ":"+app.Config.Server.GRPC.Port)
-		if err != nil {
-			app.Log.Errorf("failed to listen on gRPC port %s: %v", app.Config.Server.GRPC.Port, err)
-		} else {
-			go func() {
-				if err := app.GRPCServer.Serve(grpcLis); err != nil && err != grpc.ErrServerStopped {
-					app.Log.Errorf("failed to start gRPC server: %v", err)
-				}
-			}()
-		}
+	if app.GRPCServer != nil {
+		app.Lifecycle.Append(service.GRPCServerHook(app.Log, app.GRPCServer, ":"+serverCfg.GRPC.Port))
 	}
-
-	if app.Config.Server.GraphQL.Enable {
-		graphqlLis, err := net.Listen("tcp", ":"+app.Config.Server.GraphQL.Port)
-		if err != nil {
-			app.Log.Errorf("failed to listen on GraphQL port %s: %v", app.Config.Server.GraphQL.Port, err)
-		} else {
-			go func() {
-				if err := app.GraphQLServer.Serve(graphqlLis); err != nil && err != http.ErrServerClosed {
-					app.Log.Errorf("failed to start GraphQL server: %v", err)
-				}
-			}()
-		}
+	if app.GraphQLServer != nil {
+		app.Lifecycle.Append(service.HTTPServerHook(app.Log, "GraphQL server", app.GraphQLServer))
 	}
+	return nil
 }
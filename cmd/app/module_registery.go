@@ -1,29 +1,27 @@
 package app
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
-	userConfig "github.com/azahir21/go-backend-boilerplate/internal/user/config"
-)
 
-// registerModules registers all application modules and returns their handlers.
-// Add new modules here when extending the application.
-func registerModules(deps *module.Dependencies) ([]module.HTTPModule, []module.GRPCModule, []module.GraphQLModule) {
-	var httpModules []module.HTTPModule
-	var grpcModules []module.GRPCModule
-	var graphqlModules []module.GraphQLModule
+	// Modules register themselves with module.Register when their config package is
+	// imported. Import new modules here.
+	_ "github.com/azahir21/go-backend-boilerplate/internal/user/config"
+)
 
-	// User module - only register if SQL database is enabled
-	if deps.DBClient != nil {
-		httpModules = append(httpModules, userConfig.NewHTTPConfig(deps))
-		grpcModules = append(grpcModules, userConfig.NewGRPCConfig(deps))
-		graphqlModules = append(graphqlModules, userConfig.NewGraphQLConfig(deps))
+// registerModules builds the modules enabled under modules in the configuration.
+func (app *Application) registerModules() error {
+	modules, err := module.Build(app.Config.Modules, app.Dependencies)
+	if err != nil {
+		return fmt.Errorf("failed to register modules: %w", err)
 	}
-
-	// Add more modules here as needed:
-	// productModule := productConfig.NewProductConfig(deps)
-	// httpModules = append(httpModules, productConfig.NewHTTPConfig(deps))
-	// grpcModules = append(grpcModules, productConfig.NewGRPCConfig(deps))
-	// graphqlModules = append(graphqlModules, productConfig.NewGraphQLConfig(deps))
-
-	return httpModules, grpcModules, graphqlModules
+	app.HTTPModules, app.GRPCModules, app.GraphQLModules = modules.HTTP, modules.GRPC, modules.GraphQL
+	if len(modules.Enabled) == 0 {
+		app.Log.Warn("No modules are enabled")
+	} else {
+		app.Log.Infof("Enabled modules: %s", strings.Join(modules.Enabled, ", "))
+	}
+	return nil
 }
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	sharedGraphQL "github.com/azahir21/go-backend-boilerplate/internal/shared/graphql"
+	sharedHttp "github.com/azahir21/go-backend-boilerplate/internal/shared/http"
+	"github.com/graphql-go/graphql"
+	"google.golang.org/grpc"
+)
+
+// Route is an endpoint registered by a module.
+type Route struct {
+	// Transport is "REST", "gRPC" or "GraphQL".
+	Transport string
+	// Method is the HTTP method, "unary" or "stream" for gRPC methods and "query" or
+	// "mutation" for GraphQL fields.
+	Method string
+	// Path is the REST path, the full gRPC method name or the GraphQL field name.
+	Path string
+}
+
+// Routes lists the REST routes, gRPC methods and GraphQL root fields of all registered
+// modules, regardless of which servers are enabled.
+func (app *Application) Routes() ([]Route, error) {
+	var routes []Route
+
+	versioning, err := sharedHttp.NewVersioning(app.Config.Server.HTTP.Versioning)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure API versioning: %w", err)
+	}
+	var routers []sharedHttp.HttpRouter
+	for _, m := range app.HTTPModules {
+		routers = append(routers, m.HTTPHandler())
+	}
+	for _, r := range sharedHttp.NewServer(versioning, nil, routers...).Routes() {
+		routes = append(routes, Route{Transport: "REST", Method: r.Method, Path: r.Path})
+	}
+
+	grpcServer := grpc.NewServer()
+	for _, m := range app.GRPCModules {
+		m.RegisterGRPC(grpcServer)
+	}
+	for service, info := range grpcServer.GetServiceInfo() {
+		for _, method := range info.Methods {
+			kind := "unary"
+			if method.IsClientStream || method.IsServerStream {
+				kind = "stream"
+			}
+			routes = append(routes, Route{Transport: "gRPC", Method: kind, Path: "/" + service + "/" + method.Name})
+		}
+	}
+
+	var builders []sharedGraphQL.SchemaBuilder
+	for _, m := range app.GraphQLModules {
+		builders = append(builders, m.GraphQLSchemaBuilder())
+	}
+	if len(builders) > 0 {
+		schema, err := sharedGraphQL.NewRootSchema(builders)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GraphQL schema: %w", err)
+		}
+		for kind, root := range map[string]*graphql.Object{"query": schema.QueryType(), "mutation": schema.MutationType()} {
+			if root == nil {
+				continue
+			}
+			for name := range root.Fields() {
+				routes = append(routes, Route{Transport: "GraphQL", Method: kind, Path: name})
+			}
+		}
+	}
+
+	order := map[string]int{"REST": 0, "gRPC": 1, "GraphQL": 2}
+	sort.Slice(routes, func(i, j int) bool {
+		a, b := routes[i], routes[j]
+		if a.Transport != b.Transport {
+			return order[a.Transport] < order[b.Transport]
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return routes, nil
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/db"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/outbox"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
+	userUsecase "github.com/azahir21/go-backend-boilerplate/internal/user/usecase"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newCreateAdminCommand(log *logrus.Logger) *cobra.Command {
+	var username, email, password string
+	cmd := &cobra.Command{
+		Use:   "create-admin",
+		Short: "Create a user with the admin role",
+		Long:  "Create a user with the admin role. Values that are not given as flags are taken from the default_admin configuration.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadConfig(log)
+			if err != nil {
+				return err
+			}
+			if !cfg.DB.Enable {
+				return errors.New("SQL database is disabled in configuration")
+			}
+			if username == "" {
+				username = cfg.Admin.Username
+			}
+			if email == "" {
+				email = cfg.Admin.Email
+			}
+			if password == "" {
+				password = cfg.Admin.Password
+			}
+			if username == "" || email == "" || password == "" {
+				return errors.New("username, email and password are required")
+			}
+
+			client, _, err := db.NewEntClient(log, cfg)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			usecase := userUsecase.NewUserUsecase(unitofwork.NewUnitOfWork(client, log), outbox.NewPublisher(cfg.Outbox.Enable), nil)
+			user, err := usecase.CreateAdmin(context.Background(), username, email, password)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created admin %s (id %d)\n", user.Username, user.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&username, "username", "", "admin username (default default_admin.username)")
+	cmd.Flags().StringVar(&email, "email", "", "admin email (default default_admin.email)")
+	cmd.Flags().StringVar(&password, "password", "", "admin password (default default_admin.password)")
+	return cmd
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newConfigCommand(log *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "print",
+		Short: "Print the effective configuration with secrets redacted",
+		Long:  "Print the configuration after merging the config files and environment variables. Passwords, keys and connection strings are redacted.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadConfig(log)
+			if err != nil {
+				return err
+			}
+			out, err := cfg.Redacted().ToMap()
+			if err != nil {
+				return err
+			}
+			enc := yaml.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent(2)
+			if err := enc.Encode(out); err != nil {
+				return err
+			}
+			return enc.Close()
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/db"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// openDatabase loads the configuration and opens the SQL database. The caller closes it.
+func openDatabase(log *logrus.Logger) (*config.Config, *sql.DB, error) {
+	cfg, err := config.LoadConfig(log)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cfg.DB.Enable {
+		return nil, nil, errors.New("SQL database is disabled in configuration")
+	}
+	sqlDB, err := db.OpenDB(cfg.DB)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, sqlDB, nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/azahir21/go-backend-boilerplate/internal/scaffold"
+	"github.com/spf13/cobra"
+)
+
+func newGenerateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate application code",
+	}
+	cmd.AddCommand(newGenerateModuleCommand())
+	return cmd
+}
+
+func newGenerateModuleCommand() *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:   "module NAME FIELD...",
+		Short: "Scaffold a CRUD module with REST, gRPC and GraphQL delivery",
+		Long: "Generate the ent schema, entity, repository, usecase with its test, REST handler, .proto file, gRPC handler,\n" +
+			"GraphQL schema and module configs of a new module, and register it in the module registry and the unit of work.\n" +
+			"NAME is the snake_case singular entity name. Each FIELD is name:type[:unique][:optional], with type one of\n" +
+			"string, text, int, int64, float, bool and time.",
+		Example: "  go run ./cmd generate module product name:string:unique description:text:optional price:float",
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fields, err := scaffold.ParseFields(args[1:])
+			if err != nil {
+				return err
+			}
+			files, err := scaffold.Generate(dir, scaffold.Module{Name: args[0], Fields: fields})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			for _, f := range files {
+				fmt.Fprintln(out, f)
+			}
+			fmt.Fprintf(out, "\nNext steps:\n"+
+				"  go generate ./ent\n"+
+				"  make proto\n"+
+				"  go run ./cmd migrate create add_%[1]s   # fill it with the statements of: go run ./cmd migrate check\n"+
+				"  go test ./internal/%[2]s/...\n", args[0], scaffold.PackageName(args[0]))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", ".", "root of the repository")
+	return cmd
+}
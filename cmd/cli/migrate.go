@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/db"
+	"github.com/azahir21/go-backend-boilerplate/migrations"
+	"github.com/pressly/goose/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand(log *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply, roll back or inspect the SQL migrations embedded in the binary",
+		Long: "Apply, roll back or inspect the SQL migrations embedded in the binary. The migrations of the configured\n" +
+			"driver (migrations/<driver>) are applied under a database lock, so concurrent runs apply each version once.",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "up",
+		Short: "Apply all pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(log, func(ctx context.Context, migrator *goose.Provider) error {
+				results, err := migrator.Up(ctx)
+				if err != nil {
+					return err
+				}
+				if len(results) == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No pending migrations")
+				}
+				for _, r := range results {
+					fmt.Fprintf(cmd.OutOrStdout(), "Applied %s (%s)\n", r.Source.Path, r.Duration)
+				}
+				return nil
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "down",
+		Short: "Roll back the most recently applied migration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(log, func(ctx context.Context, migrator *goose.Provider) error {
+				result, err := migrator.Down(ctx)
+				if errors.Is(err, goose.ErrNoNextVersion) {
+					fmt.Fprintln(cmd.OutOrStdout(), "No migrations to roll back")
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Rolled back %s (%s)\n", result.Source.Path, result.Duration)
+				return nil
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show which migrations are applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(log, func(ctx context.Context, migrator *goose.Provider) error {
+				statuses, err := migrator.Status(ctx)
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tFILE")
+				for _, s := range statuses {
+					appliedAt := "-"
+					if !s.AppliedAt.IsZero() {
+						appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+					}
+					fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Source.Version, s.State, appliedAt, s.Source.Path)
+				}
+				return w.Flush()
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "check",
+		Short: "Fail if the ent schema differs from the migrated database",
+		Long: "Compare the ent schema with the database and print the statements that a missing migration would need.\n" +
+			"Run it after migrate up, e.g. in CI, to catch ent schema changes without a migration.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, sqlDB, err := openDatabase(log)
+			if err != nil {
+				return err
+			}
+			defer sqlDB.Close()
+
+			statements, err := db.SchemaDrift(context.Background(), sqlDB, cfg.DB.Driver)
+			if err != nil {
+				return err
+			}
+			if len(statements) > 0 {
+				for _, s := range statements {
+					fmt.Fprintln(cmd.OutOrStdout(), s+";")
+				}
+				return fmt.Errorf("ent schema differs from the database: %d statements missing from the migrations", len(statements))
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Database schema matches the ent schema")
+			return nil
+		},
+	})
+
+	var dir string
+	create := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a new SQL migration for every dialect",
+		Long: "Create <timestamp>_NAME.sql in the postgres, mysql, mariadb and sqlite3 migration directories.\n" +
+			"Each file must be written in its dialect's SQL and produce the schema that ent expects.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := fmt.Sprintf("%s_%s.sql", time.Now().UTC().Format("20060102150405"), args[0])
+			for _, dialect := range migrations.Dialects {
+				path := filepath.Join(dir, dialect, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					return err
+				}
+				if err := os.WriteFile(path, []byte(migrationTemplate), 0o644); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", path)
+			}
+			return nil
+		},
+	}
+	create.Flags().StringVar(&dir, "dir", "migrations", "directory of the per-dialect migration directories")
+	cmd.AddCommand(create)
+
+	return cmd
+}
+
+const migrationTemplate = `-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+`
+
+// withMigrator opens the database and runs fn with a migrator for the embedded migrations.
+func withMigrator(log *logrus.Logger, fn func(ctx context.Context, migrator *goose.Provider) error) error {
+	cfg, sqlDB, err := openDatabase(log)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	migrator, err := db.NewMigrator(sqlDB, cfg.DB)
+	if err != nil {
+		return err
+	}
+	return fn(context.Background(), migrator)
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/db"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/outbox"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newOutboxCommand(log *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "outbox",
+		Short: "Inspect and requeue dead-lettered outbox events",
+	}
+	cmd.AddCommand(newOutboxDeadLettersCommand(log), newOutboxRequeueCommand(log))
+	return cmd
+}
+
+func newOutboxDeadLettersCommand(log *logrus.Logger) *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "dead-letters",
+		Short: "List events whose delivery failed too many times",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := openEntClient(log)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			events, err := outbox.DeadLetters(context.Background(), client, limit)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tTYPE\tAGGREGATE\tATTEMPTS\tCREATED\tLAST ERROR")
+			for _, e := range events {
+				fmt.Fprintf(w, "%d\t%s\t%s/%s\t%d\t%s\t%s\n", e.ID, e.EventType, e.AggregateType, e.AggregateID,
+					e.Attempts, e.CreatedAt.Format(time.RFC3339), e.LastError)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 100, "maximum number of events to list")
+	return cmd
+}
+
+func newOutboxRequeueCommand(log *logrus.Logger) *cobra.Command {
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "requeue [ID...]",
+		Short: "Deliver dead-lettered events again",
+		Long:  "Move the given dead-lettered events, or all of them with --all, back to pending with a fresh number of delivery attempts.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !all {
+				return errors.New("give the IDs of the events to requeue, or --all")
+			}
+			ids := make([]int, len(args))
+			for i, arg := range args {
+				id, err := strconv.Atoi(arg)
+				if err != nil {
+					return fmt.Errorf("invalid event ID %q", arg)
+				}
+				ids[i] = id
+			}
+
+			client, err := openEntClient(log)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			count, err := outbox.Requeue(context.Background(), client, ids...)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Requeued %d events\n", count)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "requeue every dead-lettered event")
+	return cmd
+}
+
+// openEntClient loads the configuration and opens the ent client. The caller closes it.
+func openEntClient(log *logrus.Logger) (*ent.Client, error) {
+	cfg, err := config.LoadConfig(log)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.DB.Enable {
+		return nil, errors.New("SQL database is disabled in configuration")
+	}
+	client, _, err := db.NewEntClient(log, cfg)
+	return client, err
+}
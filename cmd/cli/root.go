@@ -0,0 +1,46 @@
+// Package cli implements the command-line interface: serving the application, running the
+// job worker and the operational commands for migrations, seed data, users, routes and
+// configuration.
+package cli
+
+import (
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Execute runs the command selected by the command-line arguments.
+func Execute(log *logrus.Logger) error {
+	return newRootCommand(log).Execute()
+}
+
+func newRootCommand(log *logrus.Logger) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "go-backend-boilerplate",
+		Short:         "Go backend boilerplate service",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// Operational commands print their results on stdout, so logs go to stderr
+			if cmd.Name() != "serve" && cmd.Name() != "worker" {
+				log.SetOutput(os.Stderr)
+				gin.DefaultWriter = os.Stderr
+			}
+		},
+	}
+	root.AddCommand(
+		newServeCommand(log),
+		newWorkerCommand(log),
+		newMigrateCommand(log),
+		newSeedCommand(log),
+		newCreateAdminCommand(log),
+		newOutboxCommand(log),
+		newScheduleCommand(log),
+		newRoutesCommand(log),
+		newConfigCommand(log),
+		newGenerateCommand(),
+	)
+	return root
+}
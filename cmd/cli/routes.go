@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/azahir21/go-backend-boilerplate/cmd/app"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newRoutesCommand(log *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "routes",
+		Short: "Print every REST route, gRPC method and GraphQL field of the registered modules",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			application, err := app.New(log)
+			if err != nil {
+				return err
+			}
+			defer application.Lifecycle.Stop(context.Background())
+
+			routes, err := application.Routes()
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "TRANSPORT\tMETHOD\tPATH")
+			for _, r := range routes {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", r.Transport, r.Method, r.Path)
+			}
+			return w.Flush()
+		},
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/cmd/app"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newScheduleCommand(log *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "List, inspect and run the scheduled tasks of the registered modules",
+	}
+	cmd.AddCommand(newScheduleListCommand(log), newScheduleHistoryCommand(log), newScheduleRunCommand(log))
+	return cmd
+}
+
+// newSchedulerApp builds the application to reach its scheduler. The caller stops its
+// lifecycle.
+func newSchedulerApp(log *logrus.Logger) (*app.Application, error) {
+	application, err := app.New(log)
+	if err != nil {
+		return nil, err
+	}
+	if application.Scheduler == nil {
+		application.Lifecycle.Stop(context.Background())
+		return nil, errors.New("the scheduler is disabled in configuration (scheduler.enable, database.enable)")
+	}
+	return application, nil
+}
+
+func newScheduleListCommand(log *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the scheduled tasks with their next and last runs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			application, err := newSchedulerApp(log)
+			if err != nil {
+				return err
+			}
+			defer application.Lifecycle.Stop(context.Background())
+
+			s := application.Scheduler
+			now := time.Now().In(s.Location())
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "TASK\tSCHEDULE\tNEXT RUN\tLAST RUN\tSTATUS")
+			for _, t := range s.Tasks() {
+				last, status := "-", "-"
+				runs, err := s.History(context.Background(), t.Name, 1)
+				if err != nil {
+					return err
+				}
+				if len(runs) > 0 {
+					last, status = runs[0].StartedAt.In(s.Location()).Format(time.RFC3339), runs[0].Status
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Name, t.Spec, t.Next(now).Format(time.RFC3339), last, status)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newScheduleHistoryCommand(log *logrus.Logger) *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "history [TASK]",
+		Short: "List the latest runs, of one task or of all",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			application, err := newSchedulerApp(log)
+			if err != nil {
+				return err
+			}
+			defer application.Lifecycle.Stop(context.Background())
+
+			var task string
+			if len(args) == 1 {
+				task = args[0]
+			}
+			s := application.Scheduler
+			runs, err := s.History(context.Background(), task, limit)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tTASK\tTRIGGER\tSTARTED\tDURATION\tSTATUS\tINSTANCE\tERROR")
+			for _, r := range runs {
+				duration := "-"
+				if r.FinishedAt != nil {
+					duration = r.FinishedAt.Sub(r.StartedAt).Round(time.Millisecond).String()
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.ID, r.Task, r.Trigger,
+					r.StartedAt.In(s.Location()).Format(time.RFC3339), duration, r.Status, r.Instance, r.Error)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "maximum number of runs to list")
+	return cmd
+}
+
+func newScheduleRunCommand(log *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "run TASK",
+		Short: "Run a scheduled task now",
+		Long:  "Run a scheduled task now in this process and record it as a manual run. It fails if the task is running on a replica.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			application, err := newSchedulerApp(log)
+			if err != nil {
+				return err
+			}
+			defer application.Lifecycle.Stop(context.Background())
+
+			start := time.Now()
+			if err := application.Scheduler.Run(context.Background(), args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Task %s succeeded in %s\n", args[0], time.Since(start).Round(time.Millisecond))
+			return nil
+		},
+	}
+}
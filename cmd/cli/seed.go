@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/db"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newSeedCommand(log *logrus.Logger) *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Load fixtures into the SQL database",
+		Long: "Insert the rows of every *.yaml fixture in the fixtures directory, in file name order, in one transaction.\n" +
+			"A fixture names its table and lists rows keyed by column name.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, sqlDB, err := openDatabase(log)
+			if err != nil {
+				return err
+			}
+			defer sqlDB.Close()
+
+			count, err := db.Seed(context.Background(), sqlDB, cfg.DB.Driver, os.DirFS(dir))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Inserted %d rows from %s\n", count, dir)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "fixtures", "directory of the fixture files")
+	return cmd
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"github.com/azahir21/go-backend-boilerplate/cmd/app"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newServeCommand(log *logrus.Logger) *cobra.Command {
+	var rest, grpc, graphql bool
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the servers",
+		Long: "Start the REST, gRPC and GraphQL servers enabled in the configuration and serve until SIGINT or SIGTERM.\n" +
+			"Without flags all delivery layers are served; the flags restrict serving to the selected ones.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var opts []app.Option
+			if rest {
+				opts = append(opts, app.WithREST())
+			}
+			if grpc {
+				opts = append(opts, app.WithGRPC())
+			}
+			if graphql {
+				opts = append(opts, app.WithGraphQL())
+			}
+			return app.Run(log, opts...)
+		},
+	}
+	cmd.Flags().BoolVar(&rest, "rest", false, "serve the REST API")
+	cmd.Flags().BoolVar(&grpc, "grpc", false, "serve the gRPC API")
+	cmd.Flags().BoolVar(&graphql, "graphql", false, "serve the GraphQL API")
+	return cmd
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"github.com/azahir21/go-backend-boilerplate/cmd/app"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newWorkerCommand(log *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "worker",
+		Short: "Run background jobs",
+		Long: "Run the background jobs of the enabled modules until SIGINT or SIGTERM, without serving the APIs.\n" +
+			"On shutdown the worker stops claiming jobs and waits for the running ones within server.shutdown.timeout.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.Run(log, app.WithWorker())
+		},
+	}
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"github.com/azahir21/go-backend-boilerplate/cmd/app"
+	"github.com/azahir21/go-backend-boilerplate/cmd/cli"
 	"github.com/azahir21/go-backend-boilerplate/pkg/logger"
 )
 
@@ -27,7 +27,7 @@ import (
 
 func main() {
 	log := logger.NewLogger()
-	if err := app.Run(log); err != nil {
-		log.Fatalf("application failed to start: %v", err)
+	if err := cli.Execute(log); err != nil {
+		log.Fatal(err)
 	}
 }
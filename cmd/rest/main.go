@@ -1,7 +1,7 @@
 package main
 
 import (
-	restapp "github.com/azahir21/go-backend-boilerplate/cmd/app/rest"
+	"github.com/azahir21/go-backend-boilerplate/cmd/app"
 	"github.com/azahir21/go-backend-boilerplate/pkg/logger"
 )
 
@@ -27,7 +27,7 @@ import (
 
 func main() {
 	log := logger.NewLogger()
-	if err := restapp.Run(log); err != nil {
+	if err := app.Run(log, app.WithREST()); err != nil {
 		log.Fatalf("REST application failed to start: %v", err)
 	}
 }
@@ -7,25 +7,49 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/ratelimit"
 	sharedGraphQL "github.com/azahir21/go-backend-boilerplate/internal/shared/graphql"
+	sharedHttp "github.com/azahir21/go-backend-boilerplate/internal/shared/http"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/middleware"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
+	"github.com/azahir21/go-backend-boilerplate/pkg/apperr"
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/tlsconfig"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/graphql-go/graphql"
 	"github.com/sirupsen/logrus"
 )
 
-func NewGraphQLServer(log *logrus.Logger, cfg config.GraphQLServerConfig, modules []module.GraphQLModule) (*http.Server, error) {
+func NewGraphQLServer(log *logrus.Logger, cfg config.GraphQLServerConfig, opts Options, modules []module.GraphQLModule) (*http.Server, error) {
+	tlsConfig, err := tlsconfig.New(log, cfg.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure GraphQL TLS: %w", err)
+	}
+
 	// Gin mode is set in cmd/app/app.go based on environment.
-	router := gin.Default()
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	if tlsConfig != nil {
+		router.Use(middleware.ClientIdentityMiddleware())
+	}
+	if opts.Tracing.Enable {
+		router.Use(middleware.TracingMiddleware(opts.Tracing.ServiceName))
+	}
+	router.Use(
+		middleware.AccessLogMiddleware(log, opts.AccessLog),
+		apperr.RecoveryMiddleware(log, apperr.DefaultConfig()),
+	)
+	if opts.Metrics.Enable {
+		router.Use(middleware.MetricsMiddleware("graphql"))
+	}
 
 	// Configure CORS
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.CorsOrigins,
 		AllowMethods:     []string{"POST", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", middleware.RequestIDHeader, "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader, ratelimit.HeaderLimit, ratelimit.HeaderRemaining, ratelimit.HeaderReset, ratelimit.HeaderPolicy, ratelimit.HeaderRetryAfter},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -43,6 +67,16 @@ func NewGraphQLServer(log *logrus.Logger, cfg config.GraphQLServerConfig, module
 		return nil, fmt.Errorf("failed to create GraphQL schema: %w", err)
 	}
 
+	// Rate limiting is applied per root field once the request body is parsed
+	var limiter *middleware.RateLimiter
+	if opts.Limiter != nil {
+		policy, err := ratelimit.NewPolicy(opts.RateLimit.GraphQL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure GraphQL rate limiting: %w", err)
+		}
+		limiter = middleware.NewRateLimiter(log, opts.Limiter, policy, opts.RateLimit.APIKeyHeader)
+	}
+
 	// GraphQL endpoint
 	router.POST("/graphql", func(c *gin.Context) {
 		var r struct {
@@ -55,14 +89,44 @@ func NewGraphQLServer(log *logrus.Logger, cfg config.GraphQLServerConfig, module
 			return
 		}
 
+		operation := r.Operation
+		if operation == "" {
+			operation = "anonymous"
+		}
+		middleware.AddAccessLogField(c, "graphql_operation", operation)
+
+		if limiter != nil {
+			// Every root field counts against its own rule, e.g. "mutation login", so that
+			// renaming the operation or aliasing a field does not escape the limit. A query
+			// that does not parse counts against the default rule.
+			fields, ok := sharedGraphQL.RootFields(r.Query, r.Operation)
+			if !ok {
+				fields = []string{"invalid"}
+			}
+			for _, field := range fields {
+				if !limiter.Check(c, field) {
+					return
+				}
+			}
+		}
+
+		start := time.Now()
+		ctx, span := sharedGraphQL.StartOperation(c.Request.Context(), operation)
 		result := graphql.Do(graphql.Params{
 			Schema:         schema,
 			RequestString:  r.Query,
 			VariableValues: r.Variables,
 			OperationName:  r.Operation,
-			Context:        c.Request.Context(),
+			Context:        ctx,
 		})
+		sharedGraphQL.EndOperation(span, result)
 
+		if opts.Metrics.Enable {
+			sharedGraphQL.ObserveOperation(operation, start, result.HasErrors())
+		}
+		if result.HasErrors() {
+			middleware.AddAccessLogField(c, "graphql_errors", len(result.Errors))
+		}
 		c.JSON(http.StatusOK, result)
 	})
 
@@ -104,12 +168,19 @@ func NewGraphQLServer(log *logrus.Logger, cfg config.GraphQLServerConfig, module
 		return nil, err
 	}
 
+	// Metrics are served in front of gin so that the middlewares do not apply
+	var handler http.Handler = router
+	if opts.Metrics.Enable && opts.Metrics.Port == "" {
+		handler = sharedHttp.MetricsHandler(opts.Metrics.Path, handler)
+	}
+
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
-		Handler:      router,
+		Handler:      handler,
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 		IdleTimeout:  idleTimeout,
+		TLSConfig:    tlsConfig,
 	}
 
 	if cfg.StartupBanner {
@@ -1,17 +1,22 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/ratelimit"
+	sharedGrpc "github.com/azahir21/go-backend-boilerplate/internal/shared/grpc"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/tlsconfig"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 )
 
-func NewGrpcServer(log *logrus.Logger, cfg config.GRPCServerConfig, modules []module.GRPCModule) (*grpc.Server, error) {
+func NewGrpcServer(log *logrus.Logger, cfg config.GRPCServerConfig, opts Options, modules []module.GRPCModule) (*grpc.Server, error) {
 	maxConnectionIdle, err := time.ParseDuration(cfg.MaxConnectionIdle)
 	if err != nil {
 		return nil, fmt.Errorf("invalid max connection idle duration: %w", err)
@@ -37,7 +42,38 @@ func NewGrpcServer(log *logrus.Logger, cfg config.GRPCServerConfig, modules []mo
 		return nil, fmt.Errorf("invalid max connection age grace duration: %w", err)
 	}
 
-	grpcServer := grpc.NewServer(
+	if cfg.ForceTransportSecurity && !cfg.TLS.Enable {
+		return nil, errors.New("gRPC force_transport_security is set but TLS is not enabled")
+	}
+	tlsConfig, err := tlsconfig.New(log, cfg.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure gRPC TLS: %w", err)
+	}
+
+	unaryInterceptors := []grpc.UnaryServerInterceptor{sharedGrpc.AccessLogUnaryInterceptor(log, opts.AccessLog)}
+	streamInterceptors := []grpc.StreamServerInterceptor{sharedGrpc.AccessLogStreamInterceptor(log, opts.AccessLog)}
+
+	if tlsConfig != nil {
+		unaryInterceptors = append(unaryInterceptors, sharedGrpc.ClientIdentityUnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, sharedGrpc.ClientIdentityStreamInterceptor())
+	}
+
+	if opts.Metrics.Enable {
+		unaryInterceptors = append(unaryInterceptors, sharedGrpc.MetricsUnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, sharedGrpc.MetricsStreamInterceptor())
+	}
+
+	if opts.Limiter != nil {
+		policy, err := ratelimit.NewPolicy(opts.RateLimit.GRPC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure gRPC rate limiting: %w", err)
+		}
+		limiter := sharedGrpc.NewRateLimiter(log, opts.Limiter, policy, opts.RateLimit.APIKeyHeader)
+		unaryInterceptors = append(unaryInterceptors, limiter.UnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, limiter.StreamInterceptor())
+	}
+
+	serverOptions := []grpc.ServerOption{
 		grpc.KeepaliveParams(keepalive.ServerParameters{
 			MaxConnectionIdle:     maxConnectionIdle,
 			Time:                  timeDuration,
@@ -45,7 +81,16 @@ func NewGrpcServer(log *logrus.Logger, cfg config.GRPCServerConfig, modules []mo
 			MaxConnectionAge:      maxConnectionAge,
 			MaxConnectionAgeGrace: maxConnectionAgeGrace,
 		}),
-	)
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+	if tlsConfig != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if opts.Tracing.Enable {
+		serverOptions = append(serverOptions, grpc.StatsHandler(sharedGrpc.TracingStatsHandler()))
+	}
+	grpcServer := grpc.NewServer(serverOptions...)
 
 	// Register gRPC services from all modules
 	for _, m := range modules {
@@ -53,6 +98,11 @@ func NewGrpcServer(log *logrus.Logger, cfg config.GRPCServerConfig, modules []mo
 		m.RegisterGRPC(grpcServer)
 	}
 
+	// grpc.health.v1, after the module services so that they can be checked by name
+	if opts.Health != nil {
+		sharedGrpc.RegisterHealthServer(grpcServer, opts.Health)
+	}
+
 	if cfg.Enable {
 		log.Infof("🚀 Starting gRPC server on :%s", cfg.Port)
 	}
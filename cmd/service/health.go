@@ -0,0 +1,44 @@
+package service
+
+import (
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/db"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/health"
+)
+
+// NewHealthRegistry registers a health checker for every enabled infrastructure component.
+// The SQL database, MongoDB and cache are critical for readiness; storage and email are not.
+// It returns nil when health checks are disabled.
+func NewHealthRegistry(cfg config.HealthConfig, deps *module.Dependencies) (*health.Registry, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	timeout, err := parseDuration(cfg.Timeout, "health timeout")
+	if err != nil {
+		return nil, err
+	}
+	cacheTTL, err := parseDuration(cfg.CacheTTL, "health cache ttl")
+	if err != nil {
+		return nil, err
+	}
+
+	registry := health.NewRegistry(timeout, cacheTTL)
+	if deps.DBClient != nil {
+		registry.Register("database", db.NewHealthChecker(deps.DBClient), true)
+	}
+	if deps.MongoClient != nil {
+		registry.Register("mongo", deps.MongoClient, true)
+	}
+	// Components without a HealthCheck method (the in-memory cache, SendGrid) are not checked
+	if checker, ok := deps.Cache.(health.Checker); ok {
+		registry.Register("cache", checker, true)
+	}
+	if checker, ok := deps.Storage.(health.Checker); ok {
+		registry.Register("storage", checker, false)
+	}
+	if checker, ok := deps.EmailClient.(health.Checker); ok {
+		registry.Register("email", checker, false)
+	}
+	return registry, nil
+}
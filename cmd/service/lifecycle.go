@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/health"
+	"github.com/azahir21/go-backend-boilerplate/pkg/lifecycle"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// HTTPServerHook returns a hook that listens on srv.Addr and serves in the background, over TLS
+// when srv.TLSConfig is set, and shuts the server down on stop. Connections still open at the
+// deadline are closed.
+func HTTPServerHook(log *logrus.Logger, name string, srv *http.Server) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			lis, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", srv.Addr, err)
+			}
+			go func() {
+				if err := serveHTTP(srv, lis); err != nil && err != http.ErrServerClosed {
+					log.Errorf("%s failed: %v", name, err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if err := srv.Shutdown(ctx); err != nil {
+				srv.Close()
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// serveHTTP serves srv on lis, over TLS when srv.TLSConfig is set. The certificate comes from
+// the TLS configuration.
+func serveHTTP(srv *http.Server, lis net.Listener) error {
+	if srv.TLSConfig != nil {
+		return srv.ServeTLS(lis, "", "")
+	}
+	return srv.Serve(lis)
+}
+
+// GRPCServerHook returns a hook that listens on addr and serves in the background. On stop
+// the server stops gracefully, or forcibly once ctx is done.
+func GRPCServerHook(log *logrus.Logger, srv *grpc.Server, addr string) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: "gRPC server",
+		OnStart: func(context.Context) error {
+			lis, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", addr, err)
+			}
+			go func() {
+				if err := srv.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+					log.Errorf("gRPC server failed: %v", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+			go func() {
+				srv.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+				return nil
+			case <-ctx.Done():
+				srv.Stop()
+				<-stopped
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// Run starts the registered components and blocks until SIGINT or SIGTERM. It then marks
+// the service as not ready (when health checks are enabled), keeps serving for the drain
+// period and stops all components within the shutdown timeout: servers first, then the
+// infrastructure in reverse order of creation. A second signal skips the drain period.
+func Run(log *logrus.Logger, cfg config.ShutdownConfig, lc *lifecycle.Manager, registry *health.Registry) error {
+	drainPeriod, err := parseDuration(cfg.DrainPeriod, "shutdown drain period")
+	if err != nil {
+		lc.Stop(context.Background())
+		return err
+	}
+	timeout, err := parseDuration(cfg.Timeout, "shutdown timeout")
+	if err != nil {
+		lc.Stop(context.Background())
+		return err
+	}
+
+	// A failed start stops the components that were already running
+	if err := lc.Start(context.Background()); err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	sig := <-signals
+
+	log.Infof("Received %s, shutting down...", sig)
+	if registry != nil && drainPeriod > 0 {
+		registry.Drain()
+		log.Infof("Draining for %s", drainPeriod)
+		select {
+		case <-time.After(drainPeriod):
+		case <-signals:
+			log.Info("Received second signal, skipping drain")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := lc.Stop(ctx); err != nil {
+		return fmt.Errorf("shutdown incomplete: %w", err)
+	}
+	log.Info("Shutdown complete")
+	return nil
+}
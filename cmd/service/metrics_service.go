@@ -0,0 +1,21 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+// NewMetricsServer creates the admin server exposing Prometheus metrics on its own port.
+// It returns nil when metrics are disabled or served on the REST and GraphQL servers.
+func NewMetricsServer(log *logrus.Logger, cfg config.MetricsConfig) *http.Server {
+	if !cfg.Enable || cfg.Port == "" {
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, promhttp.Handler())
+	log.Infof("📈 Serving metrics on :%s%s", cfg.Port, cfg.Path)
+	return &http.Server{Addr: ":" + cfg.Port, Handler: mux}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/lifecycle"
+	"github.com/sirupsen/logrus"
+	"github.com/soheilhy/cmux"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+// graphqlPath is the prefix of the GraphQL endpoint and playground.
+const graphqlPath = "/graphql"
+
+// NewMultiplexedHTTPServer combines the REST and GraphQL servers into one server that routes
+// the /graphql paths to GraphQL and everything else to REST. Either server may be nil; it
+// returns nil when both are. The settings of the REST server apply.
+//
+// Without TLS the server accepts HTTP/1.1 and cleartext HTTP/2 (h2c), and gRPC connections are
+// split off by MultiplexedServerHook. Over TLS the connection cannot be inspected before the
+// handshake, so gRPC requests are instead passed to grpcServer by the handler.
+func NewMultiplexedHTTPServer(restServer, graphqlServer *http.Server, grpcServer *grpc.Server) *http.Server {
+	base := restServer
+	if base == nil {
+		base = graphqlServer
+	}
+	if base == nil {
+		return nil
+	}
+
+	handler := base.Handler
+	if restServer != nil && graphqlServer != nil {
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == graphqlPath || strings.HasPrefix(r.URL.Path, graphqlPath+"/") {
+				graphqlServer.Handler.ServeHTTP(w, r)
+				return
+			}
+			restServer.Handler.ServeHTTP(w, r)
+		})
+	}
+
+	if base.TLSConfig == nil {
+		handler = h2c.NewHandler(handler, &http2.Server{IdleTimeout: base.IdleTimeout})
+	} else if grpcServer != nil {
+		httpHandler := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+				grpcServer.ServeHTTP(w, r)
+				return
+			}
+			httpHandler.ServeHTTP(w, r)
+		})
+	}
+
+	return &http.Server{
+		Addr:         base.Addr,
+		Handler:      handler,
+		ReadTimeout:  base.ReadTimeout,
+		WriteTimeout: base.WriteTimeout,
+		IdleTimeout:  base.IdleTimeout,
+		TLSConfig:    base.TLSConfig,
+	}
+}
+
+// MultiplexedServerHook returns a hook that listens on addr and serves gRPC and HTTP on the same
+// listener. Without TLS, HTTP/2 connections whose first request has an application/grpc content
+// type are served by grpcServer and all other connections by httpServer. Over TLS httpServer
+// serves every connection and passes gRPC requests on (see NewMultiplexedHTTPServer). Either
+// server may be nil.
+func MultiplexedServerHook(log *logrus.Logger, addr string, grpcServer *grpc.Server, httpServer *http.Server) lifecycle.Hook {
+	if httpServer == nil {
+		return GRPCServerHook(log, grpcServer, addr)
+	}
+	if httpServer.TLSConfig != nil || grpcServer == nil {
+		hook := HTTPServerHook(log, "multiplexed server", httpServer)
+		hook.OnStop = func(ctx context.Context) error {
+			return stopMultiplexed(ctx, log, addr, grpcServer, httpServer)
+		}
+		return hook
+	}
+
+	return lifecycle.Hook{
+		Name: "multiplexed server",
+		OnStart: func(context.Context) error {
+			lis, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", addr, err)
+			}
+			mux := cmux.New(lis)
+
+			// gRPC clients wait for the server's SETTINGS frame before sending headers
+			grpcListener := mux.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+			httpListener := mux.Match(cmux.Any())
+			go func() {
+				if err := grpcServer.Serve(grpcListener); err != nil && !isClosed(err) {
+					log.Errorf("gRPC server failed: %v", err)
+				}
+			}()
+			go func() {
+				if err := httpServer.Serve(httpListener); err != nil && !isClosed(err) {
+					log.Errorf("HTTP server failed: %v", err)
+				}
+			}()
+			go func() {
+				if err := mux.Serve(); err != nil && !isClosed(err) {
+					log.Errorf("Multiplexed listener failed: %v", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return stopMultiplexed(ctx, log, addr, grpcServer, httpServer)
+		},
+	}
+}
+
+// stopMultiplexed stops both servers concurrently; closing either's listener closes the shared one.
+func stopMultiplexed(ctx context.Context, log *logrus.Logger, addr string, grpcServer *grpc.Server, httpServer *http.Server) error {
+	var errs []error
+	grpcStopped := make(chan error, 1)
+	if grpcServer != nil {
+		go func() { grpcStopped <- GRPCServerHook(log, grpcServer, addr).OnStop(ctx) }()
+	} else {
+		grpcStopped <- nil
+	}
+	if err := HTTPServerHook(log, "HTTP server", httpServer).OnStop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := <-grpcStopped; err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// isClosed reports whether err is returned by a server or listener that was closed on shutdown.
+func isClosed(err error) bool {
+	return errors.Is(err, http.ErrServerClosed) ||
+		errors.Is(err, grpc.ErrServerStopped) ||
+		errors.Is(err, cmux.ErrListenerClosed) ||
+		errors.Is(err, cmux.ErrServerClosed) ||
+		errors.Is(err, net.ErrClosed)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/idempotency"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/ratelimit"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/health"
+	"github.com/sirupsen/logrus"
+)
+
+// Options holds cross-cutting settings shared by the REST, gRPC and GraphQL servers.
+type Options struct {
+	AccessLog config.AccessLogConfig
+	Metrics   config.MetricsConfig
+	Tracing   config.TracingConfig
+	RateLimit config.RateLimitConfig
+	// Limiter is nil when rate limiting is disabled.
+	Limiter ratelimit.Limiter
+	// IdempotencyStore is nil when Idempotency-Key handling is disabled.
+	IdempotencyStore idempotency.Store
+	// ResponseCache is nil when the server-side response cache is disabled or no cache is configured.
+	ResponseCache cache.Cache
+	// Health is nil when health checks are disabled (see NewHealthRegistry).
+	Health *health.Registry
+}
+
+// NewOptions builds the shared server options from the application configuration.
+// The rate limiter, idempotency store and response cache use the application cache (redis or in-memory) when enabled.
+func NewOptions(log *logrus.Logger, cfg *config.Config, appCache cache.Cache) Options {
+	opts := Options{
+		AccessLog: cfg.Server.AccessLog,
+		Metrics:   cfg.Server.Metrics,
+		Tracing:   cfg.Server.Tracing,
+		RateLimit: cfg.RateLimit,
+	}
+	if cfg.RateLimit.Enable {
+		opts.Limiter = ratelimit.NewLimiter(log, appCache)
+	} else {
+		log.Info("Rate limiting is disabled, skipping initialization")
+	}
+	if cfg.Server.HTTP.Idempotency.Enable {
+		opts.IdempotencyStore = idempotency.NewStore(log, appCache)
+	}
+	if cfg.Server.HTTP.Caching.ResponseCache {
+		if appCache != nil {
+			opts.ResponseCache = appCache
+		} else {
+			log.Warn("HTTP response cache requires the cache subsystem, skipping initialization")
+		}
+	}
+	return opts
+}
@@ -1,21 +1,25 @@
 package service
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/ratelimit"
 	sharedHttp "github.com/azahir21/go-backend-boilerplate/internal/shared/http"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/middleware"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
+	"github.com/azahir21/go-backend-boilerplate/pkg/apperr"
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/tlsconfig"
 
 	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
-
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewRestServer(log *logrus.Logger, cfg config.HTTPServerConfig, modules []module.HTTPModule) (*http.Server, error) {
+func NewRestServer(log *logrus.Logger, cfg config.HTTPServerConfig, opts Options, modules []module.HTTPModule) (*http.Server, error) {
 	// Collect HTTP handlers from all modules
 	var httpRouters []sharedHttp.HttpRouter
 	for _, m := range modules {
@@ -23,21 +27,92 @@ func NewRestServer(log *logrus.Logger, cfg config.HTTPServerConfig, modules []mo
 		httpRouters = append(httpRouters, m.HTTPHandler())
 	}
 
-	// Initialize Gin server and register routes
-	router := sharedHttp.NewServer(httpRouters...)
+	tlsConfig, err := tlsconfig.New(log, cfg.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP TLS: %w", err)
+	}
+
+	// Global middlewares, applied to every module route. The tracing span wraps the access log
+	// and recovery middlewares so that their entries carry the trace ID.
+	middlewares := []gin.HandlerFunc{middleware.RequestIDMiddleware()}
+	if tlsConfig != nil {
+		middlewares = append(middlewares, middleware.ClientIdentityMiddleware())
+	}
+	if opts.Tracing.Enable {
+		middlewares = append(middlewares, middleware.TracingMiddleware(opts.Tracing.ServiceName))
+	}
+	middlewares = append(middlewares,
+		middleware.AccessLogMiddleware(log, opts.AccessLog),
+		apperr.RecoveryMiddleware(log, apperr.DefaultConfig()),
+		cors.New(cors.Config{
+			AllowOrigins:     cfg.CorsOrigins,
+			AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+			AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", middleware.RequestIDHeader, "traceparent", "tracestate", sharedHttp.IdempotencyKeyHeader, "If-None-Match", "If-Modified-Since", sharedHttp.AcceptVersionHeader},
+			ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader, ratelimit.HeaderLimit, ratelimit.HeaderRemaining, ratelimit.HeaderReset, ratelimit.HeaderPolicy, ratelimit.HeaderRetryAfter, sharedHttp.IdempotentReplayedHeader, "ETag", "Last-Modified", sharedHttp.CacheStatusHeader, sharedHttp.APIVersionHeader, sharedHttp.DeprecationHeader, sharedHttp.SunsetHeader, "Link"},
+			AllowCredentials: true,
+			MaxAge:           12 * time.Hour,
+		}),
+	)
+
+	// Request count and latency per route
+	if opts.Metrics.Enable {
+		middlewares = append(middlewares, middleware.MetricsMiddleware("rest"))
+	}
+
+	// Response compression (before the middlewares that buffer or record uncompressed bodies)
+	if cfg.Compression.Enable {
+		middlewares = append(middlewares, middleware.CompressionMiddleware(cfg.Compression))
+	}
+
+	// Rate limiting (after CORS so that rejected responses still carry CORS headers)
+	if opts.Limiter != nil {
+		policy, err := ratelimit.NewPolicy(opts.RateLimit.HTTP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure HTTP rate limiting: %w", err)
+		}
+		limiter := middleware.NewRateLimiter(log, opts.Limiter, policy, opts.RateLimit.APIKeyHeader)
+		middlewares = append(middlewares, limiter.Middleware())
+	}
 
-	// Configure CORS
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     cfg.CorsOrigins,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
+	// Idempotency-Key support for endpoints that opt in
+	if opts.IdempotencyStore != nil {
+		ttl, err := parseDuration(cfg.Idempotency.TTL, "idempotency ttl")
+		if err != nil {
+			return nil, err
+		}
+		lockTimeout, err := parseDuration(cfg.Idempotency.LockTimeout, "idempotency lock timeout")
+		if err != nil {
+			return nil, err
+		}
+		idem := sharedHttp.NewIdempotency(log, opts.IdempotencyStore, ttl, lockTimeout)
+		middlewares = append(middlewares, idem.Middleware())
+	}
+
+	// Conditional GET (ETag / Last-Modified), wrapping the response cache so that hits can be answered with 304
+	if cfg.Caching.ETag {
+		middlewares = append(middlewares, sharedHttp.ConditionalMiddleware(cfg.Caching.WeakETag))
+	}
 
-	// Swagger endpoint
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("http://localhost:"+cfg.Port+"/swagger/doc.json")))
+	// Server-side response cache for endpoints that opt in
+	if opts.ResponseCache != nil {
+		ttl, err := parseDuration(cfg.Caching.DefaultTTL, "response cache default ttl")
+		if err != nil {
+			return nil, err
+		}
+		responseCache := sharedHttp.NewResponseCache(log, opts.ResponseCache, cache.NewTagStore(opts.ResponseCache), ttl)
+		middlewares = append(middlewares, responseCache.Middleware())
+	}
+
+	versioning, err := sharedHttp.NewVersioning(cfg.Versioning)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure API versioning: %w", err)
+	}
+
+	// Initialize Gin server and register routes
+	router := sharedHttp.NewServer(versioning, middlewares, httpRouters...)
+
+	// Swagger endpoint, for the whole API and per API version
+	router.GET("/swagger/*any", versioning.SwaggerHandler("http://localhost:"+cfg.Port+"/swagger"))
 
 	readTimeout, err := parseDuration(cfg.ReadTimeout, "read timeout")
 	if err != nil {
@@ -54,12 +129,22 @@ func NewRestServer(log *logrus.Logger, cfg config.HTTPServerConfig, modules []mo
 		return nil, err
 	}
 
+	// Health probes and metrics are served in front of gin so that the global middlewares do not apply
+	handler := versioning.Handler(router)
+	if opts.Health != nil {
+		handler = sharedHttp.HealthHandler(opts.Health, handler)
+	}
+	if opts.Metrics.Enable && opts.Metrics.Port == "" {
+		handler = sharedHttp.MetricsHandler(opts.Metrics.Path, handler)
+	}
+
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
-		Handler:      router,
+		Handler:      handler,
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 		IdleTimeout:  idleTimeout,
+		TLSConfig:    tlsConfig,
 	}
 
 	if cfg.StartupBanner {
@@ -12,6 +12,10 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/azahir21/go-backend-boilerplate/ent/job"
+	"github.com/azahir21/go-backend-boilerplate/ent/locklease"
+	"github.com/azahir21/go-backend-boilerplate/ent/outboxevent"
+	"github.com/azahir21/go-backend-boilerplate/ent/scheduledrun"
 	"github.com/azahir21/go-backend-boilerplate/ent/user"
 )
 
@@ -73,7 +77,11 @@ var (
 func checkColumn(t, c string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			user.Table: user.ValidColumn,
+			job.Table:          job.ValidColumn,
+			locklease.Table:    locklease.ValidColumn,
+			outboxevent.Table:  outboxevent.ValidColumn,
+			scheduledrun.Table: scheduledrun.ValidColumn,
+			user.Table:         user.ValidColumn,
 		})
 	})
 	return columnCheck(t, c)
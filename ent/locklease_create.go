@@ -0,0 +1,221 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/azahir21/go-backend-boilerplate/ent/locklease"
+)
+
+// LockLeaseCreate is the builder for creating a LockLease entity.
+type LockLeaseCreate struct {
+	config
+	mutation *LockLeaseMutation
+	hooks    []Hook
+}
+
+// SetOwner sets the "owner" field.
+func (_c *LockLeaseCreate) SetOwner(v string) *LockLeaseCreate {
+	_c.mutation.SetOwner(v)
+	return _c
+}
+
+// SetFence sets the "fence" field.
+func (_c *LockLeaseCreate) SetFence(v int64) *LockLeaseCreate {
+	_c.mutation.SetFence(v)
+	return _c
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_c *LockLeaseCreate) SetExpiresAt(v time.Time) *LockLeaseCreate {
+	_c.mutation.SetExpiresAt(v)
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *LockLeaseCreate) SetID(v string) *LockLeaseCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// Mutation returns the LockLeaseMutation object of the builder.
+func (_c *LockLeaseCreate) Mutation() *LockLeaseMutation {
+	return _c.mutation
+}
+
+// Save creates the LockLease in the database.
+func (_c *LockLeaseCreate) Save(ctx context.Context) (*LockLease, error) {
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *LockLeaseCreate) SaveX(ctx context.Context) *LockLease {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *LockLeaseCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *LockLeaseCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *LockLeaseCreate) check() error {
+	if _, ok := _c.mutation.Owner(); !ok {
+		return &ValidationError{Name: "owner", err: errors.New(`ent: missing required field "LockLease.owner"`)}
+	}
+	if _, ok := _c.mutation.Fence(); !ok {
+		return &ValidationError{Name: "fence", err: errors.New(`ent: missing required field "LockLease.fence"`)}
+	}
+	if _, ok := _c.mutation.ExpiresAt(); !ok {
+		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "LockLease.expires_at"`)}
+	}
+	return nil
+}
+
+func (_c *LockLeaseCreate) sqlSave(ctx context.Context) (*LockLease, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected LockLease.ID type: %T", _spec.ID.Value)
+		}
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *LockLeaseCreate) createSpec() (*LockLease, *sqlgraph.CreateSpec) {
+	var (
+		_node = &LockLease{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(locklease.Table, sqlgraph.NewFieldSpec(locklease.FieldID, field.TypeString))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.Owner(); ok {
+		_spec.SetField(locklease.FieldOwner, field.TypeString, value)
+		_node.Owner = value
+	}
+	if value, ok := _c.mutation.Fence(); ok {
+		_spec.SetField(locklease.FieldFence, field.TypeInt64, value)
+		_node.Fence = value
+	}
+	if value, ok := _c.mutation.ExpiresAt(); ok {
+		_spec.SetField(locklease.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = value
+	}
+	return _node, _spec
+}
+
+// LockLeaseCreateBulk is the builder for creating many LockLease entities in bulk.
+type LockLeaseCreateBulk struct {
+	config
+	err      error
+	builders []*LockLeaseCreate
+}
+
+// Save creates the LockLease entities in the database.
+func (_c *LockLeaseCreateBulk) Save(ctx context.Context) ([]*LockLease, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*LockLease, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*LockLeaseMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *LockLeaseCreateBulk) SaveX(ctx context.Context) []*LockLease {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *LockLeaseCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *LockLeaseCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,298 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/azahir21/go-backend-boilerplate/ent/locklease"
+	"github.com/azahir21/go-backend-boilerplate/ent/predicate"
+)
+
+// LockLeaseUpdate is the builder for updating LockLease entities.
+type LockLeaseUpdate struct {
+	config
+	hooks    []Hook
+	mutation *LockLeaseMutation
+}
+
+// Where appends a list predicates to the LockLeaseUpdate builder.
+func (_u *LockLeaseUpdate) Where(ps ...predicate.LockLease) *LockLeaseUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetOwner sets the "owner" field.
+func (_u *LockLeaseUpdate) SetOwner(v string) *LockLeaseUpdate {
+	_u.mutation.SetOwner(v)
+	return _u
+}
+
+// SetNillableOwner sets the "owner" field if the given value is not nil.
+func (_u *LockLeaseUpdate) SetNillableOwner(v *string) *LockLeaseUpdate {
+	if v != nil {
+		_u.SetOwner(*v)
+	}
+	return _u
+}
+
+// SetFence sets the "fence" field.
+func (_u *LockLeaseUpdate) SetFence(v int64) *LockLeaseUpdate {
+	_u.mutation.ResetFence()
+	_u.mutation.SetFence(v)
+	return _u
+}
+
+// SetNillableFence sets the "fence" field if the given value is not nil.
+func (_u *LockLeaseUpdate) SetNillableFence(v *int64) *LockLeaseUpdate {
+	if v != nil {
+		_u.SetFence(*v)
+	}
+	return _u
+}
+
+// AddFence adds value to the "fence" field.
+func (_u *LockLeaseUpdate) AddFence(v int64) *LockLeaseUpdate {
+	_u.mutation.AddFence(v)
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *LockLeaseUpdate) SetExpiresAt(v time.Time) *LockLeaseUpdate {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *LockLeaseUpdate) SetNillableExpiresAt(v *time.Time) *LockLeaseUpdate {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// Mutation returns the LockLeaseMutation object of the builder.
+func (_u *LockLeaseUpdate) Mutation() *LockLeaseMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *LockLeaseUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *LockLeaseUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *LockLeaseUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *LockLeaseUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *LockLeaseUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(locklease.Table, locklease.Columns, sqlgraph.NewFieldSpec(locklease.FieldID, field.TypeString))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Owner(); ok {
+		_spec.SetField(locklease.FieldOwner, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Fence(); ok {
+		_spec.SetField(locklease.FieldFence, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedFence(); ok {
+		_spec.AddField(locklease.FieldFence, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(locklease.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{locklease.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// LockLeaseUpdateOne is the builder for updating a single LockLease entity.
+type LockLeaseUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *LockLeaseMutation
+}
+
+// SetOwner sets the "owner" field.
+func (_u *LockLeaseUpdateOne) SetOwner(v string) *LockLeaseUpdateOne {
+	_u.mutation.SetOwner(v)
+	return _u
+}
+
+// SetNillableOwner sets the "owner" field if the given value is not nil.
+func (_u *LockLeaseUpdateOne) SetNillableOwner(v *string) *LockLeaseUpdateOne {
+	if v != nil {
+		_u.SetOwner(*v)
+	}
+	return _u
+}
+
+// SetFence sets the "fence" field.
+func (_u *LockLeaseUpdateOne) SetFence(v int64) *LockLeaseUpdateOne {
+	_u.mutation.ResetFence()
+	_u.mutation.SetFence(v)
+	return _u
+}
+
+// SetNillableFence sets the "fence" field if the given value is not nil.
+func (_u *LockLeaseUpdateOne) SetNillableFence(v *int64) *LockLeaseUpdateOne {
+	if v != nil {
+		_u.SetFence(*v)
+	}
+	return _u
+}
+
+// AddFence adds value to the "fence" field.
+func (_u *LockLeaseUpdateOne) AddFence(v int64) *LockLeaseUpdateOne {
+	_u.mutation.AddFence(v)
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *LockLeaseUpdateOne) SetExpiresAt(v time.Time) *LockLeaseUpdateOne {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *LockLeaseUpdateOne) SetNillableExpiresAt(v *time.Time) *LockLeaseUpdateOne {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// Mutation returns the LockLeaseMutation object of the builder.
+func (_u *LockLeaseUpdateOne) Mutation() *LockLeaseMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the LockLeaseUpdate builder.
+func (_u *LockLeaseUpdateOne) Where(ps ...predicate.LockLease) *LockLeaseUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *LockLeaseUpdateOne) Select(field string, fields ...string) *LockLeaseUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated LockLease entity.
+func (_u *LockLeaseUpdateOne) Save(ctx context.Context) (*LockLease, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *LockLeaseUpdateOne) SaveX(ctx context.Context) *LockLease {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *LockLeaseUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *LockLeaseUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *LockLeaseUpdateOne) sqlSave(ctx context.Context) (_node *LockLease, err error) {
+	_spec := sqlgraph.NewUpdateSpec(locklease.Table, locklease.Columns, sqlgraph.NewFieldSpec(locklease.FieldID, field.TypeString))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "LockLease.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, locklease.FieldID)
+		for _, f := range fields {
+			if !locklease.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != locklease.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Owner(); ok {
+		_spec.SetField(locklease.FieldOwner, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Fence(); ok {
+		_spec.SetField(locklease.FieldFence, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedFence(); ok {
+		_spec.AddField(locklease.FieldFence, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(locklease.FieldExpiresAt, field.TypeTime, value)
+	}
+	_node = &LockLease{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{locklease.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
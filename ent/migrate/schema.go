@@ -8,6 +8,95 @@ import (
 )
 
 var (
+	// JobsColumns holds the columns for the "jobs" table.
+	JobsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "type", Type: field.TypeString},
+		{Name: "payload", Type: field.TypeBytes},
+		{Name: "status", Type: field.TypeString, Default: "pending"},
+		{Name: "attempts", Type: field.TypeInt, Default: 0},
+		{Name: "max_attempts", Type: field.TypeInt},
+		{Name: "last_error", Type: field.TypeString, Nullable: true, Size: 2147483647},
+		{Name: "run_at", Type: field.TypeTime},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// JobsTable holds the schema information for the "jobs" table.
+	JobsTable = &schema.Table{
+		Name:       "jobs",
+		Columns:    JobsColumns,
+		PrimaryKey: []*schema.Column{JobsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "job_type_status_run_at",
+				Unique:  false,
+				Columns: []*schema.Column{JobsColumns[1], JobsColumns[3], JobsColumns[7]},
+			},
+		},
+	}
+	// LockLeasesColumns holds the columns for the "lock_leases" table.
+	LockLeasesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "owner", Type: field.TypeString},
+		{Name: "fence", Type: field.TypeInt64},
+		{Name: "expires_at", Type: field.TypeTime},
+	}
+	// LockLeasesTable holds the schema information for the "lock_leases" table.
+	LockLeasesTable = &schema.Table{
+		Name:       "lock_leases",
+		Columns:    LockLeasesColumns,
+		PrimaryKey: []*schema.Column{LockLeasesColumns[0]},
+	}
+	// OutboxEventsColumns holds the columns for the "outbox_events" table.
+	OutboxEventsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "aggregate_type", Type: field.TypeString},
+		{Name: "aggregate_id", Type: field.TypeString},
+		{Name: "event_type", Type: field.TypeString},
+		{Name: "payload", Type: field.TypeBytes},
+		{Name: "status", Type: field.TypeString, Default: "pending"},
+		{Name: "attempts", Type: field.TypeInt, Default: 0},
+		{Name: "last_error", Type: field.TypeString, Nullable: true, Size: 2147483647},
+		{Name: "available_at", Type: field.TypeTime},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "delivered_at", Type: field.TypeTime, Nullable: true},
+	}
+	// OutboxEventsTable holds the schema information for the "outbox_events" table.
+	OutboxEventsTable = &schema.Table{
+		Name:       "outbox_events",
+		Columns:    OutboxEventsColumns,
+		PrimaryKey: []*schema.Column{OutboxEventsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "outboxevent_status_available_at",
+				Unique:  false,
+				Columns: []*schema.Column{OutboxEventsColumns[5], OutboxEventsColumns[8]},
+			},
+		},
+	}
+	// ScheduledRunsColumns holds the columns for the "scheduled_runs" table.
+	ScheduledRunsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "task", Type: field.TypeString},
+		{Name: "trigger", Type: field.TypeString},
+		{Name: "instance", Type: field.TypeString},
+		{Name: "status", Type: field.TypeString, Default: "running"},
+		{Name: "error", Type: field.TypeString, Nullable: true, Size: 2147483647},
+		{Name: "started_at", Type: field.TypeTime},
+		{Name: "finished_at", Type: field.TypeTime, Nullable: true},
+	}
+	// ScheduledRunsTable holds the schema information for the "scheduled_runs" table.
+	ScheduledRunsTable = &schema.Table{
+		Name:       "scheduled_runs",
+		Columns:    ScheduledRunsColumns,
+		PrimaryKey: []*schema.Column{ScheduledRunsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "scheduledrun_task_started_at",
+				Unique:  false,
+				Columns: []*schema.Column{ScheduledRunsColumns[1], ScheduledRunsColumns[6]},
+			},
+		},
+	}
 	// UsersColumns holds the columns for the "users" table.
 	UsersColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
@@ -28,6 +117,10 @@ var (
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		JobsTable,
+		LockLeasesTable,
+		OutboxEventsTable,
+		ScheduledRunsTable,
 		UsersTable,
 	}
 )
@@ -6,5 +6,17 @@ import (
 	"entgo.io/ent/dialect/sql"
 )
 
+// Job is the predicate function for job builders.
+type Job func(*sql.Selector)
+
+// LockLease is the predicate function for locklease builders.
+type LockLease func(*sql.Selector)
+
+// OutboxEvent is the predicate function for outboxevent builders.
+type OutboxEvent func(*sql.Selector)
+
+// ScheduledRun is the predicate function for scheduledrun builders.
+type ScheduledRun func(*sql.Selector)
+
 // User is the predicate function for user builders.
 type User func(*sql.Selector)
@@ -5,6 +5,9 @@ package ent
 import (
 	"time"
 
+	"github.com/azahir21/go-backend-boilerplate/ent/job"
+	"github.com/azahir21/go-backend-boilerplate/ent/outboxevent"
+	"github.com/azahir21/go-backend-boilerplate/ent/scheduledrun"
 	"github.com/azahir21/go-backend-boilerplate/ent/schema"
 	"github.com/azahir21/go-backend-boilerplate/ent/user"
 )
@@ -13,6 +16,52 @@ import (
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	jobFields := schema.Job{}.Fields()
+	_ = jobFields
+	// jobDescStatus is the schema descriptor for status field.
+	jobDescStatus := jobFields[2].Descriptor()
+	// job.DefaultStatus holds the default value on creation for the status field.
+	job.DefaultStatus = jobDescStatus.Default.(string)
+	// jobDescAttempts is the schema descriptor for attempts field.
+	jobDescAttempts := jobFields[3].Descriptor()
+	// job.DefaultAttempts holds the default value on creation for the attempts field.
+	job.DefaultAttempts = jobDescAttempts.Default.(int)
+	// jobDescRunAt is the schema descriptor for run_at field.
+	jobDescRunAt := jobFields[6].Descriptor()
+	// job.DefaultRunAt holds the default value on creation for the run_at field.
+	job.DefaultRunAt = jobDescRunAt.Default.(func() time.Time)
+	// jobDescCreatedAt is the schema descriptor for created_at field.
+	jobDescCreatedAt := jobFields[7].Descriptor()
+	// job.DefaultCreatedAt holds the default value on creation for the created_at field.
+	job.DefaultCreatedAt = jobDescCreatedAt.Default.(func() time.Time)
+	outboxeventFields := schema.OutboxEvent{}.Fields()
+	_ = outboxeventFields
+	// outboxeventDescStatus is the schema descriptor for status field.
+	outboxeventDescStatus := outboxeventFields[4].Descriptor()
+	// outboxevent.DefaultStatus holds the default value on creation for the status field.
+	outboxevent.DefaultStatus = outboxeventDescStatus.Default.(string)
+	// outboxeventDescAttempts is the schema descriptor for attempts field.
+	outboxeventDescAttempts := outboxeventFields[5].Descriptor()
+	// outboxevent.DefaultAttempts holds the default value on creation for the attempts field.
+	outboxevent.DefaultAttempts = outboxeventDescAttempts.Default.(int)
+	// outboxeventDescAvailableAt is the schema descriptor for available_at field.
+	outboxeventDescAvailableAt := outboxeventFields[7].Descriptor()
+	// outboxevent.DefaultAvailableAt holds the default value on creation for the available_at field.
+	outboxevent.DefaultAvailableAt = outboxeventDescAvailableAt.Default.(func() time.Time)
+	// outboxeventDescCreatedAt is the schema descriptor for created_at field.
+	outboxeventDescCreatedAt := outboxeventFields[8].Descriptor()
+	// outboxevent.DefaultCreatedAt holds the default value on creation for the created_at field.
+	outboxevent.DefaultCreatedAt = outboxeventDescCreatedAt.Default.(func() time.Time)
+	scheduledrunFields := schema.ScheduledRun{}.Fields()
+	_ = scheduledrunFields
+	// scheduledrunDescStatus is the schema descriptor for status field.
+	scheduledrunDescStatus := scheduledrunFields[3].Descriptor()
+	// scheduledrun.DefaultStatus holds the default value on creation for the status field.
+	scheduledrun.DefaultStatus = scheduledrunDescStatus.Default.(string)
+	// scheduledrunDescStartedAt is the schema descriptor for started_at field.
+	scheduledrunDescStartedAt := scheduledrunFields[5].Descriptor()
+	// scheduledrun.DefaultStartedAt holds the default value on creation for the started_at field.
+	scheduledrun.DefaultStartedAt = scheduledrunDescStartedAt.Default.(func() time.Time)
 	userFields := schema.User{}.Fields()
 	_ = userFields
 	// userDescRole is the schema descriptor for role field.
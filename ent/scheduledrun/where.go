@@ -0,0 +1,530 @@
+// Code generated by ent, DO NOT EDIT.
+
+package scheduledrun
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/azahir21/go-backend-boilerplate/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLTE(FieldID, id))
+}
+
+// Task applies equality check predicate on the "task" field. It's identical to TaskEQ.
+func Task(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldTask, v))
+}
+
+// Trigger applies equality check predicate on the "trigger" field. It's identical to TriggerEQ.
+func Trigger(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldTrigger, v))
+}
+
+// Instance applies equality check predicate on the "instance" field. It's identical to InstanceEQ.
+func Instance(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldInstance, v))
+}
+
+// Status applies equality check predicate on the "status" field. It's identical to StatusEQ.
+func Status(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldStatus, v))
+}
+
+// Error applies equality check predicate on the "error" field. It's identical to ErrorEQ.
+func Error(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldError, v))
+}
+
+// StartedAt applies equality check predicate on the "started_at" field. It's identical to StartedAtEQ.
+func StartedAt(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldStartedAt, v))
+}
+
+// FinishedAt applies equality check predicate on the "finished_at" field. It's identical to FinishedAtEQ.
+func FinishedAt(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldFinishedAt, v))
+}
+
+// TaskEQ applies the EQ predicate on the "task" field.
+func TaskEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldTask, v))
+}
+
+// TaskNEQ applies the NEQ predicate on the "task" field.
+func TaskNEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNEQ(FieldTask, v))
+}
+
+// TaskIn applies the In predicate on the "task" field.
+func TaskIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIn(FieldTask, vs...))
+}
+
+// TaskNotIn applies the NotIn predicate on the "task" field.
+func TaskNotIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotIn(FieldTask, vs...))
+}
+
+// TaskGT applies the GT predicate on the "task" field.
+func TaskGT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGT(FieldTask, v))
+}
+
+// TaskGTE applies the GTE predicate on the "task" field.
+func TaskGTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGTE(FieldTask, v))
+}
+
+// TaskLT applies the LT predicate on the "task" field.
+func TaskLT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLT(FieldTask, v))
+}
+
+// TaskLTE applies the LTE predicate on the "task" field.
+func TaskLTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLTE(FieldTask, v))
+}
+
+// TaskContains applies the Contains predicate on the "task" field.
+func TaskContains(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContains(FieldTask, v))
+}
+
+// TaskHasPrefix applies the HasPrefix predicate on the "task" field.
+func TaskHasPrefix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasPrefix(FieldTask, v))
+}
+
+// TaskHasSuffix applies the HasSuffix predicate on the "task" field.
+func TaskHasSuffix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasSuffix(FieldTask, v))
+}
+
+// TaskEqualFold applies the EqualFold predicate on the "task" field.
+func TaskEqualFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEqualFold(FieldTask, v))
+}
+
+// TaskContainsFold applies the ContainsFold predicate on the "task" field.
+func TaskContainsFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContainsFold(FieldTask, v))
+}
+
+// TriggerEQ applies the EQ predicate on the "trigger" field.
+func TriggerEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldTrigger, v))
+}
+
+// TriggerNEQ applies the NEQ predicate on the "trigger" field.
+func TriggerNEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNEQ(FieldTrigger, v))
+}
+
+// TriggerIn applies the In predicate on the "trigger" field.
+func TriggerIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIn(FieldTrigger, vs...))
+}
+
+// TriggerNotIn applies the NotIn predicate on the "trigger" field.
+func TriggerNotIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotIn(FieldTrigger, vs...))
+}
+
+// TriggerGT applies the GT predicate on the "trigger" field.
+func TriggerGT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGT(FieldTrigger, v))
+}
+
+// TriggerGTE applies the GTE predicate on the "trigger" field.
+func TriggerGTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGTE(FieldTrigger, v))
+}
+
+// TriggerLT applies the LT predicate on the "trigger" field.
+func TriggerLT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLT(FieldTrigger, v))
+}
+
+// TriggerLTE applies the LTE predicate on the "trigger" field.
+func TriggerLTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLTE(FieldTrigger, v))
+}
+
+// TriggerContains applies the Contains predicate on the "trigger" field.
+func TriggerContains(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContains(FieldTrigger, v))
+}
+
+// TriggerHasPrefix applies the HasPrefix predicate on the "trigger" field.
+func TriggerHasPrefix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasPrefix(FieldTrigger, v))
+}
+
+// TriggerHasSuffix applies the HasSuffix predicate on the "trigger" field.
+func TriggerHasSuffix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasSuffix(FieldTrigger, v))
+}
+
+// TriggerEqualFold applies the EqualFold predicate on the "trigger" field.
+func TriggerEqualFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEqualFold(FieldTrigger, v))
+}
+
+// TriggerContainsFold applies the ContainsFold predicate on the "trigger" field.
+func TriggerContainsFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContainsFold(FieldTrigger, v))
+}
+
+// InstanceEQ applies the EQ predicate on the "instance" field.
+func InstanceEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldInstance, v))
+}
+
+// InstanceNEQ applies the NEQ predicate on the "instance" field.
+func InstanceNEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNEQ(FieldInstance, v))
+}
+
+// InstanceIn applies the In predicate on the "instance" field.
+func InstanceIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIn(FieldInstance, vs...))
+}
+
+// InstanceNotIn applies the NotIn predicate on the "instance" field.
+func InstanceNotIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotIn(FieldInstance, vs...))
+}
+
+// InstanceGT applies the GT predicate on the "instance" field.
+func InstanceGT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGT(FieldInstance, v))
+}
+
+// InstanceGTE applies the GTE predicate on the "instance" field.
+func InstanceGTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGTE(FieldInstance, v))
+}
+
+// InstanceLT applies the LT predicate on the "instance" field.
+func InstanceLT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLT(FieldInstance, v))
+}
+
+// InstanceLTE applies the LTE predicate on the "instance" field.
+func InstanceLTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLTE(FieldInstance, v))
+}
+
+// InstanceContains applies the Contains predicate on the "instance" field.
+func InstanceContains(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContains(FieldInstance, v))
+}
+
+// InstanceHasPrefix applies the HasPrefix predicate on the "instance" field.
+func InstanceHasPrefix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasPrefix(FieldInstance, v))
+}
+
+// InstanceHasSuffix applies the HasSuffix predicate on the "instance" field.
+func InstanceHasSuffix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasSuffix(FieldInstance, v))
+}
+
+// InstanceEqualFold applies the EqualFold predicate on the "instance" field.
+func InstanceEqualFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEqualFold(FieldInstance, v))
+}
+
+// InstanceContainsFold applies the ContainsFold predicate on the "instance" field.
+func InstanceContainsFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContainsFold(FieldInstance, v))
+}
+
+// StatusEQ applies the EQ predicate on the "status" field.
+func StatusEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldStatus, v))
+}
+
+// StatusNEQ applies the NEQ predicate on the "status" field.
+func StatusNEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNEQ(FieldStatus, v))
+}
+
+// StatusIn applies the In predicate on the "status" field.
+func StatusIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIn(FieldStatus, vs...))
+}
+
+// StatusNotIn applies the NotIn predicate on the "status" field.
+func StatusNotIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotIn(FieldStatus, vs...))
+}
+
+// StatusGT applies the GT predicate on the "status" field.
+func StatusGT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGT(FieldStatus, v))
+}
+
+// StatusGTE applies the GTE predicate on the "status" field.
+func StatusGTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGTE(FieldStatus, v))
+}
+
+// StatusLT applies the LT predicate on the "status" field.
+func StatusLT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLT(FieldStatus, v))
+}
+
+// StatusLTE applies the LTE predicate on the "status" field.
+func StatusLTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLTE(FieldStatus, v))
+}
+
+// StatusContains applies the Contains predicate on the "status" field.
+func StatusContains(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContains(FieldStatus, v))
+}
+
+// StatusHasPrefix applies the HasPrefix predicate on the "status" field.
+func StatusHasPrefix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasPrefix(FieldStatus, v))
+}
+
+// StatusHasSuffix applies the HasSuffix predicate on the "status" field.
+func StatusHasSuffix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasSuffix(FieldStatus, v))
+}
+
+// StatusEqualFold applies the EqualFold predicate on the "status" field.
+func StatusEqualFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEqualFold(FieldStatus, v))
+}
+
+// StatusContainsFold applies the ContainsFold predicate on the "status" field.
+func StatusContainsFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContainsFold(FieldStatus, v))
+}
+
+// ErrorEQ applies the EQ predicate on the "error" field.
+func ErrorEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldError, v))
+}
+
+// ErrorNEQ applies the NEQ predicate on the "error" field.
+func ErrorNEQ(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNEQ(FieldError, v))
+}
+
+// ErrorIn applies the In predicate on the "error" field.
+func ErrorIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIn(FieldError, vs...))
+}
+
+// ErrorNotIn applies the NotIn predicate on the "error" field.
+func ErrorNotIn(vs ...string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotIn(FieldError, vs...))
+}
+
+// ErrorGT applies the GT predicate on the "error" field.
+func ErrorGT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGT(FieldError, v))
+}
+
+// ErrorGTE applies the GTE predicate on the "error" field.
+func ErrorGTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGTE(FieldError, v))
+}
+
+// ErrorLT applies the LT predicate on the "error" field.
+func ErrorLT(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLT(FieldError, v))
+}
+
+// ErrorLTE applies the LTE predicate on the "error" field.
+func ErrorLTE(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLTE(FieldError, v))
+}
+
+// ErrorContains applies the Contains predicate on the "error" field.
+func ErrorContains(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContains(FieldError, v))
+}
+
+// ErrorHasPrefix applies the HasPrefix predicate on the "error" field.
+func ErrorHasPrefix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasPrefix(FieldError, v))
+}
+
+// ErrorHasSuffix applies the HasSuffix predicate on the "error" field.
+func ErrorHasSuffix(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldHasSuffix(FieldError, v))
+}
+
+// ErrorIsNil applies the IsNil predicate on the "error" field.
+func ErrorIsNil() predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIsNull(FieldError))
+}
+
+// ErrorNotNil applies the NotNil predicate on the "error" field.
+func ErrorNotNil() predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotNull(FieldError))
+}
+
+// ErrorEqualFold applies the EqualFold predicate on the "error" field.
+func ErrorEqualFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEqualFold(FieldError, v))
+}
+
+// ErrorContainsFold applies the ContainsFold predicate on the "error" field.
+func ErrorContainsFold(v string) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldContainsFold(FieldError, v))
+}
+
+// StartedAtEQ applies the EQ predicate on the "started_at" field.
+func StartedAtEQ(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldStartedAt, v))
+}
+
+// StartedAtNEQ applies the NEQ predicate on the "started_at" field.
+func StartedAtNEQ(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNEQ(FieldStartedAt, v))
+}
+
+// StartedAtIn applies the In predicate on the "started_at" field.
+func StartedAtIn(vs ...time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIn(FieldStartedAt, vs...))
+}
+
+// StartedAtNotIn applies the NotIn predicate on the "started_at" field.
+func StartedAtNotIn(vs ...time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotIn(FieldStartedAt, vs...))
+}
+
+// StartedAtGT applies the GT predicate on the "started_at" field.
+func StartedAtGT(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGT(FieldStartedAt, v))
+}
+
+// StartedAtGTE applies the GTE predicate on the "started_at" field.
+func StartedAtGTE(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGTE(FieldStartedAt, v))
+}
+
+// StartedAtLT applies the LT predicate on the "started_at" field.
+func StartedAtLT(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLT(FieldStartedAt, v))
+}
+
+// StartedAtLTE applies the LTE predicate on the "started_at" field.
+func StartedAtLTE(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLTE(FieldStartedAt, v))
+}
+
+// FinishedAtEQ applies the EQ predicate on the "finished_at" field.
+func FinishedAtEQ(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldEQ(FieldFinishedAt, v))
+}
+
+// FinishedAtNEQ applies the NEQ predicate on the "finished_at" field.
+func FinishedAtNEQ(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNEQ(FieldFinishedAt, v))
+}
+
+// FinishedAtIn applies the In predicate on the "finished_at" field.
+func FinishedAtIn(vs ...time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIn(FieldFinishedAt, vs...))
+}
+
+// FinishedAtNotIn applies the NotIn predicate on the "finished_at" field.
+func FinishedAtNotIn(vs ...time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotIn(FieldFinishedAt, vs...))
+}
+
+// FinishedAtGT applies the GT predicate on the "finished_at" field.
+func FinishedAtGT(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGT(FieldFinishedAt, v))
+}
+
+// FinishedAtGTE applies the GTE predicate on the "finished_at" field.
+func FinishedAtGTE(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldGTE(FieldFinishedAt, v))
+}
+
+// FinishedAtLT applies the LT predicate on the "finished_at" field.
+func FinishedAtLT(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLT(FieldFinishedAt, v))
+}
+
+// FinishedAtLTE applies the LTE predicate on the "finished_at" field.
+func FinishedAtLTE(v time.Time) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldLTE(FieldFinishedAt, v))
+}
+
+// FinishedAtIsNil applies the IsNil predicate on the "finished_at" field.
+func FinishedAtIsNil() predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldIsNull(FieldFinishedAt))
+}
+
+// FinishedAtNotNil applies the NotNil predicate on the "finished_at" field.
+func FinishedAtNotNil() predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.FieldNotNull(FieldFinishedAt))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.ScheduledRun) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.ScheduledRun) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.ScheduledRun) predicate.ScheduledRun {
+	return predicate.ScheduledRun(sql.NotPredicates(p))
+}
@@ -0,0 +1,302 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/azahir21/go-backend-boilerplate/ent/scheduledrun"
+)
+
+// ScheduledRunCreate is the builder for creating a ScheduledRun entity.
+type ScheduledRunCreate struct {
+	config
+	mutation *ScheduledRunMutation
+	hooks    []Hook
+}
+
+// SetTask sets the "task" field.
+func (_c *ScheduledRunCreate) SetTask(v string) *ScheduledRunCreate {
+	_c.mutation.SetTask(v)
+	return _c
+}
+
+// SetTrigger sets the "trigger" field.
+func (_c *ScheduledRunCreate) SetTrigger(v string) *ScheduledRunCreate {
+	_c.mutation.SetTrigger(v)
+	return _c
+}
+
+// SetInstance sets the "instance" field.
+func (_c *ScheduledRunCreate) SetInstance(v string) *ScheduledRunCreate {
+	_c.mutation.SetInstance(v)
+	return _c
+}
+
+// SetStatus sets the "status" field.
+func (_c *ScheduledRunCreate) SetStatus(v string) *ScheduledRunCreate {
+	_c.mutation.SetStatus(v)
+	return _c
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_c *ScheduledRunCreate) SetNillableStatus(v *string) *ScheduledRunCreate {
+	if v != nil {
+		_c.SetStatus(*v)
+	}
+	return _c
+}
+
+// SetError sets the "error" field.
+func (_c *ScheduledRunCreate) SetError(v string) *ScheduledRunCreate {
+	_c.mutation.SetError(v)
+	return _c
+}
+
+// SetNillableError sets the "error" field if the given value is not nil.
+func (_c *ScheduledRunCreate) SetNillableError(v *string) *ScheduledRunCreate {
+	if v != nil {
+		_c.SetError(*v)
+	}
+	return _c
+}
+
+// SetStartedAt sets the "started_at" field.
+func (_c *ScheduledRunCreate) SetStartedAt(v time.Time) *ScheduledRunCreate {
+	_c.mutation.SetStartedAt(v)
+	return _c
+}
+
+// SetNillableStartedAt sets the "started_at" field if the given value is not nil.
+func (_c *ScheduledRunCreate) SetNillableStartedAt(v *time.Time) *ScheduledRunCreate {
+	if v != nil {
+		_c.SetStartedAt(*v)
+	}
+	return _c
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (_c *ScheduledRunCreate) SetFinishedAt(v time.Time) *ScheduledRunCreate {
+	_c.mutation.SetFinishedAt(v)
+	return _c
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (_c *ScheduledRunCreate) SetNillableFinishedAt(v *time.Time) *ScheduledRunCreate {
+	if v != nil {
+		_c.SetFinishedAt(*v)
+	}
+	return _c
+}
+
+// Mutation returns the ScheduledRunMutation object of the builder.
+func (_c *ScheduledRunCreate) Mutation() *ScheduledRunMutation {
+	return _c.mutation
+}
+
+// Save creates the ScheduledRun in the database.
+func (_c *ScheduledRunCreate) Save(ctx context.Context) (*ScheduledRun, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *ScheduledRunCreate) SaveX(ctx context.Context) *ScheduledRun {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ScheduledRunCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ScheduledRunCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *ScheduledRunCreate) defaults() {
+	if _, ok := _c.mutation.Status(); !ok {
+		v := scheduledrun.DefaultStatus
+		_c.mutation.SetStatus(v)
+	}
+	if _, ok := _c.mutation.StartedAt(); !ok {
+		v := scheduledrun.DefaultStartedAt()
+		_c.mutation.SetStartedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *ScheduledRunCreate) check() error {
+	if _, ok := _c.mutation.Task(); !ok {
+		return &ValidationError{Name: "task", err: errors.New(`ent: missing required field "ScheduledRun.task"`)}
+	}
+	if _, ok := _c.mutation.Trigger(); !ok {
+		return &ValidationError{Name: "trigger", err: errors.New(`ent: missing required field "ScheduledRun.trigger"`)}
+	}
+	if _, ok := _c.mutation.Instance(); !ok {
+		return &ValidationError{Name: "instance", err: errors.New(`ent: missing required field "ScheduledRun.instance"`)}
+	}
+	if _, ok := _c.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "ScheduledRun.status"`)}
+	}
+	if _, ok := _c.mutation.StartedAt(); !ok {
+		return &ValidationError{Name: "started_at", err: errors.New(`ent: missing required field "ScheduledRun.started_at"`)}
+	}
+	return nil
+}
+
+func (_c *ScheduledRunCreate) sqlSave(ctx context.Context) (*ScheduledRun, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *ScheduledRunCreate) createSpec() (*ScheduledRun, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ScheduledRun{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(scheduledrun.Table, sqlgraph.NewFieldSpec(scheduledrun.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.Task(); ok {
+		_spec.SetField(scheduledrun.FieldTask, field.TypeString, value)
+		_node.Task = value
+	}
+	if value, ok := _c.mutation.Trigger(); ok {
+		_spec.SetField(scheduledrun.FieldTrigger, field.TypeString, value)
+		_node.Trigger = value
+	}
+	if value, ok := _c.mutation.Instance(); ok {
+		_spec.SetField(scheduledrun.FieldInstance, field.TypeString, value)
+		_node.Instance = value
+	}
+	if value, ok := _c.mutation.Status(); ok {
+		_spec.SetField(scheduledrun.FieldStatus, field.TypeString, value)
+		_node.Status = value
+	}
+	if value, ok := _c.mutation.Error(); ok {
+		_spec.SetField(scheduledrun.FieldError, field.TypeString, value)
+		_node.Error = value
+	}
+	if value, ok := _c.mutation.StartedAt(); ok {
+		_spec.SetField(scheduledrun.FieldStartedAt, field.TypeTime, value)
+		_node.StartedAt = value
+	}
+	if value, ok := _c.mutation.FinishedAt(); ok {
+		_spec.SetField(scheduledrun.FieldFinishedAt, field.TypeTime, value)
+		_node.FinishedAt = &value
+	}
+	return _node, _spec
+}
+
+// ScheduledRunCreateBulk is the builder for creating many ScheduledRun entities in bulk.
+type ScheduledRunCreateBulk struct {
+	config
+	err      error
+	builders []*ScheduledRunCreate
+}
+
+// Save creates the ScheduledRun entities in the database.
+func (_c *ScheduledRunCreateBulk) Save(ctx context.Context) ([]*ScheduledRun, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*ScheduledRun, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ScheduledRunMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *ScheduledRunCreateBulk) SaveX(ctx context.Context) []*ScheduledRun {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ScheduledRunCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ScheduledRunCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
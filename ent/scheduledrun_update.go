@@ -0,0 +1,314 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/azahir21/go-backend-boilerplate/ent/predicate"
+	"github.com/azahir21/go-backend-boilerplate/ent/scheduledrun"
+)
+
+// ScheduledRunUpdate is the builder for updating ScheduledRun entities.
+type ScheduledRunUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ScheduledRunMutation
+}
+
+// Where appends a list predicates to the ScheduledRunUpdate builder.
+func (_u *ScheduledRunUpdate) Where(ps ...predicate.ScheduledRun) *ScheduledRunUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetStatus sets the "status" field.
+func (_u *ScheduledRunUpdate) SetStatus(v string) *ScheduledRunUpdate {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *ScheduledRunUpdate) SetNillableStatus(v *string) *ScheduledRunUpdate {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetError sets the "error" field.
+func (_u *ScheduledRunUpdate) SetError(v string) *ScheduledRunUpdate {
+	_u.mutation.SetError(v)
+	return _u
+}
+
+// SetNillableError sets the "error" field if the given value is not nil.
+func (_u *ScheduledRunUpdate) SetNillableError(v *string) *ScheduledRunUpdate {
+	if v != nil {
+		_u.SetError(*v)
+	}
+	return _u
+}
+
+// ClearError clears the value of the "error" field.
+func (_u *ScheduledRunUpdate) ClearError() *ScheduledRunUpdate {
+	_u.mutation.ClearError()
+	return _u
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (_u *ScheduledRunUpdate) SetFinishedAt(v time.Time) *ScheduledRunUpdate {
+	_u.mutation.SetFinishedAt(v)
+	return _u
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (_u *ScheduledRunUpdate) SetNillableFinishedAt(v *time.Time) *ScheduledRunUpdate {
+	if v != nil {
+		_u.SetFinishedAt(*v)
+	}
+	return _u
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (_u *ScheduledRunUpdate) ClearFinishedAt() *ScheduledRunUpdate {
+	_u.mutation.ClearFinishedAt()
+	return _u
+}
+
+// Mutation returns the ScheduledRunMutation object of the builder.
+func (_u *ScheduledRunUpdate) Mutation() *ScheduledRunMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *ScheduledRunUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ScheduledRunUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *ScheduledRunUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ScheduledRunUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *ScheduledRunUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(scheduledrun.Table, scheduledrun.Columns, sqlgraph.NewFieldSpec(scheduledrun.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(scheduledrun.FieldStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Error(); ok {
+		_spec.SetField(scheduledrun.FieldError, field.TypeString, value)
+	}
+	if _u.mutation.ErrorCleared() {
+		_spec.ClearField(scheduledrun.FieldError, field.TypeString)
+	}
+	if value, ok := _u.mutation.FinishedAt(); ok {
+		_spec.SetField(scheduledrun.FieldFinishedAt, field.TypeTime, value)
+	}
+	if _u.mutation.FinishedAtCleared() {
+		_spec.ClearField(scheduledrun.FieldFinishedAt, field.TypeTime)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{scheduledrun.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// ScheduledRunUpdateOne is the builder for updating a single ScheduledRun entity.
+type ScheduledRunUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ScheduledRunMutation
+}
+
+// SetStatus sets the "status" field.
+func (_u *ScheduledRunUpdateOne) SetStatus(v string) *ScheduledRunUpdateOne {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *ScheduledRunUpdateOne) SetNillableStatus(v *string) *ScheduledRunUpdateOne {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetError sets the "error" field.
+func (_u *ScheduledRunUpdateOne) SetError(v string) *ScheduledRunUpdateOne {
+	_u.mutation.SetError(v)
+	return _u
+}
+
+// SetNillableError sets the "error" field if the given value is not nil.
+func (_u *ScheduledRunUpdateOne) SetNillableError(v *string) *ScheduledRunUpdateOne {
+	if v != nil {
+		_u.SetError(*v)
+	}
+	return _u
+}
+
+// ClearError clears the value of the "error" field.
+func (_u *ScheduledRunUpdateOne) ClearError() *ScheduledRunUpdateOne {
+	_u.mutation.ClearError()
+	return _u
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (_u *ScheduledRunUpdateOne) SetFinishedAt(v time.Time) *ScheduledRunUpdateOne {
+	_u.mutation.SetFinishedAt(v)
+	return _u
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (_u *ScheduledRunUpdateOne) SetNillableFinishedAt(v *time.Time) *ScheduledRunUpdateOne {
+	if v != nil {
+		_u.SetFinishedAt(*v)
+	}
+	return _u
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (_u *ScheduledRunUpdateOne) ClearFinishedAt() *ScheduledRunUpdateOne {
+	_u.mutation.ClearFinishedAt()
+	return _u
+}
+
+// Mutation returns the ScheduledRunMutation object of the builder.
+func (_u *ScheduledRunUpdateOne) Mutation() *ScheduledRunMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the ScheduledRunUpdate builder.
+func (_u *ScheduledRunUpdateOne) Where(ps ...predicate.ScheduledRun) *ScheduledRunUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *ScheduledRunUpdateOne) Select(field string, fields ...string) *ScheduledRunUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated ScheduledRun entity.
+func (_u *ScheduledRunUpdateOne) Save(ctx context.Context) (*ScheduledRun, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ScheduledRunUpdateOne) SaveX(ctx context.Context) *ScheduledRun {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *ScheduledRunUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ScheduledRunUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *ScheduledRunUpdateOne) sqlSave(ctx context.Context) (_node *ScheduledRun, err error) {
+	_spec := sqlgraph.NewUpdateSpec(scheduledrun.Table, scheduledrun.Columns, sqlgraph.NewFieldSpec(scheduledrun.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ScheduledRun.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, scheduledrun.FieldID)
+		for _, f := range fields {
+			if !scheduledrun.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != scheduledrun.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(scheduledrun.FieldStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Error(); ok {
+		_spec.SetField(scheduledrun.FieldError, field.TypeString, value)
+	}
+	if _u.mutation.ErrorCleared() {
+		_spec.ClearField(scheduledrun.FieldError, field.TypeString)
+	}
+	if value, ok := _u.mutation.FinishedAt(); ok {
+		_spec.SetField(scheduledrun.FieldFinishedAt, field.TypeTime, value)
+	}
+	if _u.mutation.FinishedAtCleared() {
+		_spec.ClearField(scheduledrun.FieldFinishedAt, field.TypeTime)
+	}
+	_node = &ScheduledRun{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{scheduledrun.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
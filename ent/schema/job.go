@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Job holds the schema definition for the Job entity: a background job of the SQL-backed job
+// queue. Jobs are deleted once they succeed; jobs out of attempts stay as dead.
+type Job struct {
+	ent.Schema
+}
+
+// Fields of the Job.
+func (Job) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("type").
+			Immutable(),
+		field.Bytes("payload").
+			Immutable(),
+		field.String("status").
+			Default("pending"),
+		field.Int("attempts").
+			Default(0),
+		field.Int("max_attempts"),
+		field.Text("last_error").
+			Optional(),
+		field.Time("run_at").
+			Default(time.Now),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the Job.
+func (Job) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the Job.
+func (Job) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("type", "status", "run_at"),
+	}
+}
@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+)
+
+// LockLease holds the schema definition for the LockLease entity: a distributed lock held
+// by one owner until it expires, used when redis is not available. The row outlives its
+// leases to keep the fencing counter of the lock.
+type LockLease struct {
+	ent.Schema
+}
+
+// Fields of the LockLease.
+func (LockLease) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable(),
+		// Owner is the token of the current lease, empty once it is released.
+		field.String("owner"),
+		// Fence counts the acquisitions of the lock.
+		field.Int64("fence"),
+		field.Time("expires_at"),
+	}
+}
+
+// Edges of the LockLease.
+func (LockLease) Edges() []ent.Edge {
+	return nil
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// OutboxEvent holds the schema definition for the OutboxEvent entity: a domain event written
+// in the transaction of the change it describes, and delivered by the outbox dispatcher.
+type OutboxEvent struct {
+	ent.Schema
+}
+
+// Fields of the OutboxEvent.
+func (OutboxEvent) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("aggregate_type").
+			Immutable(),
+		field.String("aggregate_id").
+			Immutable(),
+		field.String("event_type").
+			Immutable(),
+		field.Bytes("payload").
+			Immutable(),
+		field.String("status").
+			Default("pending"),
+		field.Int("attempts").
+			Default(0),
+		field.Text("last_error").
+			Optional(),
+		field.Time("available_at").
+			Default(time.Now),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("delivered_at").
+			Optional().Nillable(),
+	}
+}
+
+// Edges of the OutboxEvent.
+func (OutboxEvent) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the OutboxEvent.
+func (OutboxEvent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status", "available_at"),
+	}
+}
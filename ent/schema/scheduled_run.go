@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// ScheduledRun holds the schema definition for the ScheduledRun entity: a run of a scheduled
+// task, started by its schedule or manually.
+type ScheduledRun struct {
+	ent.Schema
+}
+
+// Fields of the ScheduledRun.
+func (ScheduledRun) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("task").
+			Immutable(),
+		field.String("trigger").
+			Immutable(),
+		field.String("instance").
+			Immutable(),
+		field.String("status").
+			Default("running"),
+		field.Text("error").
+			Optional(),
+		field.Time("started_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("finished_at").
+			Optional().Nillable(),
+	}
+}
+
+// Edges of the ScheduledRun.
+func (ScheduledRun) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the ScheduledRun.
+func (ScheduledRun) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("task", "started_at"),
+	}
+}
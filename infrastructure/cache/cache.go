@@ -1,9 +1,102 @@
 package cache
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
+// ErrCacheMiss is returned by Get when the key is not in the cache, as opposed to a failure
+// of the cache backend.
+var ErrCacheMiss = errors.New("cache miss")
+
+// Cache stores JSON encoded values. A TTL of zero keeps an entry until it is evicted or
+// deleted.
 type Cache interface {
-	Set(ctx context.Context, key string, value interface{}, ttlSeconds int) error
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// Get decodes the value of key into dest, or returns ErrCacheMiss.
 	Get(ctx context.Context, key string, dest interface{}) error
-	Del(ctx context.Context, key string) error
+	// Del deletes the given keys; missing keys are ignored.
+	Del(ctx context.Context, keys ...string) error
+	// MGet returns the JSON encoded values of the given keys that are in the cache.
+	MGet(ctx context.Context, keys ...string) (map[string][]byte, error)
+	// MSet stores every entry for ttl.
+	MSet(ctx context.Context, entries map[string]interface{}, ttl time.Duration) error
+	// Exists reports whether key is in the cache.
+	Exists(ctx context.Context, key string) (bool, error)
+	// Incr adds delta to the integer value of key and returns the result. A missing key
+	// counts as 0 and is created with ttl; an existing key keeps its expiry.
+	Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+}
+
+// Namespace returns a view of c whose keys are prefixed with namespace and a colon, e.g.
+// cache.Namespace(deps.Cache, "user"), so that modules sharing the cache cannot collide.
+func Namespace(c Cache, namespace string) Cache {
+	return &namespaced{cache: c, prefix: namespace + ":"}
+}
+
+type namespaced struct {
+	cache  Cache
+	prefix string
+}
+
+func (n *namespaced) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return n.cache.Set(ctx, n.prefix+key, value, ttl)
+}
+
+func (n *namespaced) Get(ctx context.Context, key string, dest interface{}) error {
+	return n.cache.Get(ctx, n.prefix+key, dest)
+}
+
+func (n *namespaced) Del(ctx context.Context, keys ...string) error {
+	return n.cache.Del(ctx, n.keys(keys)...)
+}
+
+func (n *namespaced) MGet(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	found, err := n.cache.MGet(ctx, n.keys(keys)...)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string][]byte, len(found))
+	for key, data := range found {
+		values[key[len(n.prefix):]] = data
+	}
+	return values, nil
+}
+
+func (n *namespaced) MSet(ctx context.Context, entries map[string]interface{}, ttl time.Duration) error {
+	prefixed := make(map[string]interface{}, len(entries))
+	for key, value := range entries {
+		prefixed[n.prefix+key] = value
+	}
+	return n.cache.MSet(ctx, prefixed, ttl)
+}
+
+func (n *namespaced) Exists(ctx context.Context, key string) (bool, error) {
+	return n.cache.Exists(ctx, n.prefix+key)
+}
+
+func (n *namespaced) Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return n.cache.Incr(ctx, n.prefix+key, delta, ttl)
+}
+
+func (n *namespaced) keys(keys []string) []string {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = n.prefix + key
+	}
+	return prefixed
+}
+
+// fullKey resolves the namespaces of c around key, identifying the entry across views.
+func fullKey(c Cache, key string) string {
+	for {
+		n, ok := c.(*namespaced)
+		if !ok {
+			return key
+		}
+		key = n.prefix + key
+		c = n.cache
+	}
 }
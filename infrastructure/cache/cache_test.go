@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestCache(t *testing.T, keyPrefix string) *RistrettoCache {
+	t.Helper()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	c, err := NewRistrettoCache(log, config.RistrettoConfig{NumCounters: 1000, MaxCost: 1000, BufferItems: 64}, keyPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestRistrettoCache(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "app:")
+
+	var got string
+	if err := c.Get(ctx, "greeting", &got); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("Get() of a missing key error = %v, want ErrCacheMiss", err)
+	}
+	if err := c.Set(ctx, "greeting", "hello", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, "greeting", &got); err != nil || got != "hello" {
+		t.Fatalf("Get() = %q, %v, want the value just set", got, err)
+	}
+	if _, found := c.cached.Get("app:greeting"); !found {
+		t.Error("the entry is not stored under the key prefix")
+	}
+
+	if err := c.MSet(ctx, map[string]interface{}{"a": 1, "b": 2}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	values, err := c.MGet(ctx, "a", "b", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || string(values["a"]) != "1" || string(values["b"]) != "2" {
+		t.Errorf("MGet() = %q, want the two stored entries", values)
+	}
+
+	if err := c.Del(ctx, "a", "greeting"); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]bool{"a": false, "greeting": false, "b": true} {
+		if found, err := c.Exists(ctx, key); err != nil || found != want {
+			t.Errorf("Exists(%s) = %v, %v, want %v", key, found, err, want)
+		}
+	}
+}
+
+func TestRistrettoCache_Incr(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+
+	if n, err := c.Incr(ctx, "visits", 1, time.Minute); err != nil || n != 1 {
+		t.Fatalf("Incr() of a new counter = %d, %v, want 1", n, err)
+	}
+	if n, err := c.Incr(ctx, "visits", 4, time.Hour); err != nil || n != 5 {
+		t.Fatalf("Incr() = %d, %v, want 5", n, err)
+	}
+	if ttl, _ := c.cached.GetTTL("visits"); ttl > time.Minute {
+		t.Errorf("counter TTL = %v, want the TTL it was created with", ttl)
+	}
+	var n int64
+	if err := c.Get(ctx, "visits", &n); err != nil || n != 5 {
+		t.Errorf("Get() of a counter = %d, %v, want 5", n, err)
+	}
+
+	if err := c.Set(ctx, "name", "five", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Incr(ctx, "name", 1, 0); err == nil {
+		t.Error("Incr() of a string succeeded")
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+	users := Namespace(c, "user")
+
+	if err := users.MSet(ctx, map[string]interface{}{"1": "alice"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	var name string
+	if err := c.Get(ctx, "user:1", &name); err != nil || name != "alice" {
+		t.Fatalf("Get(user:1) = %q, %v, want the namespaced entry", name, err)
+	}
+	values, err := users.MGet(ctx, "1")
+	if err != nil || string(values["1"]) != `"alice"` {
+		t.Errorf("MGet() = %q, %v, want the entry under its key in the namespace", values, err)
+	}
+	if found, _ := Namespace(c, "order").Exists(ctx, "1"); found {
+		t.Error("an entry of another namespace exists")
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		loads.Add(1)
+		<-release
+		return "report", nil
+	}
+
+	// Concurrent misses share one load
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := GetOrLoad(ctx, c, "report", time.Minute, load); err != nil || v != "report" {
+				t.Errorf("GetOrLoad() = %q, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loaded %d times, want once", n)
+	}
+
+	// A hit far from its expiry does not load
+	if _, err := GetOrLoad(ctx, c, "report", time.Minute, load); err != nil || loads.Load() != 1 {
+		t.Errorf("GetOrLoad() of a cached entry loaded again (%v)", err)
+	}
+
+	failing := func(context.Context) (string, error) { return "", errors.New("database down") }
+	if _, err := GetOrLoad(ctx, c, "other", time.Minute, failing); err == nil || err.Error() != "database down" {
+		t.Errorf("GetOrLoad() error = %v, want the load error", err)
+	}
+}
+
+func TestGetOrLoad_RefreshesEarly(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+	// The last load took an hour and the entry is about to expire: a refresh is due
+	stale := loadedEntry[string]{Value: "old", Delta: time.Hour, ExpiresAt: time.Now().Add(time.Millisecond)}
+	if err := c.Set(ctx, "report", stale, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	failing := func(context.Context) (string, error) { return "", errors.New("database down") }
+	if v, err := GetOrLoad(ctx, c, "report", time.Minute, failing); err != nil || v != "old" {
+		t.Errorf("GetOrLoad() = %q, %v, want the cached value when the refresh fails", v, err)
+	}
+	refresh := func(context.Context) (string, error) { return "new", nil }
+	if v, err := GetOrLoad(ctx, c, "report", time.Minute, refresh); err != nil || v != "new" {
+		t.Errorf("GetOrLoad() = %q, %v, want the refreshed value", v, err)
+	}
+}
+
+func TestRefreshEarly(t *testing.T) {
+	now := time.Now()
+	if refreshEarly(time.Millisecond, now.Add(time.Hour), now) {
+		t.Error("a fast load far from its expiry was refreshed early")
+	}
+	if !refreshEarly(time.Millisecond, now, now) {
+		t.Error("an expired entry was not refreshed")
+	}
+	if refreshEarly(time.Hour, time.Time{}, now) {
+		t.Error("an entry without expiry was refreshed early")
+	}
+}
+
+func TestTagStore_NeverReusesVersions(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+	tags := NewTagStore(c)
+	clock := time.Unix(1000, 0)
+	tags.now = func() time.Time { return clock }
+
+	first := tags.Versions(ctx, []string{"User:1"})
+	if again := tags.Versions(ctx, []string{"User:1"}); again != first {
+		t.Fatalf("Versions() = %s then %s, want the seeded version kept", first, again)
+	}
+	clock = clock.Add(time.Second)
+	if err := tags.Invalidate(ctx, "User:1"); err != nil {
+		t.Fatal(err)
+	}
+	invalidated := tags.Versions(ctx, []string{"User:1"})
+	if invalidated == first {
+		t.Fatal("Invalidate() did not change the version")
+	}
+
+	// The version is evicted: a new one replaces it instead of an earlier one coming back
+	if err := c.Del(ctx, tagKeyPrefix+"User:1"); err != nil {
+		t.Fatal(err)
+	}
+	clock = clock.Add(time.Second)
+	if evicted := tags.Versions(ctx, []string{"User:1"}); evicted == first || evicted == invalidated {
+		t.Errorf("Versions() after an eviction = %s, want a version never used before", evicted)
+	}
+}
@@ -10,9 +10,9 @@ import (
 func NewCache(log *logrus.Logger, cfg config.Cache) (Cache, error) {
 	switch cfg.Type {
 	case "redis":
-		return NewRedisCache(log, cfg.Redis)
+		return NewRedisCache(log, cfg.Redis, cfg.KeyPrefix)
 	case "ristretto":
-		return NewRistrettoCache(log, cfg.Ristretto)
+		return NewRistrettoCache(log, cfg.Ristretto, cfg.KeyPrefix)
 	default:
 		return nil, fmt.Errorf("unsupported cache type: %s", cfg.Type)
 	}
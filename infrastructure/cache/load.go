@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// loads shares concurrent loads of the same entry within the process.
+var loads singleflight.Group
+
+// earlyRefreshBeta scales how early GetOrLoad refreshes entries; above 1 favours earlier
+// refreshes.
+const earlyRefreshBeta = 1.0
+
+// loadedEntry is the stored form of a value loaded by GetOrLoad.
+type loadedEntry[T any] struct {
+	Value T `json:"value"`
+	// Delta is how long the load took.
+	Delta     time.Duration `json:"delta"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+// GetOrLoad returns the value of key, or calls load and stores its result for ttl on a miss.
+// Concurrent misses of an entry within the process share a single load. To keep a popular
+// entry from expiring under many readers at once, each hit refreshes it early with a
+// probability that grows as the expiry nears and with the time the last load took. Entries
+// are stored along with this bookkeeping, so a key should only be read through GetOrLoad.
+//
+// A failing cache does not fail the call: the value is loaded instead.
+func GetOrLoad[T any](ctx context.Context, c Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	var entry loadedEntry[T]
+	err := c.Get(ctx, key, &entry)
+	cached := err == nil
+	if cached && !refreshEarly(entry.Delta, entry.ExpiresAt, time.Now()) {
+		return entry.Value, nil
+	}
+
+	// The entry type keeps loads of different value types for the same key apart
+	flight := fmt.Sprintf("%T:%s", entry, fullKey(c, key))
+	value, err, _ := loads.Do(flight, func() (interface{}, error) {
+		// Waiting callers share the result, so one of them giving up must not cancel the load
+		loadCtx := context.WithoutCancel(ctx)
+		start := time.Now()
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		loaded := loadedEntry[T]{Value: value, Delta: time.Since(start)}
+		if ttl > 0 {
+			loaded.ExpiresAt = time.Now().Add(ttl)
+		}
+		// Storing is best effort; the next read loads again
+		_ = c.Set(loadCtx, key, loaded, ttl)
+		return value, nil
+	})
+	if err != nil {
+		if cached {
+			// A failed early refresh still has the cached value to fall back on
+			return entry.Value, nil
+		}
+		var zero T
+		return zero, err
+	}
+	return value.(T), nil
+}
+
+// refreshEarly decides whether to refresh an entry before it expires, following the XFetch
+// algorithm ("Optimal Probabilistic Cache Stampede Prevention", Vattani et al.).
+func refreshEarly(delta time.Duration, expiresAt, now time.Time) bool {
+	if expiresAt.IsZero() || delta <= 0 {
+		return false
+	}
+	// -ln(u) for u in (0, 1] is exponentially distributed with mean 1
+	gap := time.Duration(float64(delta) * earlyRefreshBeta * -math.Log(1-rand.Float64()))
+	return !now.Add(gap).Before(expiresAt)
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"errors"
+
+	"github.com/dgraph-io/ristretto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Lookup results recorded in cache_requests_total.
+const (
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+var cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "cache_requests_total",
+	Help: "Number of cache lookups by backend (redis, ristretto) and result (hit, miss, error).",
+}, []string{"cache", "result"})
+
+// ristrettoCollector exports the built-in ristretto metrics, enabled by ristretto.metrics.
+type ristrettoCollector struct {
+	metrics *ristretto.Metrics
+	descs   map[string]*prometheus.Desc
+}
+
+// ristrettoCounters are the monotonic ristretto metrics, exported as ristretto_<name>_total.
+var ristrettoCounters = map[string]func(*ristretto.Metrics) uint64{
+	"hits":          (*ristretto.Metrics).Hits,
+	"misses":        (*ristretto.Metrics).Misses,
+	"keys_added":    (*ristretto.Metrics).KeysAdded,
+	"keys_updated":  (*ristretto.Metrics).KeysUpdated,
+	"keys_evicted":  (*ristretto.Metrics).KeysEvicted,
+	"cost_added":    (*ristretto.Metrics).CostAdded,
+	"cost_evicted":  (*ristretto.Metrics).CostEvicted,
+	"sets_dropped":  (*ristretto.Metrics).SetsDropped,
+	"sets_rejected": (*ristretto.Metrics).SetsRejected,
+	"gets_dropped":  (*ristretto.Metrics).GetsDropped,
+	"gets_kept":     (*ristretto.Metrics).GetsKept,
+}
+
+func registerRistrettoMetrics(metrics *ristretto.Metrics) error {
+	c := &ristrettoCollector{metrics: metrics, descs: make(map[string]*prometheus.Desc)}
+	for name := range ristrettoCounters {
+		c.descs[name] = prometheus.NewDesc("ristretto_"+name+"_total", "Ristretto cache metric "+name+".", nil, nil)
+	}
+	c.descs["ratio"] = prometheus.NewDesc("ristretto_hit_ratio", "Ristretto cache hit ratio.", nil, nil)
+
+	err := prometheus.Register(c)
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) {
+		// A re-created cache replaces the metrics source of the registered collector
+		already.ExistingCollector.(*ristrettoCollector).metrics = metrics
+		return nil
+	}
+	return err
+}
+
+func (c *ristrettoCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *ristrettoCollector) Collect(ch chan<- prometheus.Metric) {
+	for name, value := range ristrettoCounters {
+		ch <- prometheus.MustNewConstMetric(c.descs[name], prometheus.CounterValue, float64(value(c.metrics)))
+	}
+	ch <- prometheus.MustNewConstMetric(c.descs["ratio"], prometheus.GaugeValue, c.metrics.Ratio())
+}
@@ -3,20 +3,33 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
+// incrScript adds to a counter and sets the expiry of a new one.
+// KEYS[1] = counter key; ARGV[1] = delta; ARGV[2] = ttl (ms, 0 for none). Returns the new value.
+var incrScript = redis.NewScript(`
+local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call('PTTL', KEYS[1]) == -1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return value
+`)
+
 type RedisCache struct {
 	client *redis.Client
 	log    *logrus.Logger
+	prefix string
 }
 
-func NewRedisCache(log *logrus.Logger, cfg config.RedisConfig) (*RedisCache, error) {
+func NewRedisCache(log *logrus.Logger, cfg config.RedisConfig, keyPrefix string) (*RedisCache, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:     cfg.Addr,
 		Password: cfg.Password,
@@ -31,29 +44,38 @@ func NewRedisCache(log *logrus.Logger, cfg config.RedisConfig) (*RedisCache, err
 	}
 
 	log.Infof("Redis cache initialized at %s", cfg.Addr)
-	return &RedisCache{client: client, log: log}, nil
+	return &RedisCache{client: client, log: log, prefix: keyPrefix}, nil
 }
 
-func (r *RedisCache) Set(ctx context.Context, key string, value interface{}, ttlSeconds int) error {
+func (r *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) (err error) {
+	ctx, span := startSpan(ctx, "redis", "set", key)
+	defer func() { tracing.End(span, err) }()
+
 	data, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache value for key %s: %w", key, err)
 	}
 
-	if err := r.client.Set(ctx, key, data, time.Duration(ttlSeconds)*time.Second).Err(); err != nil {
+	if err := r.client.Set(ctx, r.prefix+key, data, ttl).Err(); err != nil {
 		return fmt.Errorf("failed to set cache key %s: %w", key, err)
 	}
 	return nil
 }
 
-func (r *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
-	data, err := r.client.Get(ctx, key).Bytes()
+func (r *RedisCache) Get(ctx context.Context, key string, dest interface{}) (err error) {
+	ctx, span := startSpan(ctx, "redis", "get", key)
+	result := resultError
+	defer func() { observeGet(span, "redis", result, err) }()
+
+	data, err := r.client.Get(ctx, r.prefix+key).Bytes()
 	if err != nil {
-		if err == redis.Nil {
-			return fmt.Errorf("cache key not found: %s", key)
+		if errors.Is(err, redis.Nil) {
+			result = resultMiss
+			return ErrCacheMiss
 		}
 		return fmt.Errorf("failed to get cache key %s: %w", key, err)
 	}
+	result = resultHit
 
 	if err := json.Unmarshal(data, dest); err != nil {
 		return fmt.Errorf("failed to unmarshal cache value for key %s: %w", key, err)
@@ -61,9 +83,108 @@ func (r *RedisCache) Get(ctx context.Context, key string, dest interface{}) erro
 	return nil
 }
 
-func (r *RedisCache) Del(ctx context.Context, key string) error {
-	if err := r.client.Del(ctx, key).Err(); err != nil {
-		return fmt.Errorf("failed to delete cache key %s: %w", key, err)
+func (r *RedisCache) Del(ctx context.Context, keys ...string) (err error) {
+	if len(keys) == 0 {
+		return nil
+	}
+	ctx, span := startSpan(ctx, "redis", "del", keys...)
+	defer func() { tracing.End(span, err) }()
+
+	if err := r.client.Del(ctx, r.keys(keys)...).Err(); err != nil {
+		return fmt.Errorf("failed to delete cache keys %v: %w", keys, err)
 	}
 	return nil
 }
+
+func (r *RedisCache) MGet(ctx context.Context, keys ...string) (_ map[string][]byte, err error) {
+	values := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+	ctx, span := startSpan(ctx, "redis", "mget", keys...)
+	defer func() { observeMGet(span, "redis", len(values), len(keys)-len(values), err) }()
+
+	found, err := r.client.MGet(ctx, r.keys(keys)...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache keys %v: %w", keys, err)
+	}
+	for i, value := range found {
+		// Missing keys are nil
+		if data, ok := value.(string); ok {
+			values[keys[i]] = []byte(data)
+		}
+	}
+	return values, nil
+}
+
+func (r *RedisCache) MSet(ctx context.Context, entries map[string]interface{}, ttl time.Duration) (err error) {
+	if len(entries) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	ctx, span := startSpan(ctx, "redis", "mset", keys...)
+	defer func() { tracing.End(span, err) }()
+
+	pipe := r.client.Pipeline()
+	for key, value := range entries {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal cache value for key %s: %w", key, err)
+		}
+		pipe.Set(ctx, r.prefix+key, data, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set cache keys %v: %w", keys, err)
+	}
+	return nil
+}
+
+func (r *RedisCache) Exists(ctx context.Context, key string) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "redis", "exists", key)
+	defer func() { tracing.End(span, err) }()
+
+	n, err := r.client.Exists(ctx, r.prefix+key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check cache key %s: %w", key, err)
+	}
+	return n == 1, nil
+}
+
+func (r *RedisCache) Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "redis", "incr", key)
+	defer func() { tracing.End(span, err) }()
+
+	value, err := incrScript.Run(ctx, r.client, []string{r.prefix + key}, delta, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment cache key %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// keys applies the key prefix.
+func (r *RedisCache) keys(keys []string) []string {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = r.prefix + key
+	}
+	return prefixed
+}
+
+// Client returns the underlying redis client so that other subsystems
+// (e.g. rate limiting) can share the connection for atomic operations.
+func (r *RedisCache) Client() *redis.Client {
+	return r.client
+}
+
+// HealthCheck pings the redis server.
+func (r *RedisCache) HealthCheck(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Close closes the redis connection pool, shared with the rate limiter and idempotency store.
+func (r *RedisCache) Close() error {
+	return r.client.Close()
+}
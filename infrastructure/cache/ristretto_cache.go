@@ -3,20 +3,29 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
 	"github.com/dgraph-io/ristretto"
 	"github.com/sirupsen/logrus"
 )
 
+// RistrettoCache is an in-process cache. Unlike plain ristretto, writes are visible to reads
+// as soon as they return, e.g. a bumped tag version.
 type RistrettoCache struct {
 	cached *ristretto.Cache
 	log    *logrus.Logger
+	prefix string
+	// incrMu makes the read-modify-write of Incr atomic
+	incrMu sync.Mutex
 }
 
-func NewRistrettoCache(log *logrus.Logger, cfg config.RistrettoConfig) (*RistrettoCache, error) {
+func NewRistrettoCache(log *logrus.Logger, cfg config.RistrettoConfig, keyPrefix string) (*RistrettoCache, error) {
 	cached, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: cfg.NumCounters,
 		MaxCost:     cfg.MaxCost,
@@ -26,33 +35,44 @@ func NewRistrettoCache(log *logrus.Logger, cfg config.RistrettoConfig) (*Ristret
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize ristretto cache: %w", err)
 	}
+	if cfg.Metrics {
+		if err := registerRistrettoMetrics(cached.Metrics); err != nil {
+			return nil, fmt.Errorf("failed to register ristretto metrics: %w", err)
+		}
+	}
 
 	log.Info("Ristretto in-memory cache initialized")
-	return &RistrettoCache{cached: cached, log: log}, nil
+	return &RistrettoCache{cached: cached, log: log, prefix: keyPrefix}, nil
 }
 
-func (r *RistrettoCache) Set(ctx context.Context, key string, value interface{}, ttlSeconds int) error {
+func (r *RistrettoCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) (err error) {
+	_, span := startSpan(ctx, "ristretto", "set", key)
+	defer func() { tracing.End(span, err) }()
+
 	data, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache value for key %s: %w", key, err)
 	}
-
-	if !r.cached.SetWithTTL(key, data, 1, time.Duration(ttlSeconds)*time.Second) {
-		return fmt.Errorf("failed to set cache key %s: cache rejected the entry", key)
+	if err := r.set(key, data, ttl); err != nil {
+		return err
 	}
+	r.cached.Wait()
 	return nil
 }
 
-func (r *RistrettoCache) Get(ctx context.Context, key string, dest interface{}) error {
-	value, found := r.cached.Get(key)
-	if !found {
-		return fmt.Errorf("cache key not found: %s", key)
-	}
+func (r *RistrettoCache) Get(ctx context.Context, key string, dest interface{}) (err error) {
+	_, span := startSpan(ctx, "ristretto", "get", key)
+	result := resultMiss
+	defer func() { observeGet(span, "ristretto", result, err) }()
 
-	data, ok := value.([]byte)
-	if !ok {
-		return fmt.Errorf("cache value for key %s has unexpected type: %T", key, value)
+	data, err := r.get(key)
+	if err != nil {
+		if !errors.Is(err, ErrCacheMiss) {
+			result = resultError
+		}
+		return err
 	}
+	result = resultHit
 
 	if err := json.Unmarshal(data, dest); err != nil {
 		return fmt.Errorf("failed to unmarshal cache value for key %s: %w", key, err)
@@ -60,7 +80,119 @@ func (r *RistrettoCache) Get(ctx context.Context, key string, dest interface{})
 	return nil
 }
 
-func (r *RistrettoCache) Del(ctx context.Context, key string) error {
-	r.cached.Del(key)
+func (r *RistrettoCache) Del(ctx context.Context, keys ...string) error {
+	_, span := startSpan(ctx, "ristretto", "del", keys...)
+	defer span.End()
+
+	for _, key := range keys {
+		r.cached.Del(r.prefix + key)
+	}
+	return nil
+}
+
+func (r *RistrettoCache) MGet(ctx context.Context, keys ...string) (_ map[string][]byte, err error) {
+	values := make(map[string][]byte, len(keys))
+	_, span := startSpan(ctx, "ristretto", "mget", keys...)
+	defer func() { observeMGet(span, "ristretto", len(values), len(keys)-len(values), err) }()
+
+	for _, key := range keys {
+		data, err := r.get(key)
+		if errors.Is(err, ErrCacheMiss) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[key] = data
+	}
+	return values, nil
+}
+
+func (r *RistrettoCache) MSet(ctx context.Context, entries map[string]interface{}, ttl time.Duration) (err error) {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	_, span := startSpan(ctx, "ristretto", "mset", keys...)
+	defer func() { tracing.End(span, err) }()
+
+	for key, value := range entries {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal cache value for key %s: %w", key, err)
+		}
+		if err := r.set(key, data, ttl); err != nil {
+			return err
+		}
+	}
+	r.cached.Wait()
+	return nil
+}
+
+func (r *RistrettoCache) Exists(ctx context.Context, key string) (bool, error) {
+	_, span := startSpan(ctx, "ristretto", "exists", key)
+	defer span.End()
+
+	_, found := r.cached.Get(r.prefix + key)
+	return found, nil
+}
+
+// Incr stores the counter like Set would store the integer, so that Get decodes it. Counters
+// can be evicted like any other entry.
+func (r *RistrettoCache) Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (_ int64, err error) {
+	_, span := startSpan(ctx, "ristretto", "incr", key)
+	defer func() { tracing.End(span, err) }()
+
+	r.incrMu.Lock()
+	defer r.incrMu.Unlock()
+
+	var value int64
+	data, err := r.get(key)
+	switch {
+	case err == nil:
+		// A counter expiring in the meantime starts over
+		if remaining, ok := r.cached.GetTTL(r.prefix + key); ok {
+			if value, err = strconv.ParseInt(string(data), 10, 64); err != nil {
+				return 0, fmt.Errorf("failed to increment cache key %s: value is not an integer", key)
+			}
+			// Keep the expiry of the existing counter
+			ttl = remaining
+		}
+	case !errors.Is(err, ErrCacheMiss):
+		return 0, err
+	}
+
+	value += delta
+	if err := r.set(key, []byte(strconv.FormatInt(value, 10)), ttl); err != nil {
+		return 0, err
+	}
+	r.cached.Wait()
+	return value, nil
+}
+
+// set stores the JSON encoded value of key.
+func (r *RistrettoCache) set(key string, data []byte, ttl time.Duration) error {
+	if !r.cached.SetWithTTL(r.prefix+key, data, 1, ttl) {
+		return fmt.Errorf("failed to set cache key %s: cache rejected the entry", key)
+	}
+	return nil
+}
+
+// get returns the JSON encoded value of key, or ErrCacheMiss.
+func (r *RistrettoCache) get(key string) ([]byte, error) {
+	value, found := r.cached.Get(r.prefix + key)
+	if !found {
+		return nil, ErrCacheMiss
+	}
+	data, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("cache value for key %s has unexpected type: %T", key, value)
+	}
+	return data, nil
+}
+
+// Close stops the ristretto background goroutines.
+func (r *RistrettoCache) Close() error {
+	r.cached.Close()
 	return nil
 }
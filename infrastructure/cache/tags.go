@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const tagKeyPrefix = "tag:"
+
+// Invalidator invalidates every cache entry associated with one of the given tags.
+// Repositories call it (directly or through an ent hook) after writes.
+type Invalidator interface {
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
+// EntityTag returns the invalidation tag of a single record, e.g. "User:42".
+func EntityTag(entityType string, id interface{}) string {
+	return fmt.Sprintf("%s:%v", entityType, id)
+}
+
+// EntityTags returns the invalidation tags of an entity: its type (e.g. "User") and,
+// when id is not nil, the single record. Any write to a type invalidates the type tag.
+func EntityTags(entityType string, id interface{}) []string {
+	if id == nil {
+		return []string{entityType}
+	}
+	return []string{entityType, EntityTag(entityType, id)}
+}
+
+// TagStore implements tag-based invalidation on top of any Cache.
+// Each tag has a version; entries embed the versions of their tags in their key,
+// so bumping a version makes all entries of that tag unreachable without enumerating them.
+// Versions are timestamps and are never reused: a version lost to eviction is replaced by a
+// new one, which also leaves the entries stored under the lost version unreachable.
+type TagStore struct {
+	cache Cache
+	now   func() time.Time
+}
+
+// NewTagStore creates a TagStore backed by the given cache.
+func NewTagStore(c Cache) *TagStore {
+	return &TagStore{cache: c, now: time.Now}
+}
+
+// Versions returns a fingerprint of the current versions of the given tags,
+// suitable for inclusion in a cache key.
+func (t *TagStore) Versions(ctx context.Context, tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	found, err := t.cache.MGet(ctx, t.keys(tags)...)
+	seed := t.now().UnixNano()
+	seeded := make(map[string]interface{})
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		key := tagKeyPrefix + tag
+		var version int64
+		data, ok := found[key]
+		if !ok || json.Unmarshal(data, &version) != nil {
+			// A tag never invalidated, evicted or unreadable gets a new version
+			version = seed
+			seeded[key] = version
+		}
+		parts[i] = tag + "=" + strconv.FormatInt(version, 36)
+	}
+	// Without the stored versions the new ones only hold for this call; storing them is
+	// best effort, as an entry under a version that did not stick is merely never read
+	if len(seeded) > 0 && err == nil {
+		_ = t.cache.MSet(ctx, seeded, 0)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Invalidate implements Invalidator by bumping the version of every tag.
+func (t *TagStore) Invalidate(ctx context.Context, tags ...string) error {
+	version := t.now().UnixNano()
+	entries := make(map[string]interface{}, len(tags))
+	for _, key := range t.keys(tags) {
+		entries[key] = version
+	}
+	// Tag versions do not expire; a lost version is replaced by a new one in Versions
+	if err := t.cache.MSet(ctx, entries, 0); err != nil {
+		return fmt.Errorf("failed to invalidate cache tags %v: %w", tags, err)
+	}
+	return nil
+}
+
+// keys returns the cache keys of the tag versions.
+func (t *TagStore) keys(tags []string) []string {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = tagKeyPrefix + tag
+	}
+	return keys
+}
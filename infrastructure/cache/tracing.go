@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/azahir21/go-backend-boilerplate/infrastructure/cache")
+
+// startSpan starts a client span for a cache operation, e.g. "cache.get". Operations on several
+// keys record how many instead of the keys.
+func startSpan(ctx context.Context, backend, operation string, keys ...string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{attribute.String("cache.backend", backend)}
+	if len(keys) == 1 {
+		attrs = append(attrs, attribute.String("cache.key", keys[0]))
+	} else {
+		attrs = append(attrs, attribute.Int("cache.keys", len(keys)))
+	}
+	return tracer.Start(ctx, "cache."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// observeGet records the result of a lookup in cache_requests_total and on its span, and ends
+// the span. A miss is not an error.
+func observeGet(span trace.Span, backend, result string, err error) {
+	cacheRequests.WithLabelValues(backend, result).Inc()
+	span.SetAttributes(attribute.String("cache.result", result))
+	if result == resultMiss {
+		err = nil
+	}
+	tracing.End(span, err)
+}
+
+// observeMGet records the hits and misses of a batch lookup in cache_requests_total and on its
+// span, and ends the span.
+func observeMGet(span trace.Span, backend string, hits, misses int, err error) {
+	if err != nil {
+		cacheRequests.WithLabelValues(backend, resultError).Inc()
+	} else {
+		cacheRequests.WithLabelValues(backend, resultHit).Add(float64(hits))
+		cacheRequests.WithLabelValues(backend, resultMiss).Add(float64(misses))
+		span.SetAttributes(attribute.Int("cache.hits", hits))
+	}
+	tracing.End(span, err)
+}
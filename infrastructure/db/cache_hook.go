@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/sirupsen/logrus"
+)
+
+// CacheInvalidationHook returns an ent hook that invalidates the cache tags of every
+// mutated entity, so repositories invalidate cached responses on writes without extra code.
+// Inside a transaction, invalidation is deferred until the transaction commits.
+func CacheInvalidationHook(log *logrus.Logger, inv cache.Invalidator) ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			if m.Type() == ent.TypeOutboxEvent || m.Type() == ent.TypeJob {
+				// Outbox and job queue bookkeeping is never cached
+				return next.Mutate(ctx, m)
+			}
+			tags := cache.EntityTags(m.Type(), nil)
+			if !m.Op().Is(ent.OpCreate) {
+				// Resolve the affected IDs before the rows change (or disappear).
+				if idm, ok := m.(interface {
+					IDs(context.Context) ([]int, error)
+				}); ok {
+					if ids, err := idm.IDs(ctx); err == nil {
+						for _, id := range ids {
+							tags = append(tags, cache.EntityTag(m.Type(), id))
+						}
+					}
+				}
+			}
+
+			value, err := next.Mutate(ctx, m)
+			if err != nil {
+				return value, err
+			}
+
+			invalidate := func(ctx context.Context) {
+				if err := inv.Invalidate(ctx, tags...); err != nil {
+					log.WithError(err).WithField("tags", tags).Warn("Failed to invalidate cache tags")
+				}
+			}
+			if txm, ok := m.(interface{ Tx() (*ent.Tx, error) }); ok {
+				if tx, err := txm.Tx(); err == nil {
+					tx.OnCommit(func(next ent.Committer) ent.Committer {
+						return ent.CommitFunc(func(ctx context.Context, tx *ent.Tx) error {
+							if err := next.Commit(ctx, tx); err != nil {
+								return err
+							}
+							invalidate(ctx)
+							return nil
+						})
+					})
+					return value, nil
+				}
+			}
+			invalidate(ctx)
+			return value, nil
+		})
+	}
+}
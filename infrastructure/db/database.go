@@ -2,9 +2,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
+	entsql "entgo.io/ent/dialect/sql"
 	"github.com/azahir21/go-backend-boilerplate/ent"
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
 	_ "github.com/go-sql-driver/mysql" // MySQL driver
@@ -13,29 +16,26 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func NewEntClient(log *logrus.Logger, cfg *config.Config) (*ent.Client, error) {
-	var dsn string
-
-	if cfg.DB.DSN != "" {
-		dsn = cfg.DB.DSN
-	} else {
-		switch cfg.DB.Driver {
-		case "postgres":
-			dsn = fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-				cfg.DB.Host, cfg.DB.Port, cfg.DB.User, cfg.DB.Password, cfg.DB.Name, cfg.DB.SSLMode)
-		case "mysql", "mariadb":
-			dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=True",
-				cfg.DB.User, cfg.DB.Password, cfg.DB.Host, cfg.DB.Port, cfg.DB.Name)
-		case "sqlite3":
-			dsn = fmt.Sprintf("file:%s?_fk=1", cfg.DB.Name)
-		default:
-			return nil, fmt.Errorf("unsupported database driver: %s", cfg.DB.Driver)
-		}
+// NewEntClient opens the ent client of the SQL database, applying migrations and checking
+// for schema drift as configured. It also returns the connection pool of the client, e.g. for
+// session-level locks; closing the client closes it.
+func NewEntClient(log *logrus.Logger, cfg *config.Config) (*ent.Client, *sql.DB, error) {
+	dsn, err := dataSourceName(cfg.DB)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	client, err := ent.Open(cfg.DB.Driver, dsn)
+	drv, err := entsql.Open(cfg.DB.Driver, dsn)
 	if err != nil {
-		return nil, fmt.Errorf("failed opening connection to %s: %w", cfg.DB.Driver, err)
+		return nil, nil, fmt.Errorf("failed opening connection to %s: %w", cfg.DB.Driver, err)
+	}
+	client := ent.NewClient(ent.Driver(instrument(drv)))
+
+	if cfg.DB.Migrations.ApplyOnStart {
+		if err := ApplyMigrations(context.Background(), log, drv.DB(), cfg.DB); err != nil {
+			client.Close()
+			return nil, nil, err
+		}
 	}
 
 	if cfg.DB.AutoMigrate {
@@ -45,11 +45,59 @@ func NewEntClient(log *logrus.Logger, cfg *config.Config) (*ent.Client, error) {
 
 		if err := client.Schema.Create(ctx); err != nil {
 			client.Close() // Close client on migration failure
-			return nil, fmt.Errorf("failed creating schema resources: %w", err)
+			return nil, nil, fmt.Errorf("failed creating schema resources: %w", err)
 		}
 		log.Info("Database schema migration completed successfully")
 	}
 
+	if cfg.DB.Migrations.CheckDrift {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		statements, err := SchemaDrift(ctx, drv.DB(), cfg.DB.Driver)
+		if err != nil {
+			client.Close()
+			return nil, nil, err
+		}
+		if len(statements) > 0 {
+			client.Close()
+			return nil, nil, fmt.Errorf("ent schema differs from the database; a migration is missing for:\n%s", strings.Join(statements, "\n"))
+		}
+		log.Info("Database schema matches the ent schema")
+	}
+
 	log.Infof("Database connection established (driver: %s, database: %s)", cfg.DB.Driver, cfg.DB.Name)
-	return client, nil
+	return client, drv.DB(), nil
+}
+
+// OpenDB opens a plain database connection, e.g. for migrations and seed data.
+func OpenDB(cfg config.Database) (*sql.DB, error) {
+	dsn, err := dataSourceName(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(cfg.Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening connection to %s: %w", cfg.Driver, err)
+	}
+	return db, nil
+}
+
+// dataSourceName returns the configured DSN or builds one from the connection settings.
+func dataSourceName(cfg config.Database) (string, error) {
+	if cfg.DSN != "" {
+		return cfg.DSN, nil
+	}
+	switch cfg.Driver {
+	case "postgres":
+		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode), nil
+	case "mysql", "mariadb":
+		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=True",
+			cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name), nil
+	case "sqlite3":
+		return fmt.Sprintf("file:%s?_fk=1", cfg.Name), nil
+	default:
+		return "", fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+	}
 }
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	atlas "ariga.io/atlas/sql/migrate"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/schema"
+	"github.com/azahir21/go-backend-boilerplate/ent/migrate"
+)
+
+// SchemaDrift compares the ent schema with the database and returns the statements ent would
+// run to reconcile them; none when the migrated database matches the schema. Columns and
+// indexes that ent does not know count as drift, tables it does not know (e.g.
+// goose_db_version) do not. Nothing is changed in the database.
+func SchemaDrift(ctx context.Context, db *sql.DB, driver string) ([]string, error) {
+	entDialect, err := entDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+	var statements []string
+	record := schema.WithApplyHook(func(schema.Applier) schema.Applier {
+		return schema.ApplyFunc(func(_ context.Context, _ dialect.ExecQuerier, plan *atlas.Plan) error {
+			for _, c := range plan.Changes {
+				statements = append(statements, c.Cmd)
+			}
+			return nil
+		})
+	})
+	err = migrate.NewSchema(entsql.OpenDB(entDialect, db)).Create(ctx, record, schema.WithDropColumn(true), schema.WithDropIndex(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare the ent schema with the database: %w", err)
+	}
+	return statements, nil
+}
@@ -0,0 +1,20 @@
+package db
+
+import (
+	"context"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/pkg/health"
+)
+
+// NewHealthChecker returns a checker for the SQL database. It begins and rolls back a
+// transaction, which takes a pooled connection and makes a round trip to the server.
+func NewHealthChecker(client *ent.Client) health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		tx, err := client.Tx(ctx)
+		if err != nil {
+			return err
+		}
+		return tx.Rollback()
+	})
+}
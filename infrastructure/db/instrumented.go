@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/azahir21/go-backend-boilerplate/infrastructure/db")
+
+var (
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "SQL statement latency by operation (select, insert, update, delete, other).",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+
+	queryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Number of failed SQL statements by operation.",
+	}, []string{"operation"})
+)
+
+// instrumentedDriver records the latency and errors of every statement executed through ent,
+// inside or outside transactions, and traces each statement as a client span.
+type instrumentedDriver struct {
+	dialect.Driver
+}
+
+func instrument(drv dialect.Driver) dialect.Driver {
+	return &instrumentedDriver{Driver: drv}
+}
+
+func (d *instrumentedDriver) Exec(ctx context.Context, query string, args, v any) error {
+	return observeStatement(ctx, d.Dialect(), query, func(ctx context.Context) error { return d.Driver.Exec(ctx, query, args, v) })
+}
+
+func (d *instrumentedDriver) Query(ctx context.Context, query string, args, v any) error {
+	return observeStatement(ctx, d.Dialect(), query, func(ctx context.Context) error { return d.Driver.Query(ctx, query, args, v) })
+}
+
+func (d *instrumentedDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedTx{Tx: tx, dialect: d.Dialect()}, nil
+}
+
+// BeginTx starts a transaction with options, such as an isolation level or read-only mode.
+func (d *instrumentedDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	drv, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("%s driver does not support transaction options", d.Dialect())
+	}
+	tx, err := drv.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedTx{Tx: tx, dialect: d.Dialect()}, nil
+}
+
+type instrumentedTx struct {
+	dialect.Tx
+	dialect string
+}
+
+func (t *instrumentedTx) Exec(ctx context.Context, query string, args, v any) error {
+	return observeStatement(ctx, t.dialect, query, func(ctx context.Context) error { return t.Tx.Exec(ctx, query, args, v) })
+}
+
+func (t *instrumentedTx) Query(ctx context.Context, query string, args, v any) error {
+	return observeStatement(ctx, t.dialect, query, func(ctx context.Context) error { return t.Tx.Query(ctx, query, args, v) })
+}
+
+// ExecContext runs a raw statement in the transaction, e.g. a SAVEPOINT.
+func (t *instrumentedTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	tx, ok := t.Tx.(interface {
+		ExecContext(context.Context, string, ...any) (sql.Result, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("%s transaction does not support ExecContext", t.dialect)
+	}
+	var result sql.Result
+	err := observeStatement(ctx, t.dialect, query, func(ctx context.Context) error {
+		var err error
+		result, err = tx.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// observeStatement runs a statement in a span named after its operation. The statement is
+// recorded without its arguments.
+func observeStatement(ctx context.Context, system, query string, run func(ctx context.Context) error) error {
+	operation := statementOperation(query)
+	ctx, span := tracer.Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", system),
+			attribute.String("db.operation", operation),
+			attribute.String("db.statement", query),
+		),
+	)
+	start := time.Now()
+	err := run(ctx)
+	queryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		queryErrors.WithLabelValues(operation).Inc()
+	}
+	tracing.End(span, err)
+	return err
+}
+
+// statementOperation returns the lower-cased leading keyword of common DML statements.
+func statementOperation(query string) string {
+	verb, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	switch verb = strings.ToLower(verb); verb {
+	case "select", "insert", "update", "delete":
+		return verb
+	default:
+		return "other"
+	}
+}
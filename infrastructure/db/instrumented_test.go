@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/azahir21/go-backend-boilerplate/ent"
+)
+
+func TestInstrumentedDriver_TxOptionsAndRawStatements(t *testing.T) {
+	ctx := context.Background()
+	db, cfg := openTestDB(t)
+	client := ent.NewClient(ent.Driver(instrument(entsql.OpenDB(cfg.Driver, db))))
+	if err := client.Schema.Create(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := client.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	defer tx.Rollback()
+	for _, stmt := range []string{"SAVEPOINT sp", "RELEASE SAVEPOINT sp"} {
+		if _, err := tx.Client().ExecContext(ctx, stmt); err != nil {
+			t.Errorf("ExecContext(%q) error = %v", stmt, err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/migrations"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultLockTimeout bounds the wait for the migration lock when database.migrations.lock_timeout
+// is not set.
+const defaultLockTimeout = 5 * time.Minute
+
+// lockPollInterval is how often a replica retries acquiring the PostgreSQL advisory lock.
+const lockPollInterval = 5 * time.Second
+
+// NewMigrator returns a goose provider for the embedded migrations of the configured driver,
+// tracking applied versions in the goose_db_version table. Migrations run under a database
+// lock, so that replicas migrating the same database at once apply each version only once.
+func NewMigrator(db *sql.DB, cfg config.Database) (*goose.Provider, error) {
+	fsys, err := migrations.ForDriver(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(db, cfg, fsys)
+}
+
+func newMigrator(db *sql.DB, cfg config.Database, fsys fs.FS) (*goose.Provider, error) {
+	dialect, err := gooseDialect(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+	var opts []goose.ProviderOption
+	locker, err := sessionLocker(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if locker != nil {
+		opts = append(opts, goose.WithSessionLocker(locker))
+	}
+	provider, err := goose.NewProvider(dialect, db, fsys, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return provider, nil
+}
+
+// ApplyMigrations applies the pending embedded migrations.
+func ApplyMigrations(ctx context.Context, log *logrus.Logger, db *sql.DB, cfg config.Database) error {
+	migrator, err := NewMigrator(db, cfg)
+	if err != nil {
+		return err
+	}
+	results, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	for _, r := range results {
+		log.Infof("Applied migration %s (%s)", r.Source.Path, r.Duration)
+	}
+	version, err := migrator.GetDBVersion(ctx)
+	if err != nil {
+		return err
+	}
+	log.Infof("Database schema is at version %d", version)
+	return nil
+}
+
+func gooseDialect(driver string) (goose.Dialect, error) {
+	switch driver {
+	case "postgres":
+		return goose.DialectPostgres, nil
+	case "mysql", "mariadb":
+		return goose.DialectMySQL, nil
+	case "sqlite3":
+		return goose.DialectSQLite3, nil
+	default:
+		return "", fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
+
+// sessionLocker returns the migration lock of the driver. SQLite has none: the database is a
+// local file and its writes are serialized anyway.
+func sessionLocker(cfg config.Database) (lock.SessionLocker, error) {
+	timeout := defaultLockTimeout
+	if cfg.Migrations.LockTimeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(cfg.Migrations.LockTimeout); err != nil {
+			return nil, fmt.Errorf("invalid database.migrations.lock_timeout: %w", err)
+		}
+	}
+	switch cfg.Driver {
+	case "postgres":
+		attempts := uint64(timeout / lockPollInterval)
+		if attempts < 1 {
+			attempts = 1
+		}
+		return lock.NewPostgresSessionLocker(lock.WithLockTimeout(uint64(lockPollInterval/time.Second), attempts))
+	case "mysql", "mariadb":
+		return mysqlSessionLocker{name: "goose_migrations", timeout: timeout}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// mysqlSessionLocker holds a named lock (GET_LOCK) for the duration of the migration session.
+type mysqlSessionLocker struct {
+	name    string
+	timeout time.Duration
+}
+
+func (l mysqlSessionLocker) SessionLock(ctx context.Context, conn *sql.Conn) error {
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", l.name, int(l.timeout.Seconds())).Scan(&acquired); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if acquired.Int64 != 1 {
+		return fmt.Errorf("timed out after %s waiting for the migration lock", l.timeout)
+	}
+	return nil
+}
+
+func (l mysqlSessionLocker) SessionUnlock(ctx context.Context, conn *sql.Conn) error {
+	if _, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", l.name); err != nil {
+		return fmt.Errorf("failed to release migration lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+)
+
+func openTestDB(t *testing.T) (*sql.DB, config.Database) {
+	t.Helper()
+	cfg := config.Database{Driver: "sqlite3", Name: filepath.Join(t.TempDir(), "test.db")}
+	db, err := OpenDB(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, cfg
+}
+
+func TestMigrationsMatchEntSchema(t *testing.T) {
+	ctx := context.Background()
+	db, cfg := openTestDB(t)
+
+	statements, err := SchemaDrift(ctx, db, cfg.Driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) == 0 {
+		t.Fatal("SchemaDrift() on an empty database reported no drift")
+	}
+
+	migrator, err := NewMigrator(db, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if statements, err = SchemaDrift(ctx, db, cfg.Driver); err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) != 0 {
+		t.Errorf("SchemaDrift() after migrating = %q, want none", statements)
+	}
+
+	// A column ent does not know about is drift as well
+	if _, err := db.Exec("ALTER TABLE users ADD COLUMN nickname text"); err != nil {
+		t.Fatal(err)
+	}
+	if statements, err = SchemaDrift(ctx, db, cfg.Driver); err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) == 0 {
+		t.Error("SchemaDrift() with an extra column reported no drift")
+	}
+}
+
+func TestNewMigrator_Errors(t *testing.T) {
+	db, cfg := openTestDB(t)
+	if _, err := NewMigrator(db, config.Database{Driver: "oracle"}); err == nil {
+		t.Error("NewMigrator(oracle) error = nil")
+	}
+	cfg.Driver = "postgres"
+	cfg.Migrations.LockTimeout = "soon"
+	if _, err := NewMigrator(db, cfg); err == nil {
+		t.Error("NewMigrator() with an invalid lock timeout error = nil")
+	}
+}
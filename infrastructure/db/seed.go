@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is a seed data file: rows to insert into a table, keyed by column name.
+type Fixture struct {
+	Table string                   `yaml:"table"`
+	Rows  []map[string]interface{} `yaml:"rows"`
+}
+
+// Seed inserts the rows of every *.yaml fixture in fsys, in file name order, in a single
+// transaction. It returns the number of inserted rows.
+func Seed(ctx context.Context, db *sql.DB, driver string, fsys fs.FS) (int, error) {
+	entDialect, err := entDialect(driver)
+	if err != nil {
+		return 0, err
+	}
+	files, err := fs.Glob(fsys, "*.yaml")
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(files)
+
+	var fixtures []Fixture
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read fixture %s: %w", file, err)
+		}
+		var f Fixture
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return 0, fmt.Errorf("failed to parse fixture %s: %w", file, err)
+		}
+		if f.Table == "" {
+			return 0, fmt.Errorf("fixture %s has no table", path.Base(file))
+		}
+		fixtures = append(fixtures, f)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	count := 0
+	for _, f := range fixtures {
+		for i, row := range f.Rows {
+			columns := make([]string, 0, len(row))
+			for column := range row {
+				columns = append(columns, column)
+			}
+			sort.Strings(columns)
+			values := make([]interface{}, len(columns))
+			for j, column := range columns {
+				values[j] = row[column]
+			}
+
+			query, args := entsql.Dialect(entDialect).Insert(f.Table).Columns(columns...).Values(values...).Query()
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return 0, fmt.Errorf("failed to insert row %d into %s: %w", i+1, f.Table, err)
+			}
+			count++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func entDialect(driver string) (string, error) {
+	switch driver {
+	case "postgres":
+		return dialect.Postgres, nil
+	case "mysql", "mariadb":
+		return dialect.MySQL, nil
+	case "sqlite3":
+		return dialect.SQLite, nil
+	default:
+		return "", fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	db, cfg := openTestDB(t)
+	if _, err := db.Exec("CREATE TABLE items (id integer PRIMARY KEY, name text NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := fstest.MapFS{
+		"001_items.yaml": {Data: []byte("table: items\nrows:\n  - id: 1\n    name: first\n  - id: 2\n    name: second\n")},
+		"README.md":      {Data: []byte("not a fixture")},
+	}
+	count, err := Seed(ctx, db, cfg.Driver, fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Seed() = %d, want 2", count)
+	}
+
+	// A failing row rolls back the whole seed
+	delete(fixtures, "001_items.yaml")
+	fixtures["002_items.yaml"] = &fstest.MapFile{Data: []byte("table: items\nrows:\n  - id: 3\n    name: third\n  - id: 1\n    name: duplicate\n")}
+	if _, err := Seed(ctx, db, cfg.Driver, fixtures); err == nil {
+		t.Fatal("Seed() with a duplicate key error = nil")
+	}
+	var rows int
+	if err := db.QueryRow("SELECT COUNT(*) FROM items").Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 2 {
+		t.Errorf("items after failed seed = %d, want 2", rows)
+	}
+}
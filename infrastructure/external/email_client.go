@@ -1,6 +1,7 @@
 package external
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
@@ -9,7 +10,7 @@ import (
 
 // EmailClient defines the interface for sending emails.
 type EmailClient interface {
-	SendEmail(to, subject, body string) error
+	SendEmail(ctx context.Context, to, subject, body string) error
 }
 
 // NewEmailClient creates a new email client based on configuration.
@@ -0,0 +1,47 @@
+package external
+
+import (
+	"context"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/azahir21/go-backend-boilerplate/infrastructure/external")
+
+var (
+	emailSends = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "email_sends_total",
+		Help: "Number of email send attempts by provider (smtp, sendgrid) and outcome (success, failure).",
+	}, []string{"provider", "outcome"})
+
+	emailSendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "email_send_duration_seconds",
+		Help:    "Email send latency by provider.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider"})
+)
+
+// startSend starts the client span of an email send. The recipient is not recorded.
+func startSend(ctx context.Context, provider string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "email.send",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("email.provider", provider)),
+	)
+}
+
+// observeSend records a send in the email metrics and ends its span.
+func observeSend(span trace.Span, provider string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	emailSends.WithLabelValues(provider, outcome).Inc()
+	emailSendDuration.WithLabelValues(provider).Observe(time.Since(start).Seconds())
+	tracing.End(span, err)
+}
@@ -1,7 +1,9 @@
 package external
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
 	"github.com/sendgrid/sendgrid-go"
@@ -26,12 +28,15 @@ func NewSendGridClient(cfg config.SendGridConfig) (EmailClient, error) {
 }
 
 // SendEmail sends an email using SendGrid.
-func (s *SendGridClient) SendEmail(to, subject, body string) error {
+func (s *SendGridClient) SendEmail(ctx context.Context, to, subject, body string) (err error) {
+	ctx, span := startSend(ctx, "sendgrid")
+	defer func(start time.Time) { observeSend(span, "sendgrid", start, err) }(time.Now())
+
 	from := mail.NewEmail("", s.cfg.From)
 	toEmail := mail.NewEmail("", to)
 
 	message := mail.NewSingleEmail(from, subject, toEmail, body, body)
-	response, err := s.client.Send(message)
+	response, err := s.client.SendWithContext(ctx, message)
 	if err != nil {
 		return fmt.Errorf("failed to send email via SendGrid: %w", err)
 	}
@@ -1,8 +1,11 @@
 package external
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/smtp"
+	"time"
 
 	"github.com/azahir21/go-backend-boilerplate/pkg/config"
 )
@@ -21,16 +24,40 @@ func NewSmtpClient(cfg config.SmtpConfig) (EmailClient, error) {
 }
 
 // SendEmail sends an email using SMTP.
-func (s *SmtpClient) SendEmail(to, subject, body string) error {
+func (s *SmtpClient) SendEmail(ctx context.Context, to, subject, body string) (err error) {
+	_, span := startSend(ctx, "smtp")
+	defer func(start time.Time) { observeSend(span, "smtp", start, err) }(time.Now())
+
 	addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
 	auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
 
 	msg := []byte(fmt.Sprintf("To: %s\r\nSubject: %s\r\n\r\n%s", to, subject, body))
 
-	err := smtp.SendMail(addr, auth, s.cfg.From, []string{to}, msg)
+	err = smtp.SendMail(addr, auth, s.cfg.From, []string{to}, msg)
 	if err != nil {
 		return fmt.Errorf("failed to send email via SMTP: %w", err)
 	}
 
 	return nil
 }
+
+// HealthCheck connects to the SMTP server and completes the greeting, without authenticating.
+func (s *SmtpClient) HealthCheck(ctx context.Context) error {
+	addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	return client.Quit()
+}
@@ -0,0 +1,19 @@
+package idempotency
+
+import (
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/sirupsen/logrus"
+)
+
+// NewStore creates a Store backed by the configured cache.
+// A redis cache gives records shared by all replicas. Other caches (ristretto may
+// evict entries under memory pressure) or no cache fall back to an in-memory store.
+func NewStore(log *logrus.Logger, appCache cache.Cache) Store {
+	if redisCache, ok := appCache.(*cache.RedisCache); ok {
+		log.Info("Idempotency store initialized with redis backend")
+		return NewRedisStore(redisCache.Client())
+	}
+
+	log.Info("Idempotency store initialized with in-memory backend")
+	return NewMemoryStore()
+}
@@ -0,0 +1,30 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record is the stored state of an idempotent request.
+// A record is created in-flight when the key is reserved and completed
+// with the recorded response once the handler finishes.
+type Record struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Store persists idempotency records.
+type Store interface {
+	// Reserve atomically claims key for a request with the given fingerprint.
+	// If the key is free it is reserved for lockTTL and (nil, true) is returned.
+	// Otherwise the existing record is returned with false.
+	Reserve(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, bool, error)
+	// Complete stores the final response for key, keeping it for ttl.
+	Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error
+	// Release removes a reservation so that the request can be retried.
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,75 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval controls how often expired records are evicted from memory.
+const sweepInterval = time.Minute
+
+// MemoryStore is an in-process Store. Records are not shared between replicas.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]memoryRecord
+	lastSweep time.Time
+}
+
+type memoryRecord struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates a new in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]memoryRecord), lastSweep: time.Now()}
+}
+
+// Reserve implements Store.
+func (m *MemoryStore) Reserve(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	if existing, ok := m.records[key]; ok && now.Before(existing.expiresAt) {
+		record := existing.record
+		return &record, false, nil
+	}
+
+	m.records[key] = memoryRecord{record: Record{Fingerprint: fingerprint}, expiresAt: now.Add(lockTTL)}
+	return nil, true, nil
+}
+
+// Complete implements Store.
+func (m *MemoryStore) Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[key] = memoryRecord{record: *record, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Release implements Store.
+func (m *MemoryStore) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records, key)
+	return nil
+}
+
+// sweep evicts expired records. Callers must hold m.mu.
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	for key, r := range m.records {
+		if now.After(r.expiresAt) {
+			delete(m.records, key)
+		}
+	}
+	m.lastSweep = now
+}
@@ -0,0 +1,78 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces idempotency keys in redis.
+const keyPrefix = "idempotency:"
+
+// RedisStore is a Store shared by all replicas, using SET NX for reservations.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a store that shares the given redis client.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Reserve implements Store.
+func (r *RedisStore) Reserve(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, bool, error) {
+	data, err := json.Marshal(&Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	// Retry once when the existing record expires between SET NX and GET.
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := r.client.SetNX(ctx, keyPrefix+key, data, lockTTL).Result()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to reserve idempotency key %s: %w", key, err)
+		}
+		if ok {
+			return nil, true, nil
+		}
+
+		raw, err := r.client.Get(ctx, keyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get idempotency key %s: %w", key, err)
+		}
+
+		var existing Record
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal idempotency record %s: %w", key, err)
+		}
+		return &existing, false, nil
+	}
+	return nil, false, fmt.Errorf("failed to reserve idempotency key %s: key is churning", key)
+}
+
+// Complete implements Store.
+func (r *RedisStore) Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+	if err := r.client.Set(ctx, keyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotency key %s: %w", key, err)
+	}
+	return nil
+}
+
+// Release implements Store.
+func (r *RedisStore) Release(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, keyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key %s: %w", key, err)
+	}
+	return nil
+}
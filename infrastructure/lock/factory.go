@@ -0,0 +1,32 @@
+package lock
+
+import (
+	"database/sql"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/sirupsen/logrus"
+)
+
+// NewBackend creates a Backend shared by all replicas when possible: a redis cache gives
+// redis locks, a Postgres database advisory locks on its pool db and other SQL databases a
+// lock table. Without either, locks fall back to memory and only coordinate a single replica.
+func NewBackend(log *logrus.Logger, appCache cache.Cache, client *ent.Client, db *sql.DB, driver string) Backend {
+	if redisCache, ok := appCache.(*cache.RedisCache); ok {
+		log.Info("Locks initialized with redis backend")
+		return NewRedisBackend(redisCache.Client())
+	}
+
+	if client != nil && driver == "postgres" {
+		log.Info("Locks initialized with Postgres advisory lock backend")
+		return NewPostgresBackend(db, client)
+	}
+
+	if client != nil {
+		log.Info("Locks initialized with SQL backend")
+		return NewSQLBackend(client)
+	}
+
+	log.Info("Locks initialized with in-memory backend, not shared between replicas")
+	return NewMemoryBackend()
+}
@@ -0,0 +1,175 @@
+// Package lock hands out distributed locks to coordinate work across replicas. A lock is
+// held through a Lease, which expires after its TTL unless it is renewed, and which carries
+// a fencing token that increases with every acquisition of the lock: writes guarded by the
+// lock can pass it along so that stale holders are rejected.
+package lock
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+var (
+	// ErrLocked is returned by TryLock when another owner holds the lock.
+	ErrLocked = errors.New("lock is held by another owner")
+	// ErrNotHeld is returned when renewing or releasing a lease that has expired or was
+	// released.
+	ErrNotHeld = errors.New("lock is not held")
+)
+
+// Backend stores the locks. Each acquisition is identified by a random owner token, so that
+// only its owner can renew or release it.
+type Backend interface {
+	// Acquire takes the lock name for token until ttl elapses. It returns the fencing token of
+	// the acquisition, or ErrLocked if another owner holds the lock.
+	Acquire(ctx context.Context, name, token string, ttl time.Duration) (int64, error)
+	// Renew extends the lock held by token by ttl, or returns ErrNotHeld.
+	Renew(ctx context.Context, name, token string, ttl time.Duration) error
+	// Release frees the lock held by token, or returns ErrNotHeld.
+	Release(ctx context.Context, name, token string) error
+}
+
+const (
+	defaultRetryInterval = 100 * time.Millisecond
+	maxRetryInterval     = time.Second
+)
+
+// Locker acquires locks from a backend.
+type Locker struct {
+	backend       Backend
+	retryInterval time.Duration
+}
+
+// Option configures a Locker.
+type Option func(*Locker)
+
+// WithRetryInterval sets the first wait of Lock between attempts; it doubles up to one
+// second.
+func WithRetryInterval(d time.Duration) Option {
+	return func(l *Locker) {
+		if d > 0 {
+			l.retryInterval = d
+		}
+	}
+}
+
+// New creates a locker on the given backend.
+func New(backend Backend, opts ...Option) *Locker {
+	l := &Locker{backend: backend, retryInterval: defaultRetryInterval}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Backend returns the backend of the locker.
+func (l *Locker) Backend() Backend {
+	return l.backend
+}
+
+// TryLock acquires the lock name for ttl without waiting, or returns ErrLocked.
+func (l *Locker) TryLock(ctx context.Context, name string, ttl time.Duration) (*Lease, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("invalid ttl %v for lock %s", ttl, name)
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	fence, err := l.backend.Acquire(ctx, name, token, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &Lease{backend: l.backend, name: name, token: token, fence: fence, ttl: ttl}, nil
+}
+
+// Lock acquires the lock name for ttl, waiting until it is free or ctx is done.
+func (l *Locker) Lock(ctx context.Context, name string, ttl time.Duration) (*Lease, error) {
+	wait := l.retryInterval
+	for {
+		lease, err := l.TryLock(ctx, name, ttl)
+		if !errors.Is(err, ErrLocked) {
+			return lease, err
+		}
+		// Jitter spreads out the retries of waiters that started together
+		timer := time.NewTimer(wait/2 + rand.N(wait/2+1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("failed to acquire lock %s: %w", name, ctx.Err())
+		case <-timer.C:
+		}
+		wait = min(wait*2, maxRetryInterval)
+	}
+}
+
+// Lease is a held lock.
+type Lease struct {
+	backend Backend
+	name    string
+	token   string
+	fence   int64
+	ttl     time.Duration
+}
+
+// Name returns the name of the lock.
+func (l *Lease) Name() string { return l.name }
+
+// Token returns the owner token of the lease.
+func (l *Lease) Token() string { return l.token }
+
+// Fence returns the fencing token of the lease, greater than that of every earlier
+// acquisition of the lock.
+func (l *Lease) Fence() int64 { return l.fence }
+
+// Renew extends the lease by its TTL, or returns ErrNotHeld if it was lost.
+func (l *Lease) Renew(ctx context.Context) error {
+	return l.backend.Renew(ctx, l.name, l.token, l.ttl)
+}
+
+// Release frees the lock, or returns ErrNotHeld if the lease was lost in the meantime.
+func (l *Lease) Release(ctx context.Context) error {
+	return l.backend.Release(ctx, l.name, l.token)
+}
+
+// KeepAlive renews the lease every third of its TTL until the returned cancel function is
+// called. The returned context is done when the lease is lost, so work guarded by the lock
+// should run with it. Renewals failing for a whole TTL count as a loss.
+func (l *Lease) KeepAlive(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer cancel()
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+		renewed := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			err := l.Renew(ctx)
+			switch {
+			case err == nil:
+				renewed = time.Now()
+			case errors.Is(err, ErrNotHeld), time.Since(renewed) >= l.ttl:
+				return
+			}
+		}
+	}()
+	return ctx, cancel
+}
+
+// newToken returns a random owner token.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := crand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
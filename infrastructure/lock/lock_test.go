@@ -0,0 +1,196 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent/enttest"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestLocker() (*Locker, *MemoryBackend, *time.Time) {
+	now := time.Unix(1000, 0)
+	backend := NewMemoryBackend()
+	backend.now = func() time.Time { return now }
+	return New(backend, WithRetryInterval(time.Millisecond)), backend, &now
+}
+
+func TestLocker_TryLock(t *testing.T) {
+	ctx := context.Background()
+	l, _, now := newTestLocker()
+
+	first, err := l.TryLock(ctx, "report", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.TryLock(ctx, "report", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Fatalf("TryLock() of a held lock error = %v, want ErrLocked", err)
+	}
+	if _, err := l.TryLock(ctx, "invoice", time.Minute); err != nil {
+		t.Fatalf("TryLock() of another lock error = %v", err)
+	}
+
+	// The lease expires, another owner takes the lock with a greater fencing token
+	*now = now.Add(time.Minute)
+	second, err := l.TryLock(ctx, "report", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Fence() <= first.Fence() {
+		t.Errorf("fence = %d after %d, want it to increase", second.Fence(), first.Fence())
+	}
+	if err := first.Renew(ctx); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Renew() of an expired lease error = %v, want ErrNotHeld", err)
+	}
+	if err := first.Release(ctx); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Release() of an expired lease error = %v, want ErrNotHeld", err)
+	}
+	if err := second.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := l.TryLock(ctx, "report", time.Minute); err != nil {
+		t.Errorf("TryLock() of a released lock error = %v", err)
+	}
+}
+
+func TestLease_Renew(t *testing.T) {
+	ctx := context.Background()
+	l, _, now := newTestLocker()
+
+	lease, err := l.TryLock(ctx, "report", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(50 * time.Second)
+	if err := lease.Renew(ctx); err != nil {
+		t.Fatalf("Renew() error = %v", err)
+	}
+	*now = now.Add(50 * time.Second)
+	if _, err := l.TryLock(ctx, "report", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("TryLock() of a renewed lock error = %v, want ErrLocked", err)
+	}
+}
+
+func TestLocker_Lock(t *testing.T) {
+	ctx := context.Background()
+	l := New(NewMemoryBackend(), WithRetryInterval(time.Millisecond))
+
+	lease, err := l.TryLock(ctx, "report", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Lock(timeout, "report", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Lock() of a held lock error = %v, want the context error", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		lease.Release(ctx)
+	}()
+	if _, err := l.Lock(ctx, "report", time.Minute); err != nil {
+		t.Errorf("Lock() error = %v, want the lock once it is released", err)
+	}
+}
+
+func TestLease_KeepAlive(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryBackend()
+	l := New(backend)
+
+	lease, err := l.TryLock(ctx, "report", 30*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, stop := lease.KeepAlive(ctx)
+	defer stop()
+	time.Sleep(100 * time.Millisecond)
+	if kept.Err() != nil {
+		t.Fatal("the context of a renewed lease is done")
+	}
+
+	// Another owner steals the lock: the lease is lost at the next renewal
+	backend.mu.Lock()
+	backend.locks["report"] = memoryLock{token: "thief", expiresAt: time.Now().Add(time.Minute)}
+	backend.mu.Unlock()
+	select {
+	case <-kept.Done():
+	case <-time.After(time.Second):
+		t.Fatal("the context of a lost lease is not done")
+	}
+}
+
+func TestSQLBackend(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	now := time.Now().UTC()
+	backend := NewSQLBackend(client)
+	backend.now = func() time.Time { return now }
+
+	fence, err := backend.Acquire(ctx, "report", "a", time.Minute)
+	if err != nil || fence != 1 {
+		t.Fatalf("Acquire() = %d, %v, want fence 1", fence, err)
+	}
+	if _, err := backend.Acquire(ctx, "report", "b", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Acquire() of a held lock error = %v, want ErrLocked", err)
+	}
+	if err := backend.Renew(ctx, "report", "b", time.Minute); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Renew() by another owner error = %v, want ErrNotHeld", err)
+	}
+	if err := backend.Renew(ctx, "report", "a", time.Minute); err != nil {
+		t.Errorf("Renew() error = %v", err)
+	}
+
+	// Released and expired locks are taken over with the next fencing token
+	if err := backend.Release(ctx, "report", "a"); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if err := backend.Release(ctx, "report", "a"); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Release() of a released lock error = %v, want ErrNotHeld", err)
+	}
+	if fence, err := backend.Acquire(ctx, "report", "b", time.Minute); err != nil || fence != 2 {
+		t.Fatalf("Acquire() of a released lock = %d, %v, want fence 2", fence, err)
+	}
+	now = now.Add(time.Minute)
+	if err := backend.Renew(ctx, "report", "b", time.Minute); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Renew() of an expired lease error = %v, want ErrNotHeld", err)
+	}
+	if fence, err := backend.Acquire(ctx, "report", "c", time.Minute); err != nil || fence != 3 {
+		t.Fatalf("Acquire() of an expired lock = %d, %v, want fence 3", fence, err)
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	if _, ok := NewBackend(log, nil, nil, nil, "").(*MemoryBackend); !ok {
+		t.Error("NewBackend() without a database is not the memory backend")
+	}
+	if _, ok := NewBackend(log, nil, client, nil, "sqlite3").(*SQLBackend); !ok {
+		t.Error("NewBackend() of a sqlite3 database is not the SQL backend")
+	}
+	backend, ok := NewBackend(log, nil, client, nil, "postgres").(*PostgresBackend)
+	if !ok {
+		t.Fatal("NewBackend() of a Postgres database is not the advisory lock backend")
+	}
+
+	// The advisory backend counts fencing tokens per lock in the lock table
+	for _, want := range []int64{1, 2} {
+		if fence, err := backend.nextFence(ctx, "report"); err != nil || fence != want {
+			t.Errorf("nextFence() = %d, %v, want %d", fence, err, want)
+		}
+	}
+	if fence, err := backend.nextFence(ctx, "invoice"); err != nil || fence != 1 {
+		t.Errorf("nextFence() of another lock = %d, %v, want 1", fence, err)
+	}
+}
@@ -0,0 +1,71 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryBackend is an in-process Backend, e.g. for tests and single-replica deployments.
+// Locks are not shared between replicas.
+type MemoryBackend struct {
+	mu     sync.Mutex
+	locks  map[string]memoryLock
+	fences map[string]int64
+	now    func() time.Time
+}
+
+type memoryLock struct {
+	token     string
+	expiresAt time.Time
+}
+
+// NewMemoryBackend creates a new in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		locks:  make(map[string]memoryLock),
+		fences: make(map[string]int64),
+		now:    time.Now,
+	}
+}
+
+// Acquire implements Backend.
+func (m *MemoryBackend) Acquire(ctx context.Context, name, token string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if l, ok := m.locks[name]; ok && now.Before(l.expiresAt) {
+		return 0, ErrLocked
+	}
+	m.locks[name] = memoryLock{token: token, expiresAt: now.Add(ttl)}
+	m.fences[name]++
+	return m.fences[name], nil
+}
+
+// Renew implements Backend.
+func (m *MemoryBackend) Renew(ctx context.Context, name, token string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	l, ok := m.locks[name]
+	if !ok || l.token != token || !now.Before(l.expiresAt) {
+		return ErrNotHeld
+	}
+	m.locks[name] = memoryLock{token: token, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Release implements Backend.
+func (m *MemoryBackend) Release(ctx context.Context, name, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.locks[name]
+	if !ok || l.token != token || !m.now().Before(l.expiresAt) {
+		return ErrNotHeld
+	}
+	delete(m.locks, name)
+	return nil
+}
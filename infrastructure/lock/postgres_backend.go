@@ -0,0 +1,152 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+)
+
+// PostgresBackend is a Backend on Postgres session-level advisory locks. Each held lock keeps
+// a connection of the pool for its session, which holds the lock until it is released or the
+// session ends, e.g. when the replica dies. Unlike SQLBackend it does not depend on the clocks
+// of the replicas, and the TTL does not apply: renewing checks that the session is still alive.
+// Fencing tokens are counted per lock in its lock_leases row, which only the holder writes.
+type PostgresBackend struct {
+	db     *sql.DB
+	client *ent.Client
+
+	mu       sync.Mutex
+	sessions map[string]*sql.Conn // by token
+}
+
+// NewPostgresBackend creates a backend on the connection pool of client.
+func NewPostgresBackend(db *sql.DB, client *ent.Client) *PostgresBackend {
+	return &PostgresBackend{db: db, client: client, sessions: make(map[string]*sql.Conn)}
+}
+
+// Acquire implements Backend.
+func (p *PostgresBackend) Acquire(ctx context.Context, name, token string, ttl time.Duration) (int64, error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+	var held bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", advisoryKey(name)).Scan(&held); err != nil {
+		discard(conn)
+		return 0, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+	if !held {
+		conn.Close()
+		return 0, ErrLocked
+	}
+	fence, err := p.nextFence(ctx, name)
+	if err != nil {
+		discard(conn)
+		return 0, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+
+	p.mu.Lock()
+	p.sessions[token] = conn
+	p.mu.Unlock()
+	return fence, nil
+}
+
+// nextFence increments the fencing counter of the lock, inserting its row on first use.
+func (p *PostgresBackend) nextFence(ctx context.Context, name string) (int64, error) {
+	l, err := p.client.LockLease.UpdateOneID(name).AddFence(1).Save(ctx)
+	if err == nil {
+		return l.Fence, nil
+	}
+	if !ent.IsNotFound(err) {
+		return 0, err
+	}
+	err = p.client.LockLease.Create().
+		SetID(name).
+		SetOwner("").
+		SetFence(1).
+		SetExpiresAt(time.Now().UTC()).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Renew implements Backend.
+func (p *PostgresBackend) Renew(ctx context.Context, name, token string, ttl time.Duration) error {
+	p.mu.Lock()
+	conn, ok := p.sessions[token]
+	p.mu.Unlock()
+	if !ok {
+		return ErrNotHeld
+	}
+	if err := conn.PingContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to renew lock %s: %w", name, err)
+		}
+		// The session, and with it the lock, is gone
+		p.forget(token)
+		discard(conn)
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// Release implements Backend.
+func (p *PostgresBackend) Release(ctx context.Context, name, token string) error {
+	conn := p.forget(token)
+	if conn == nil {
+		return ErrNotHeld
+	}
+	var held bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", advisoryKey(name)).Scan(&held); err != nil || !held {
+		// Ending the session releases whatever it still holds
+		discard(conn)
+		if err != nil {
+			return fmt.Errorf("failed to release lock %s: %w", name, err)
+		}
+		return ErrNotHeld
+	}
+	return conn.Close()
+}
+
+// Close ends the sessions of the held locks. The pool belongs to the ent client, which
+// closes it.
+func (p *PostgresBackend) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for token, conn := range p.sessions {
+		discard(conn)
+		delete(p.sessions, token)
+	}
+	return nil
+}
+
+// forget removes the session of token and returns it, or nil.
+func (p *PostgresBackend) forget(token string) *sql.Conn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conn := p.sessions[token]
+	delete(p.sessions, token)
+	return conn
+}
+
+// discard closes the session of conn instead of returning it to the pool, so that it cannot
+// leak an advisory lock to the next user of the connection.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
+
+// advisoryKey maps a lock name to the 64-bit key of its advisory lock.
+func advisoryKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
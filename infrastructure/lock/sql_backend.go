@@ -0,0 +1,87 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/locklease"
+)
+
+// SQLBackend is a Backend shared by all replicas, keeping a row per lock in the lock_leases
+// table of the application database. The row holds the owner token of the current lease and
+// the fencing counter of the lock, so it stays once the lock is released. Expiry is decided
+// with the clocks of the replicas, which must be synchronized.
+type SQLBackend struct {
+	client *ent.Client
+	now    func() time.Time
+}
+
+// NewSQLBackend creates a backend in the database of client.
+func NewSQLBackend(client *ent.Client) *SQLBackend {
+	return &SQLBackend{client: client, now: func() time.Time { return time.Now().UTC() }}
+}
+
+// Acquire takes over the row of the lock if it is free, and inserts it otherwise. Of
+// concurrent inserts only one passes the primary key.
+func (s *SQLBackend) Acquire(ctx context.Context, name, token string, ttl time.Duration) (int64, error) {
+	now := s.now()
+	l, err := s.client.LockLease.UpdateOneID(name).
+		Where(locklease.Or(locklease.OwnerEQ(""), locklease.ExpiresAtLTE(now))).
+		SetOwner(token).
+		SetExpiresAt(now.Add(ttl)).
+		AddFence(1).
+		Save(ctx)
+	if err == nil {
+		return l.Fence, nil
+	}
+	if !ent.IsNotFound(err) {
+		return 0, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+
+	err = s.client.LockLease.Create().
+		SetID(name).
+		SetOwner(token).
+		SetFence(1).
+		SetExpiresAt(now.Add(ttl)).
+		Exec(ctx)
+	if ent.IsConstraintError(err) {
+		return 0, ErrLocked
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+	return 1, nil
+}
+
+// Renew implements Backend.
+func (s *SQLBackend) Renew(ctx context.Context, name, token string, ttl time.Duration) error {
+	now := s.now()
+	n, err := s.client.LockLease.Update().
+		Where(locklease.ID(name), locklease.OwnerEQ(token), locklease.ExpiresAtGT(now)).
+		SetExpiresAt(now.Add(ttl)).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to renew lock %s: %w", name, err)
+	}
+	if n == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// Release implements Backend.
+func (s *SQLBackend) Release(ctx context.Context, name, token string) error {
+	n, err := s.client.LockLease.Update().
+		Where(locklease.ID(name), locklease.OwnerEQ(token), locklease.ExpiresAtGT(s.now())).
+		SetOwner("").
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", name, err)
+	}
+	if n == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package ratelimit
+
+import (
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/sirupsen/logrus"
+)
+
+// NewLimiter creates a Limiter backed by the configured cache.
+// A redis cache gives distributed counters shared by all replicas;
+// any other (or no) cache falls back to an in-memory limiter.
+func NewLimiter(log *logrus.Logger, appCache cache.Cache) Limiter {
+	if redisCache, ok := appCache.(*cache.RedisCache); ok {
+		log.Info("Rate limiter initialized with redis backend")
+		return NewRedisLimiter(redisCache.Client())
+	}
+
+	log.Info("Rate limiter initialized with in-memory backend")
+	return NewMemoryLimiter()
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Header names follow the IETF "RateLimit header fields for HTTP" draft.
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderPolicy     = "RateLimit-Policy"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// Headers returns the response headers describing the result of a rate limit check.
+func Headers(rule Rule, result Result) map[string]string {
+	headers := map[string]string{
+		HeaderLimit:     strconv.Itoa(result.Limit),
+		HeaderRemaining: strconv.Itoa(max(result.Remaining, 0)),
+		HeaderReset:     strconv.Itoa(ceilSeconds(result.ResetAfter)),
+		HeaderPolicy:    strconv.Itoa(rule.Limit) + ";w=" + strconv.Itoa(ceilSeconds(rule.Period)),
+	}
+	if !result.Allowed {
+		headers[HeaderRetryAfter] = strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1))
+	}
+	return headers
+}
+
+// ClientKey builds the counter key for a client of a route according to the rule's KeyBy.
+// When the preferred identity is unavailable (anonymous user, missing API key)
+// the client IP is used instead. Routes falling back to the default rule keep separate
+// counters.
+func ClientKey(route string, rule Rule, ip, userID, apiKey string) string {
+	var identity string
+	switch {
+	case rule.KeyBy == KeyByUser && userID != "":
+		identity = "user:" + userID
+	case rule.KeyBy == KeyByAPIKey && apiKey != "":
+		// Never store raw API keys in the counter backend.
+		sum := sha256.Sum256([]byte(apiKey))
+		identity = "key:" + hex.EncodeToString(sum[:8])
+	default:
+		identity = "ip:" + ip
+	}
+	return route + ":" + identity
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval controls how often idle keys are evicted from memory.
+const sweepInterval = time.Minute
+
+// MemoryLimiter is an in-process Limiter. Counters are not shared between replicas.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryEntry struct {
+	// token bucket state
+	tokens float64
+	last   time.Time
+	// sliding window state: timestamps of accepted requests, oldest first
+	hits []time.Time
+
+	expiresAt time.Time
+}
+
+// NewMemoryLimiter creates a new in-memory limiter.
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		entries:   make(map[string]*memoryEntry),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Allow implements Limiter.
+func (m *MemoryLimiter) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	entryKey := string(rule.Algorithm) + ":" + key
+	entry, ok := m.entries[entryKey]
+	if !ok {
+		entry = &memoryEntry{tokens: float64(rule.Burst), last: now}
+		m.entries[entryKey] = entry
+	}
+
+	var result Result
+	if rule.Algorithm == SlidingWindow {
+		result = entry.slidingWindow(now, rule)
+	} else {
+		result = entry.tokenBucket(now, rule)
+	}
+	return result, nil
+}
+
+func (e *memoryEntry) tokenBucket(now time.Time, rule Rule) Result {
+	interval := rule.refillInterval()
+	elapsed := now.Sub(e.last)
+	if elapsed > 0 {
+		e.tokens = math.Min(float64(rule.Burst), e.tokens+float64(elapsed)/float64(interval))
+		e.last = now
+	}
+
+	result := Result{Limit: rule.Burst}
+	if e.tokens >= 1 {
+		e.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - e.tokens) * float64(interval))
+	}
+	result.Remaining = int(e.tokens)
+	result.ResetAfter = time.Duration((float64(rule.Burst) - e.tokens) * float64(interval))
+	e.expiresAt = now.Add(result.ResetAfter)
+	return result
+}
+
+func (e *memoryEntry) slidingWindow(now time.Time, rule Rule) Result {
+	windowStart := now.Add(-rule.Period)
+	i := 0
+	for i < len(e.hits) && !e.hits[i].After(windowStart) {
+		i++
+	}
+	e.hits = e.hits[i:]
+
+	result := Result{Limit: rule.Limit}
+	if len(e.hits) < rule.Limit {
+		e.hits = append(e.hits, now)
+		result.Allowed = true
+	} else {
+		result.RetryAfter = e.hits[0].Add(rule.Period).Sub(now)
+	}
+	result.Remaining = rule.Limit - len(e.hits)
+	result.ResetAfter = e.hits[len(e.hits)-1].Add(rule.Period).Sub(now)
+	e.expiresAt = now.Add(rule.Period)
+	return result
+}
+
+// sweep evicts entries that have fully replenished. Callers must hold m.mu.
+func (m *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	for key, entry := range m.entries {
+		if now.After(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	m.lastSweep = now
+}
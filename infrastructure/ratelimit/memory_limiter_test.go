@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+)
+
+func newTestLimiter(start time.Time) (*MemoryLimiter, *time.Time) {
+	now := start
+	m := NewMemoryLimiter()
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestMemoryLimiter_TokenBucket(t *testing.T) {
+	m, now := newTestLimiter(time.Unix(1000, 0))
+	rule := Rule{Name: "test", Algorithm: TokenBucket, KeyBy: KeyByIP, Limit: 2, Period: 2 * time.Second, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		res, _ := m.Allow(context.Background(), "k", rule)
+		if !res.Allowed {
+			t.Fatalf("request %d should be allowed", i)
+		}
+	}
+
+	res, _ := m.Allow(context.Background(), "k", rule)
+	if res.Allowed {
+		t.Fatal("third request should be rejected")
+	}
+	if res.RetryAfter != time.Second {
+		t.Errorf("RetryAfter = %v, want %v", res.RetryAfter, time.Second)
+	}
+
+	*now = now.Add(time.Second)
+	res, _ = m.Allow(context.Background(), "k", rule)
+	if !res.Allowed {
+		t.Error("request should be allowed after one token refilled")
+	}
+	if res.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", res.Remaining)
+	}
+}
+
+func TestMemoryLimiter_SlidingWindow(t *testing.T) {
+	m, now := newTestLimiter(time.Unix(1000, 0))
+	rule := Rule{Name: "test", Algorithm: SlidingWindow, KeyBy: KeyByIP, Limit: 2, Period: time.Minute}
+
+	m.Allow(context.Background(), "k", rule)
+	*now = now.Add(30 * time.Second)
+	m.Allow(context.Background(), "k", rule)
+
+	res, _ := m.Allow(context.Background(), "k", rule)
+	if res.Allowed {
+		t.Fatal("third request inside the window should be rejected")
+	}
+	if res.RetryAfter != 30*time.Second {
+		t.Errorf("RetryAfter = %v, want %v", res.RetryAfter, 30*time.Second)
+	}
+
+	*now = now.Add(31 * time.Second)
+	res, _ = m.Allow(context.Background(), "k", rule)
+	if !res.Allowed {
+		t.Error("request should be allowed once the oldest hit left the window")
+	}
+
+	other, _ := m.Allow(context.Background(), "other", rule)
+	if !other.Allowed || other.Remaining != 1 {
+		t.Errorf("separate keys must not share counters, got %+v", other)
+	}
+}
+
+func TestPolicy_RuleFor(t *testing.T) {
+	policy, err := NewPolicy(config.RateLimitTransportConfig{
+		Enable:  true,
+		Default: config.RateLimitRule{Requests: 100, Period: "1m"},
+		Routes: []config.RateLimitRule{
+			{Match: "POST /api/v1/auth/login", Algorithm: "sliding_window", Requests: 5},
+			{Match: "GET /api/v1/ping", Disable: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+
+	rule, ok := policy.RuleFor("POST /api/v1/auth/login")
+	if !ok || rule.Algorithm != SlidingWindow || rule.Limit != 5 || rule.Period != time.Minute {
+		t.Errorf("route rule = %+v, want sliding_window 5/1m inheriting the default period", rule)
+	}
+
+	if _, ok := policy.RuleFor("GET /api/v1/ping"); ok {
+		t.Error("disabled route should not be limited")
+	}
+
+	rule, ok = policy.RuleFor("GET /api/v1/other")
+	if !ok || rule.Name != "default" || rule.Burst != 100 || rule.KeyBy != KeyByIP {
+		t.Errorf("default rule = %+v", rule)
+	}
+}
+
+func TestNewPolicy_Invalid(t *testing.T) {
+	_, err := NewPolicy(config.RateLimitTransportConfig{
+		Enable:  true,
+		Default: config.RateLimitRule{Requests: 10, Period: "1m", Algorithm: "leaky"},
+	})
+	if err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+
+	for _, rule := range []config.RateLimitRule{
+		{Requests: 0, Period: "1m"},
+		{Requests: 10, Period: "0s"},
+		{Requests: 10, Period: "-1m"},
+		{Requests: 2_000_000, Period: "1s"},
+	} {
+		if _, err := NewPolicy(config.RateLimitTransportConfig{Enable: true, Default: rule}); err == nil {
+			t.Errorf("NewPolicy(%+v) succeeded, want an error", rule)
+		}
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	rule := Rule{Name: "default", KeyBy: KeyByUser}
+	if a, b := ClientKey("GET /a", rule, "1.2.3.4", "", ""), ClientKey("GET /b", rule, "1.2.3.4", "", ""); a == b {
+		t.Errorf("routes sharing the default rule share the counter %s", a)
+	}
+	if got := ClientKey("GET /a", rule, "1.2.3.4", "7", ""); got != "GET /a:user:7" {
+		t.Errorf("ClientKey() = %s, want GET /a:user:7", got)
+	}
+	if got := ClientKey("GET /a", rule, "1.2.3.4", "", ""); got != "GET /a:ip:1.2.3.4" {
+		t.Errorf("ClientKey() of an anonymous user = %s, want the IP", got)
+	}
+}
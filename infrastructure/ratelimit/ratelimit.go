@@ -0,0 +1,176 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+)
+
+// Algorithm identifies a rate limiting algorithm.
+type Algorithm string
+
+const (
+	// TokenBucket refills Requests tokens per Period up to Burst tokens.
+	TokenBucket Algorithm = "token_bucket"
+	// SlidingWindow allows at most Requests in any rolling Period.
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+// KeyBy identifies how clients are told apart.
+type KeyBy string
+
+const (
+	KeyByIP     KeyBy = "ip"
+	KeyByUser   KeyBy = "user"
+	KeyByAPIKey KeyBy = "api_key"
+)
+
+// Rule is a parsed, validated rate limit.
+type Rule struct {
+	Name      string
+	Algorithm Algorithm
+	KeyBy     KeyBy
+	Limit     int
+	Period    time.Duration
+	Burst     int
+}
+
+// Result describes the outcome of a single Allow call.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the limit is fully replenished.
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request would be allowed (zero when allowed).
+	RetryAfter time.Duration
+}
+
+// Limiter counts requests for a key against a rule.
+type Limiter interface {
+	Allow(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// Policy resolves the rule that applies to a route of one transport.
+type Policy struct {
+	enabled  bool
+	fallback Rule
+	routes   map[string]*Rule
+}
+
+// NewPolicy parses the transport configuration into a Policy.
+func NewPolicy(cfg config.RateLimitTransportConfig) (*Policy, error) {
+	p := &Policy{enabled: cfg.Enable, routes: make(map[string]*Rule, len(cfg.Routes))}
+	if !cfg.Enable {
+		return p, nil
+	}
+
+	fallback, err := parseRule(cfg.Default, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default rate limit rule: %w", err)
+	}
+	fallback.Name = "default"
+	p.fallback = fallback
+
+	for _, r := range cfg.Routes {
+		if r.Match == "" {
+			return nil, fmt.Errorf("rate limit route rule is missing match")
+		}
+		if r.Disable {
+			p.routes[r.Match] = nil
+			continue
+		}
+		rule, err := parseRule(r, &fallback)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit rule for %q: %w", r.Match, err)
+		}
+		rule.Name = r.Match
+		p.routes[r.Match] = &rule
+	}
+	return p, nil
+}
+
+// RuleFor returns the rule for the given route and whether limiting applies at all.
+func (p *Policy) RuleFor(route string) (Rule, bool) {
+	if p == nil || !p.enabled {
+		return Rule{}, false
+	}
+	if rule, ok := p.routes[route]; ok {
+		if rule == nil {
+			return Rule{}, false
+		}
+		return *rule, true
+	}
+	return p.fallback, true
+}
+
+// parseRule converts a config rule, inheriting unset fields from base when provided.
+func parseRule(r config.RateLimitRule, base *Rule) (Rule, error) {
+	rule := Rule{
+		Algorithm: Algorithm(r.Algorithm),
+		KeyBy:     KeyBy(r.KeyBy),
+		Limit:     r.Requests,
+		Burst:     r.Burst,
+	}
+	if r.Period != "" {
+		period, err := time.ParseDuration(r.Period)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid period: %w", err)
+		}
+		rule.Period = period
+	}
+
+	if base != nil {
+		if rule.Algorithm == "" {
+			rule.Algorithm = base.Algorithm
+		}
+		if rule.KeyBy == "" {
+			rule.KeyBy = base.KeyBy
+		}
+		if rule.Limit == 0 {
+			rule.Limit = base.Limit
+		}
+		if rule.Period == 0 {
+			rule.Period = base.Period
+		}
+	}
+
+	if rule.Algorithm == "" {
+		rule.Algorithm = TokenBucket
+	}
+	if rule.KeyBy == "" {
+		rule.KeyBy = KeyByIP
+	}
+	if rule.Burst <= 0 {
+		rule.Burst = rule.Limit
+	}
+
+	switch rule.Algorithm {
+	case TokenBucket, SlidingWindow:
+	default:
+		return Rule{}, fmt.Errorf("unsupported algorithm: %s", rule.Algorithm)
+	}
+	switch rule.KeyBy {
+	case KeyByIP, KeyByUser, KeyByAPIKey:
+	default:
+		return Rule{}, fmt.Errorf("unsupported key_by: %s", rule.KeyBy)
+	}
+	if rule.Limit <= 0 {
+		return Rule{}, fmt.Errorf("requests must be greater than zero")
+	}
+	if rule.Period <= 0 {
+		return Rule{}, fmt.Errorf("period must be greater than zero")
+	}
+	// Limiters count time in microseconds, so a shorter refill interval would round to zero
+	if rule.refillInterval() < time.Microsecond {
+		return Rule{}, fmt.Errorf("period must allow at least a microsecond per request")
+	}
+	return rule, nil
+}
+
+// refillInterval returns the time needed to regenerate a single token.
+func (r Rule) refillInterval() time.Duration {
+	return r.Period / time.Duration(r.Limit)
+}
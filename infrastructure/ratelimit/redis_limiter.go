@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces rate limit keys in redis.
+const keyPrefix = "ratelimit:"
+
+// tokenBucketScript refills and takes one token atomically.
+// KEYS[1] = bucket key; ARGV[1] = capacity; ARGV[2] = refill interval per token (microseconds).
+// Returns {allowed, remaining, retry_after_us, reset_after_us}.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+  tokens = capacity
+  ts = now
+end
+
+if now > ts then
+  tokens = math.min(capacity, tokens + (now - ts) / interval)
+  ts = now
+end
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) * interval)
+end
+
+local reset = math.ceil((capacity - tokens) * interval)
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.ceil(reset / 1000) + 1000)
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// slidingWindowScript records a hit in a sorted set if the window has room.
+// KEYS[1] = window key; ARGV[1] = limit; ARGV[2] = window (microseconds); ARGV[3] = unique member.
+// Returns {allowed, remaining, retry_after_us, reset_after_us}.
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+
+local allowed = 0
+local retry = 0
+if count < limit then
+  redis.call('ZADD', KEYS[1], now, ARGV[3])
+  count = count + 1
+  allowed = 1
+else
+  local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+  retry = tonumber(oldest[2]) + window - now
+end
+
+local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+local reset = tonumber(newest[2]) + window - now
+redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+return {allowed, limit - count, retry, reset}
+`)
+
+// RedisLimiter is a distributed Limiter backed by redis.
+type RedisLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisLimiter creates a limiter that shares the given redis client.
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+// Allow implements Limiter.
+func (r *RedisLimiter) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	redisKey := keyPrefix + string(rule.Algorithm) + ":" + key
+
+	var (
+		values []int64
+		err    error
+		limit  int
+	)
+	switch rule.Algorithm {
+	case SlidingWindow:
+		limit = rule.Limit
+		values, err = slidingWindowScript.Run(ctx, r.client, []string{redisKey},
+			rule.Limit, rule.Period.Microseconds(), uuid.NewString()).Int64Slice()
+	default:
+		limit = rule.Burst
+		values, err = tokenBucketScript.Run(ctx, r.client, []string{redisKey},
+			rule.Burst, rule.refillInterval().Microseconds()).Int64Slice()
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to evaluate rate limit for key %s: %w", key, err)
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result for key %s: %v", key, values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
@@ -9,7 +9,16 @@ import (
 )
 
 // NewStorage creates a new Storage implementation based on the provided configuration.
+// Operations are recorded in the storage_operation_duration_seconds metric and traced.
 func NewStorage(ctx context.Context, log *logrus.Logger, cfg config.StorageConfig) (Storage, error) {
+	backend, err := newBackend(ctx, log, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return instrument(backend, cfg.Type), nil
+}
+
+func newBackend(ctx context.Context, log *logrus.Logger, cfg config.StorageConfig) (Storage, error) {
 	switch cfg.Type {
 	case "local":
 		log.Info("Initializing Local Storage...")
@@ -89,3 +89,14 @@ func (g *GCSStorage) GetSignedURL(ctx context.Context, bucketName, objectName st
 
 	return url, nil
 }
+
+// HealthCheck verifies that the configured bucket exists and is accessible.
+func (g *GCSStorage) HealthCheck(ctx context.Context) error {
+	_, err := g.client.Bucket(g.bucket).Attrs(ctx)
+	return err
+}
+
+// Close closes the GCS client.
+func (g *GCSStorage) Close() error {
+	return g.client.Close()
+}
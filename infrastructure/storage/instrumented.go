@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/azahir21/go-backend-boilerplate/infrastructure/storage")
+
+var operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "storage_operation_duration_seconds",
+	Help:    "Storage operation latency by backend, operation and status (success, error).",
+	Buckets: prometheus.DefBuckets,
+}, []string{"backend", "operation", "status"})
+
+// instrumentedStorage records the latency and outcome of every operation of a backend in
+// storage_operation_duration_seconds and traces it as a client span, e.g. "storage.upload".
+type instrumentedStorage struct {
+	next    Storage
+	backend string
+}
+
+func instrument(next Storage, backend string) Storage {
+	return &instrumentedStorage{next: next, backend: backend}
+}
+
+func (s *instrumentedStorage) start(ctx context.Context, operation, bucketName, objectName string) (context.Context, trace.Span, time.Time) {
+	ctx, span := tracer.Start(ctx, "storage."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("storage.backend", s.backend),
+			attribute.String("storage.bucket", bucketName),
+			attribute.String("storage.object", objectName),
+		),
+	)
+	return ctx, span, time.Now()
+}
+
+func (s *instrumentedStorage) observe(span trace.Span, operation string, start time.Time, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	operationDuration.WithLabelValues(s.backend, operation, status).Observe(time.Since(start).Seconds())
+	tracing.End(span, err)
+}
+
+func (s *instrumentedStorage) Upload(ctx context.Context, bucketName, objectName string, file io.Reader) (string, error) {
+	ctx, span, start := s.start(ctx, "upload", bucketName, objectName)
+	location, err := s.next.Upload(ctx, bucketName, objectName, file)
+	s.observe(span, "upload", start, err)
+	return location, err
+}
+
+// Download measures the time to open the object, not to read it.
+func (s *instrumentedStorage) Download(ctx context.Context, bucketName, objectName string) (io.ReadCloser, error) {
+	ctx, span, start := s.start(ctx, "download", bucketName, objectName)
+	rc, err := s.next.Download(ctx, bucketName, objectName)
+	s.observe(span, "download", start, err)
+	return rc, err
+}
+
+func (s *instrumentedStorage) Delete(ctx context.Context, bucketName, objectName string) error {
+	ctx, span, start := s.start(ctx, "delete", bucketName, objectName)
+	err := s.next.Delete(ctx, bucketName, objectName)
+	s.observe(span, "delete", start, err)
+	return err
+}
+
+func (s *instrumentedStorage) GetSignedURL(ctx context.Context, bucketName, objectName string, method string, durationMinutes int) (string, error) {
+	ctx, span, start := s.start(ctx, "signed_url", bucketName, objectName)
+	url, err := s.next.GetSignedURL(ctx, bucketName, objectName, method, durationMinutes)
+	s.observe(span, "signed_url", start, err)
+	return url, err
+}
+
+// HealthCheck forwards to the backend's health check.
+func (s *instrumentedStorage) HealthCheck(ctx context.Context) error {
+	checker, ok := s.next.(interface{ HealthCheck(context.Context) error })
+	if !ok {
+		return errors.New("storage backend does not support health checks")
+	}
+	return checker.HealthCheck(ctx)
+}
+
+// Close closes the backend if it holds resources (e.g. the GCS client).
+func (s *instrumentedStorage) Close() error {
+	if closer, ok := s.next.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
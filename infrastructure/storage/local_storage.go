@@ -91,3 +91,15 @@ func (l *LocalStorage) GetSignedURL(ctx context.Context, bucketName, objectName
 	// In a real application, you might want to return an error or a URL to a local web server that serves this file.
 	return filePath, nil
 }
+
+// HealthCheck verifies that the base path is an existing directory.
+func (l *LocalStorage) HealthCheck(ctx context.Context) error {
+	info, err := os.Stat(l.basePath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", l.basePath)
+	}
+	return nil
+}
@@ -109,3 +109,9 @@ func (s *S3Storage) GetSignedURL(ctx context.Context, bucketName, objectName str
 
 	return urlStr, nil
 }
+
+// HealthCheck verifies that the configured bucket exists and is accessible.
+func (s *S3Storage) HealthCheck(ctx context.Context) error {
+	_, err := s.s3Client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucket)})
+	return err
+}
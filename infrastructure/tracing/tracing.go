@@ -0,0 +1,87 @@
+// Package tracing configures OpenTelemetry tracing: the global tracer provider, the exporter
+// selected by server.tracing.exporter and W3C trace-context propagation. Instrumented
+// packages obtain their tracer from the global provider and end their spans with End.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracerProvider creates the tracer provider and installs it, together with the W3C
+// trace-context and baggage propagators, as the global OpenTelemetry provider.
+// It returns nil when tracing is disabled; the caller shuts the provider down on exit
+// to flush pending spans.
+func NewTracerProvider(ctx context.Context, log *logrus.Logger, cfg config.TracingConfig) (*sdktrace.TracerProvider, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
+	}
+	switch cfg.Exporter {
+	case "otlp":
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OTLP.Endpoint)}
+		if cfg.OTLP.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		if len(cfg.OTLP.Headers) > 0 {
+			clientOpts = append(clientOpts, otlptracegrpc.WithHeaders(cfg.OTLP.Headers))
+		}
+		// The exporter connects lazily, so an unreachable collector does not prevent startup
+		exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+		log.Infof("Tracing enabled, exporting spans to %s", cfg.OTLP.Endpoint)
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+		log.Info("Tracing enabled, exporting spans to stdout")
+	case "none", "":
+		log.Info("Tracing enabled without exporter, spans are only used for log correlation")
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter: %s", cfg.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider, nil
+}
+
+// End records err on span, marking it as failed, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,270 @@
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+)
+
+// moduleData is the input of the templates.
+type moduleData struct {
+	// GoModule is the import path of the repository's Go module.
+	GoModule string
+	// Name is the snake_case entity name, e.g. "order_item".
+	Name string
+	// Package is the name of the module's packages and of its ent package, e.g. "orderitem".
+	Package string
+	// Type is the ent and domain type name, e.g. "OrderItem".
+	Type string
+	// Var is the name of variables holding an entity, e.g. "orderItem".
+	Var string
+	// Plural is the snake_case plural, e.g. "order_items", and the GraphQL list field.
+	Plural string
+	// PluralType is the plural used in type and method names, e.g. "OrderItems".
+	PluralType string
+	// PluralVar is the camelCase plural, e.g. "orderItems".
+	PluralVar string
+	// ProtoPlural is the Go name protoc derives for the repeated field of list responses.
+	ProtoPlural string
+	// Route is the REST collection path, e.g. "/order-items".
+	Route string
+	// Human and HumanPlural name the entity in messages, e.g. "order item".
+	Human       string
+	HumanPlural string
+	Fields      []fieldData
+}
+
+func newModuleData(goModule string, m Module) moduleData {
+	pluralName := snake(plural(pascal(m.Name)))
+	d := moduleData{
+		GoModule:    goModule,
+		Name:        m.Name,
+		Package:     PackageName(m.Name),
+		Type:        pascal(m.Name),
+		Var:         camel(m.Name),
+		Plural:      pluralName,
+		PluralType:  pascal(pluralName),
+		PluralVar:   camel(pluralName),
+		ProtoPlural: protoName(pluralName),
+		Route:       "/" + strings.ReplaceAll(pluralName, "_", "-"),
+		Human:       strings.ReplaceAll(m.Name, "_", " "),
+		HumanPlural: strings.ReplaceAll(pluralName, "_", " "),
+	}
+	for i, f := range m.Fields {
+		d.Fields = append(d.Fields, fieldData{
+			Field:     f,
+			GoName:    pascal(f.Name),
+			ProtoName: protoName(f.Name),
+			Number:    i + 2,
+		})
+	}
+	return d
+}
+
+// HasTime reports whether a field has the time type, so templates import the time package.
+func (d moduleData) HasTime() bool {
+	for _, f := range d.Fields {
+		if f.Type == Time {
+			return true
+		}
+	}
+	return false
+}
+
+// LastNumber is the proto field number of the last field of the entity message.
+func (d moduleData) LastNumber() int {
+	return len(d.Fields) + 1
+}
+
+// fieldData is a field with the names and types the templates need.
+type fieldData struct {
+	Field
+	// GoName is the name of the ent and domain struct field, e.g. "UserID".
+	GoName string
+	// ProtoName is the name of the generated protobuf struct field, e.g. "UserId".
+	ProtoName string
+	// Number is the proto field number in messages that start with the id.
+	Number int
+}
+
+// GoType is the Go type of the field.
+func (f fieldData) GoType() string {
+	switch f.Type {
+	case Int:
+		return "int"
+	case Int64:
+		return "int64"
+	case Float:
+		return "float64"
+	case Bool:
+		return "bool"
+	case Time:
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
+
+// EntFunc is the ent field builder, e.g. "String" for field.String.
+func (f fieldData) EntFunc() string {
+	switch f.Type {
+	case Text:
+		return "Text"
+	case Int:
+		return "Int"
+	case Int64:
+		return "Int64"
+	case Float:
+		return "Float"
+	case Bool:
+		return "Bool"
+	case Time:
+		return "Time"
+	default:
+		return "String"
+	}
+}
+
+// ProtoType is the protobuf type of the field.
+func (f fieldData) ProtoType() string {
+	switch f.Type {
+	case Int, Int64:
+		return "int64"
+	case Float:
+		return "double"
+	case Bool:
+		return "bool"
+	case Time:
+		return "google.protobuf.Timestamp"
+	default:
+		return "string"
+	}
+}
+
+// GraphQLType is the graphql-go scalar of the field.
+func (f fieldData) GraphQLType() string {
+	switch f.Type {
+	case Int, Int64:
+		return "graphql.Int"
+	case Float:
+		return "graphql.Float"
+	case Bool:
+		return "graphql.Boolean"
+	case Time:
+		return "graphql.DateTime"
+	default:
+		return "graphql.String"
+	}
+}
+
+// GraphQLGoType is the Go type graphql-go coerces an argument of the field to.
+func (f fieldData) GraphQLGoType() string {
+	switch f.Type {
+	case Int, Int64:
+		return "int"
+	case Float:
+		return "float64"
+	case Bool:
+		return "bool"
+	case Time:
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
+
+// ListType is the listquery field type.
+func (f fieldData) ListType() string {
+	switch f.Type {
+	case Int, Int64:
+		return "Int"
+	case Float:
+		return "Float"
+	case Bool:
+		return "Bool"
+	case Time:
+		return "Time"
+	default:
+		return "String"
+	}
+}
+
+// Sortable reports whether lists can be ordered by the field. Text columns are not indexable
+// on every database, so they only filter.
+func (f fieldData) Sortable() bool {
+	return f.Type != Text
+}
+
+// Binding is the struct tag suffix validating the field in REST requests. Booleans and
+// numbers are not required: their zero values are valid input.
+func (f fieldData) Binding() string {
+	if f.Optional {
+		return ""
+	}
+	switch f.Type {
+	case String, Text, Time:
+		return ` binding:"required"`
+	}
+	return ""
+}
+
+// ToProto converts the field of the domain value v to its protobuf value.
+func (f fieldData) ToProto(v string) string {
+	switch f.Type {
+	case Int:
+		return fmt.Sprintf("int64(%s.%s)", v, f.GoName)
+	case Time:
+		return fmt.Sprintf("timestamppb.New(%s.%s)", v, f.GoName)
+	}
+	return v + "." + f.GoName
+}
+
+// FromProto converts the field of the protobuf message v to its domain value.
+func (f fieldData) FromProto(v string) string {
+	switch f.Type {
+	case Int:
+		return fmt.Sprintf("int(%s.%s)", v, f.ProtoName)
+	case Time:
+		return fmt.Sprintf("%s.%s.AsTime()", v, f.ProtoName)
+	}
+	return v + "." + f.ProtoName
+}
+
+// FromGraphQL converts the GraphQL argument v to the field's domain value.
+func (f fieldData) FromGraphQL(v string) string {
+	if f.Type == Int64 {
+		return fmt.Sprintf("int64(%s)", v)
+	}
+	return v
+}
+
+// ListKey is the cursor key of the field of the domain value v.
+func (f fieldData) ListKey(v string) string {
+	if f.Type == Int {
+		return fmt.Sprintf("int64(%s.%s)", v, f.GoName)
+	}
+	return v + "." + f.GoName
+}
+
+// Sample is a literal of the field's type for test data; distinct n give distinct values.
+func (f fieldData) Sample(n int) string {
+	switch f.Type {
+	case Int, Int64:
+		return fmt.Sprint(n)
+	case Float:
+		return fmt.Sprintf("%d.5", n)
+	case Bool:
+		return fmt.Sprint(n%2 == 1)
+	case Time:
+		return fmt.Sprintf("time.Date(2025, 1, %d, 0, 0, 0, 0, time.UTC)", n)
+	default:
+		return fmt.Sprintf("%q", fmt.Sprintf("%s-%d", f.Name, n))
+	}
+}
+
+// Equal compares the field of the domain values a and b.
+func (f fieldData) Equal(a, b string) string {
+	if f.Type == Time {
+		return fmt.Sprintf("%s.%s.Equal(%s.%s)", a, f.GoName, b, f.GoName)
+	}
+	return fmt.Sprintf("%s.%s == %s.%s", a, f.GoName, b, f.GoName)
+}
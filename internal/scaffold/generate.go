@@ -0,0 +1,137 @@
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"title": title,
+	"add":   func(a, b int) int { return a + b },
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// outputs maps each template to the file it renders, relative to the repository root.
+// {name} is replaced by the snake_case name and {pkg} by the package name.
+var outputs = []struct{ template, path string }{
+	{"ent_schema.go.tmpl", "ent/schema/{pkg}.go"},
+	{"entity.go.tmpl", "internal/shared/entity/{name}.go"},
+	{"repository.go.tmpl", "internal/{pkg}/repository/{name}_repo.go"},
+	{"repository_impl.go.tmpl", "internal/{pkg}/repository/implementation/{name}_repo_impl.go"},
+	{"usecase.go.tmpl", "internal/{pkg}/usecase/{name}_usecase.go"},
+	{"usecase_test.go.tmpl", "internal/{pkg}/usecase/{name}_usecase_test.go"},
+	{"dto.go.tmpl", "internal/{pkg}/delivery/http/dto/{name}_dto.go"},
+	{"http_handler.go.tmpl", "internal/{pkg}/delivery/http/{name}_handler.go"},
+	{"module.proto.tmpl", "internal/{pkg}/delivery/grpc/proto/{name}.proto"},
+	{"grpc_handler.go.tmpl", "internal/{pkg}/delivery/grpc/{name}_handler.go"},
+	{"graphql_schema.go.tmpl", "internal/{pkg}/delivery/graphql/{name}_schema.go"},
+	{"graphql_resolver.go.tmpl", "internal/{pkg}/delivery/graphql/{name}_resolver.go"},
+	{"http_config.go.tmpl", "internal/{pkg}/config/http.config.go"},
+	{"grpc_config.go.tmpl", "internal/{pkg}/config/grpc.config.go"},
+	{"graphql_config.go.tmpl", "internal/{pkg}/config/graphql.config.go"},
+	{"module.go.tmpl", "internal/{pkg}/config/module.go"},
+}
+
+// registryFile is edited to wire a generated module into the application.
+const registryFile = "cmd/app/module_registery.go"
+
+// Generate writes the module into the repository rooted at root and imports it in the module
+// registry of the application. It refuses to overwrite existing files, and writes nothing
+// unless every file renders. It returns the created and edited files, relative to
+// root.
+func Generate(root string, m Module) ([]string, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	goModule, err := modulePath(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	d := newModuleData(goModule, m)
+
+	if _, err := os.Stat(filepath.Join(root, "internal", d.Package)); err == nil {
+		return nil, fmt.Errorf("internal/%s already exists", d.Package)
+	}
+	replacer := strings.NewReplacer("{name}", d.Name, "{pkg}", d.Package)
+	files := make(map[string][]byte)
+	var paths []string
+	for _, out := range outputs {
+		path := replacer.Replace(out.path)
+		if _, err := os.Stat(filepath.Join(root, path)); err == nil {
+			return nil, fmt.Errorf("%s already exists", path)
+		}
+		content, err := render(out.template, d)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		files[path] = content
+		paths = append(paths, path)
+	}
+
+	src, err := os.ReadFile(filepath.Join(root, registryFile))
+	if err != nil {
+		return nil, err
+	}
+	content, err := wireRegistry(src, d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wire %s: %w", registryFile, err)
+	}
+	files[registryFile] = content
+	paths = append(paths, registryFile)
+
+	for _, path := range paths {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(full, files[path], 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+func render(name string, d moduleData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, d); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return buf.Bytes(), nil
+	}
+	return format.Source(buf.Bytes())
+}
+
+// modulePath reads the module path from a go.mod file.
+func modulePath(gomod string) (string, error) {
+	f, err := os.Open(gomod)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%s not found; run the generator from the repository root", gomod)
+		}
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s has no module directive", gomod)
+}
@@ -0,0 +1,160 @@
+// Package scaffold generates a feature module shaped like the user module: ent schema,
+// domain entity, repository, usecase, REST, gRPC and GraphQL delivery, dependency-injection
+// configs and a usecase test, and wires the module into the application.
+package scaffold
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"entgo.io/ent/entc/gen"
+)
+
+// FieldType is the type of a module field.
+type FieldType string
+
+const (
+	String FieldType = "string"
+	Text   FieldType = "text"
+	Int    FieldType = "int"
+	Int64  FieldType = "int64"
+	Float  FieldType = "float"
+	Bool   FieldType = "bool"
+	Time   FieldType = "time"
+)
+
+var fieldTypes = []FieldType{String, Text, Int, Int64, Float, Bool, Time}
+
+// Field is a field of a generated module.
+type Field struct {
+	Name     string
+	Type     FieldType
+	Unique   bool
+	Optional bool
+}
+
+// Module describes the module to generate.
+type Module struct {
+	// Name is the snake_case singular name of the entity, e.g. "order_item".
+	Name   string
+	Fields []Field
+}
+
+// identifier matches snake_case names whose words start with a letter, so that ent, protoc
+// and the generator derive the same Go names.
+var identifier = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z][a-z0-9]*)*$`)
+
+// reserved are the fields every generated entity has.
+var reserved = map[string]bool{"id": true, "created_at": true, "updated_at": true}
+
+// reservedModules are names whose packages or variables would collide with the packages the
+// generated code imports, or with ent's generated packages.
+var reservedModules = map[string]bool{
+	"api": true, "apperr": true, "codes": true, "config": true, "context": true, "dto": true,
+	"emptypb": true, "ent": true, "enttest": true, "entity": true, "errors": true, "fmt": true,
+	"gin": true, "graphql": true, "grpc": true, "hook": true, "http": true, "httpresp": true,
+	"implementation": true, "listquery": true, "logrus": true, "middleware": true, "migrate": true,
+	"module": true, "predicate": true, "privacy": true, "proto": true, "repository": true,
+	"runtime": true, "schema": true, "status": true, "testing": true, "time": true,
+	"timestamppb": true, "unitofwork": true, "url": true, "usecase": true, "user": true,
+}
+
+// ParseFields parses field definitions of the form name:type[:unique][:optional], with type
+// one of string, text, int, int64, float, bool and time.
+func ParseFields(args []string) ([]Field, error) {
+	var fields []Field
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		parts := strings.Split(arg, ":")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("field %q: expected name:type", arg)
+		}
+		f := Field{Name: parts[0], Type: FieldType(parts[1])}
+		if !identifier.MatchString(f.Name) {
+			return nil, fmt.Errorf("field %q: name must be snake_case", arg)
+		}
+		if reserved[f.Name] {
+			return nil, fmt.Errorf("field %q: %s is generated for every module", arg, f.Name)
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("field %q: duplicate name", arg)
+		}
+		seen[f.Name] = true
+		if !validType(f.Type) {
+			return nil, fmt.Errorf("field %q: unknown type %q (want one of %s)", arg, f.Type, typeList())
+		}
+		for _, modifier := range parts[2:] {
+			switch modifier {
+			case "unique":
+				f.Unique = true
+			case "optional":
+				f.Optional = true
+			default:
+				return nil, fmt.Errorf("field %q: unknown modifier %q (want unique or optional)", arg, modifier)
+			}
+		}
+		if f.Unique && f.Type == Text {
+			return nil, fmt.Errorf("field %q: text fields cannot be unique; use string", arg)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func validType(t FieldType) bool {
+	for _, known := range fieldTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func typeList() string {
+	names := make([]string, len(fieldTypes))
+	for i, t := range fieldTypes {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (m Module) validate() error {
+	if !identifier.MatchString(m.Name) {
+		return fmt.Errorf("module name %q must be snake_case, e.g. order_item", m.Name)
+	}
+	if token.IsKeyword(PackageName(m.Name)) || token.IsKeyword(camel(m.Name)) {
+		return fmt.Errorf("module name %q is a Go keyword", m.Name)
+	}
+	if reservedModules[PackageName(m.Name)] || reservedModules[camel(m.Name)] {
+		return fmt.Errorf("module name %q collides with a package used by the generated code", m.Name)
+	}
+	if len(m.Fields) == 0 {
+		return fmt.Errorf("module %s needs at least one field", m.Name)
+	}
+	return nil
+}
+
+// Naming helpers of ent's code generator, so that generated code matches the names of the
+// generated ent types, setters and packages.
+var (
+	pascal = gen.Funcs["pascal"].(func(string) string)
+	camel  = gen.Funcs["camel"].(func(string) string)
+	snake  = gen.Funcs["snake"].(func(string) string)
+	plural = gen.Funcs["plural"].(func(string) string)
+)
+
+// PackageName is the name of the Go packages and of the ent package of the module name.
+func PackageName(name string) string {
+	return strings.ToLower(pascal(name))
+}
+
+// protoName is the Go name protoc-gen-go derives from a snake_case field name.
+func protoName(name string) string {
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, "")
+}
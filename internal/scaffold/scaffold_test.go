@@ -0,0 +1,172 @@
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields([]string{"sku:string:unique", "note:text:optional", "user_id:int"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Field{
+		{Name: "sku", Type: String, Unique: true},
+		{Name: "note", Type: Text, Optional: true},
+		{Name: "user_id", Type: Int},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ParseFields() = %+v, want %+v", fields, want)
+	}
+
+	for _, args := range [][]string{
+		{"name"},
+		{"Name:string"},
+		{"2fa:bool"},
+		{"id:int"},
+		{"name:string", "name:text"},
+		{"name:uuid"},
+		{"name:string:indexed"},
+		{"body:text:unique"},
+	} {
+		if _, err := ParseFields(args); err == nil {
+			t.Errorf("ParseFields(%q) succeeded, want an error", args)
+		}
+	}
+}
+
+func TestModuleValidate(t *testing.T) {
+	fields := []Field{{Name: "name", Type: String}}
+	if err := (Module{Name: "order_item", Fields: fields}).validate(); err != nil {
+		t.Errorf("validate() error = %v", err)
+	}
+	for _, m := range []Module{
+		{Name: "OrderItem", Fields: fields},
+		{Name: "type", Fields: fields},
+		{Name: "status", Fields: fields},
+		{Name: "product"},
+	} {
+		if err := m.validate(); err == nil {
+			t.Errorf("validate(%q) succeeded, want an error", m.Name)
+		}
+	}
+}
+
+func TestNewModuleData(t *testing.T) {
+	d := newModuleData("example.com/app", Module{Name: "order_item", Fields: []Field{{Name: "user_id", Type: Int}}})
+	got := []string{d.Package, d.Type, d.Var, d.Plural, d.PluralType, d.ProtoPlural, d.Route, d.Human}
+	want := []string{"orderitem", "OrderItem", "orderItem", "order_items", "OrderItems", "OrderItems", "/order-items", "order item"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("names = %q, want %q", got, want)
+	}
+	if f := d.Fields[0]; f.GoName != "UserID" || f.ProtoName != "UserId" {
+		t.Errorf("field names = %s, %s, want UserID, UserId", f.GoName, f.ProtoName)
+	}
+}
+
+// newRepo creates a repository with the files Generate edits.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":     "module example.com/app\n\ngo 1.24\n",
+		registryFile: filepath.Join("..", "..", registryFile),
+	}
+	for path, content := range files {
+		if path != "go.mod" {
+			src, err := os.ReadFile(content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content = string(src)
+		}
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestGenerate(t *testing.T) {
+	root := newRepo(t)
+	fields, err := ParseFields([]string{
+		"sku:string:unique", "note:text:optional", "quantity:int", "total:int64",
+		"price:float", "gift:bool", "shipped_at:time:optional",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Module{Name: "order_item", Fields: fields}
+
+	files, err := Generate(root, m)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(files) != len(outputs)+1 {
+		t.Errorf("Generate() wrote %d files, want %d", len(files), len(outputs)+1)
+	}
+	for _, path := range files {
+		if !strings.HasSuffix(path, ".go") {
+			continue
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(root, path), nil, 0); err != nil {
+			t.Errorf("%s does not parse: %v", path, err)
+		}
+	}
+
+	for path, want := range map[string][]string{
+		registryFile: {
+			`_ "example.com/app/internal/orderitem/config"`,
+		},
+		"internal/orderitem/config/module.go": {
+			`Name:     "order_item",`,
+			"unitofwork.RegisterRepository(orderItemRepoImpl.NewOrderItemRepository)",
+		},
+		"internal/orderitem/usecase/order_item_usecase.go": {
+			"unitofwork.Repo[repository.OrderItemRepository](txUow).Create(ctx, orderItem)",
+		},
+		"internal/orderitem/delivery/grpc/proto/order_item.proto": {
+			"google.protobuf.Timestamp shipped_at = 8;",
+			"google.protobuf.Timestamp updated_at = 10;",
+			"repeated OrderItem order_items = 1;",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range want {
+			if !strings.Contains(string(content), s) {
+				t.Errorf("%s does not contain %q", path, s)
+			}
+		}
+	}
+
+	if _, err := Generate(root, m); err == nil {
+		t.Error("second Generate() succeeded, want an error for existing files")
+	}
+}
+
+func TestGenerate_WritesNothingOnError(t *testing.T) {
+	root := newRepo(t)
+	if err := os.WriteFile(filepath.Join(root, registryFile), []byte("package app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Generate(root, Module{Name: "product", Fields: []Field{{Name: "name", Type: String}}})
+	if err == nil {
+		t.Fatal("Generate() succeeded without an import block in the registry")
+	}
+	if _, err := os.Stat(filepath.Join(root, "internal", "product")); !os.IsNotExist(err) {
+		t.Errorf("Generate() wrote the module despite failing: %v", err)
+	}
+}
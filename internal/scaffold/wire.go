@@ -0,0 +1,39 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// wireRegistry imports the module's config package, which registers the module.
+func wireRegistry(src []byte, d moduleData) ([]byte, error) {
+	src, err := addImport(src, fmt.Sprintf("_ %q", d.GoModule+"/internal/"+d.Package+"/config"))
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(src)
+}
+
+// addImport adds an import spec to the last group of the file's import block; gofmt then
+// sorts it into place.
+func addImport(src []byte, spec string) ([]byte, error) {
+	start := bytes.Index(src, []byte("\nimport ("))
+	if start < 0 {
+		return nil, fmt.Errorf("import block not found")
+	}
+	end := bytes.Index(src[start:], []byte("\n)"))
+	if end < 0 {
+		return nil, fmt.Errorf("import block not terminated")
+	}
+	at := start + end + 1
+	out := make([]byte, 0, len(src)+len(spec)+2)
+	out = append(out, src[:at]...)
+	out = append(out, "\t"+spec+"\n"...)
+	return append(out, src[at:]...), nil
+}
+
+func title(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,31 @@
+package graphql
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	operations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "graphql_operations_total",
+		Help: "Number of executed GraphQL operations by operation name and result (success, error).",
+	}, []string{"operation", "result"})
+
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "graphql_operation_duration_seconds",
+		Help:    "GraphQL execution latency by operation name.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+)
+
+// ObserveOperation records an executed operation. Operations whose result carries errors count as "error".
+func ObserveOperation(operation string, start time.Time, hasErrors bool) {
+	result := "success"
+	if hasErrors {
+		result = "error"
+	}
+	operations.WithLabelValues(operation, result).Inc()
+	operationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
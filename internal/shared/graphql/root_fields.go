@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// RootFields returns the root fields selected by the operation of a request as
+// "<operation type> <field>", e.g. "mutation login", the form rate limit rules match.
+// A field selected several times, e.g. under different aliases, is listed each time.
+// ok is false when the query does not parse or has no operation of that name; executing
+// it reports the error.
+func RootFields(query, operationName string) (fields []string, ok bool) {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil, false
+	}
+
+	var operation *ast.OperationDefinition
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			if operationName == "" && operation != nil {
+				// Several operations need a name to pick one
+				return nil, false
+			}
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		case *ast.FragmentDefinition:
+			fragments[def.Name.Value] = def
+		}
+	}
+	if operation == nil {
+		return nil, false
+	}
+
+	visited := make(map[string]bool)
+	var collect func(set *ast.SelectionSet)
+	collect = func(set *ast.SelectionSet) {
+		if set == nil {
+			return
+		}
+		for _, selection := range set.Selections {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				fields = append(fields, operation.Operation+" "+selection.Name.Value)
+			case *ast.InlineFragment:
+				collect(selection.SelectionSet)
+			case *ast.FragmentSpread:
+				// Fragments may spread each other in a cycle, which validation rejects later
+				name := selection.Name.Value
+				if fragment, found := fragments[name]; found && !visited[name] {
+					visited[name] = true
+					collect(fragment.SelectionSet)
+				}
+			}
+		}
+	}
+	collect(operation.SelectionSet)
+	return fields, len(fields) > 0
+}
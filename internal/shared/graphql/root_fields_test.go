@@ -0,0 +1,37 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRootFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		operation string
+		want      string
+	}{
+		{"shorthand query", `{ user(id: 1) { id } }`, "", "query user"},
+		{"aliases", `mutation Harmless { a: login(username: "x", password: "1") b: login(username: "x", password: "2") }`, "", "mutation login,mutation login"},
+		{"named operation", `query A { user(id: 1) { id } } mutation B { register(username: "x", email: "y", password: "z") { id } }`, "B", "mutation register"},
+		{"fragments", `mutation { ...F ... on Mutation { register(username: "x", email: "y", password: "z") { id } } } fragment F on Mutation { login(username: "x", password: "y") ...F }`, "", "mutation login,mutation register"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, ok := RootFields(tt.query, tt.operation)
+			if got := strings.Join(fields, ","); !ok || got != tt.want {
+				t.Errorf("RootFields() = %s, %v, want %s", got, ok, tt.want)
+			}
+		})
+	}
+
+	for _, query := range []string{`mutation {`, `query A { user } query B { user }`} {
+		if fields, ok := RootFields(query, ""); ok {
+			t.Errorf("RootFields(%q) = %v, want no fields", query, fields)
+		}
+	}
+	if fields, ok := RootFields(`query A { user }`, "B"); ok {
+		t.Errorf("RootFields() of a missing operation = %v, want no fields", fields)
+	}
+}
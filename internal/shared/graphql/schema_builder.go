@@ -11,15 +11,22 @@ type SchemaBuilder interface {
 }
 
 // NewRootSchema creates a new GraphQL schema by combining fields from multiple SchemaBuilders.
+// The resolvers of the root fields are traced.
 func NewRootSchema(builders []SchemaBuilder) (graphql.Schema, error) {
 	queryFields := make(graphql.Fields)
 	mutationFields := make(graphql.Fields)
 
 	for _, builder := range builders {
 		for name, field := range builder.BuildQueryFields() {
+			if field.Resolve != nil {
+				field.Resolve = traceResolver("query", name, field.Resolve)
+			}
 			queryFields[name] = field
 		}
 		for name, field := range builder.BuildMutationFields() {
+			if field.Resolve != nil {
+				field.Resolve = traceResolver("mutation", name, field.Resolve)
+			}
 			mutationFields[name] = field
 		}
 	}
@@ -29,15 +36,14 @@ func NewRootSchema(builders []SchemaBuilder) (graphql.Schema, error) {
 		Fields: queryFields,
 	})
 
-	rootMutation := graphql.NewObject(graphql.ObjectConfig{
-		Name:   "RootMutation",
-		Fields: mutationFields,
-	})
-
-	return graphql.NewSchema(
-		graphql.SchemaConfig{
-			Query:    rootQuery,
-			Mutation: rootMutation,
-		},
-	)
+	config := graphql.SchemaConfig{Query: rootQuery}
+	// Object types need at least one field, so the mutation type is left out when no module
+	// has mutations
+	if len(mutationFields) > 0 {
+		config.Mutation = graphql.NewObject(graphql.ObjectConfig{
+			Name:   "RootMutation",
+			Fields: mutationFields,
+		})
+	}
+	return graphql.NewSchema(config)
 }
@@ -0,0 +1,45 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/azahir21/go-backend-boilerplate/internal/shared/graphql")
+
+// StartOperation starts the span of a GraphQL operation as a child of the HTTP request span.
+func StartOperation(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "graphql "+operation, trace.WithAttributes(attribute.String("graphql.operation.name", operation)))
+}
+
+// EndOperation ends the span of an operation, marking it as failed when the result carries errors.
+func EndOperation(span trace.Span, result *graphql.Result) {
+	var err error
+	if result.HasErrors() {
+		err = errors.New(result.Errors[0].Message)
+		span.SetAttributes(attribute.Int("graphql.errors", len(result.Errors)))
+	}
+	tracing.End(span, err)
+}
+
+// traceResolver wraps the resolver of a root field in a span, e.g. "graphql.resolve query.user".
+func traceResolver(kind, name string, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	spanName := "graphql.resolve " + kind + "." + name
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx := p.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, span := tracer.Start(ctx, spanName, trace.WithAttributes(attribute.String("graphql.field.name", name)))
+		p.Context = ctx
+		result, err := resolve(p)
+		tracing.End(span, err)
+		return result, err
+	}
+}
@@ -0,0 +1,153 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadataKey is the metadata key used to propagate request IDs (lower-cased X-Request-ID).
+const requestIDMetadataKey = "x-request-id"
+
+type requestIDCtxKey struct{}
+
+// RequestIDFromContext returns the request ID attached by the access log interceptors, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return id
+}
+
+// AccessLogUnaryInterceptor logs every unary RPC through logrus. The user ID is taken from a
+// valid bearer token in the authorization metadata.
+func AccessLogUnaryInterceptor(log *logrus.Logger, cfg config.AccessLogConfig) grpclib.UnaryServerInterceptor {
+	al := newAccessLogger(log, cfg)
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = al.withRequestID(ctx)
+		resp, err := handler(ctx, req)
+		al.log(ctx, info.FullMethod, "unary", start, err)
+		return resp, err
+	}
+}
+
+// AccessLogStreamInterceptor logs every streaming RPC through logrus once the stream ends.
+func AccessLogStreamInterceptor(log *logrus.Logger, cfg config.AccessLogConfig) grpclib.StreamServerInterceptor {
+	al := newAccessLogger(log, cfg)
+	return func(srv interface{}, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		start := time.Now()
+		ctx := al.withRequestID(ss.Context())
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		al.log(ctx, info.FullMethod, "stream", start, err)
+		return err
+	}
+}
+
+type accessLogger struct {
+	logger *logrus.Logger
+	cfg    config.AccessLogConfig
+	skip   map[string]struct{}
+}
+
+func newAccessLogger(log *logrus.Logger, cfg config.AccessLogConfig) *accessLogger {
+	skip := make(map[string]struct{}, len(cfg.SkipPaths))
+	for _, p := range cfg.SkipPaths {
+		skip[p] = struct{}{}
+	}
+	return &accessLogger{logger: log, cfg: cfg, skip: skip}
+}
+
+// withRequestID reuses the incoming x-request-id metadata or generates a new ID,
+// stores it in the context and sends it back as a response header.
+func (a *accessLogger) withRequestID(ctx context.Context) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(requestIDMetadataKey); len(vals) > 0 && len(vals[0]) <= 128 {
+			requestID = vals[0]
+		}
+	}
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	_ = grpclib.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, requestID))
+	return context.WithValue(ctx, requestIDCtxKey{}, requestID)
+}
+
+func (a *accessLogger) log(ctx context.Context, fullMethod, kind string, start time.Time, err error) {
+	if !a.cfg.Enable {
+		return
+	}
+	if _, ok := a.skip[fullMethod]; ok {
+		return
+	}
+
+	code := status.Code(err)
+	if code == codes.OK && !logger.ShouldSample(a.cfg.SampleRate) {
+		return
+	}
+
+	service, method := splitFullMethod(fullMethod)
+	fields := logrus.Fields{
+		"transport":  "grpc",
+		"rpc_type":   kind,
+		"service":    service,
+		"method":     method,
+		"code":       code.String(),
+		"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+		"request_id": RequestIDFromContext(ctx),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields["client_ip"] = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ua := md.Get("user-agent"); len(ua) > 0 {
+		fields["user_agent"] = ua[0]
+	}
+	if claims, ok := bearerClaims(md); ok {
+		fields["user_id"] = claims.UserID
+	}
+	if a.cfg.LogHeaders {
+		fields["headers"] = logger.MaskValues(md, a.cfg.MaskHeaders)
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+
+	entry := a.logger.WithContext(ctx).WithFields(fields)
+	switch code {
+	case codes.OK:
+		entry.Info("gRPC request")
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.Unimplemented:
+		entry.Error("gRPC request")
+	default:
+		entry.Warn("gRPC request")
+	}
+}
+
+// splitFullMethod splits "/package.Service/Method" into service and method names.
+func splitFullMethod(fullMethod string) (string, string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "unknown", name
+}
+
+// wrappedStream overrides the context of a ServerStream.
+type wrappedStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/helper"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type testStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func incomingContext(pairs ...string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+	return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+}
+
+func TestAccessLogUnaryInterceptor(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	interceptor := AccessLogUnaryInterceptor(log, config.AccessLogConfig{
+		Enable:      true,
+		SampleRate:  0,
+		SkipPaths:   []string{"/grpc.health.v1.Health/Check"},
+		LogHeaders:  true,
+		MaskHeaders: []string{"authorization"},
+	})
+	call := func(ctx context.Context, method string, err error) string {
+		var requestID string
+		interceptor(ctx, nil, &grpclib.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			requestID = RequestIDFromContext(ctx)
+			return nil, err
+		})
+		return requestID
+	}
+
+	call(incomingContext(), "/grpc.health.v1.Health/Check", status.Error(codes.Unavailable, "down"))
+	if got := call(incomingContext(), "/user.UserService/GetUser", nil); got == "" {
+		t.Error("RequestIDFromContext() is empty, want a generated ID")
+	}
+	if n := len(hook.AllEntries()); n != 0 {
+		t.Fatalf("skipped and unsampled RPCs logged %d entries, want none", n)
+	}
+
+	helper.InitJWT("test-secret", 1)
+	token, err := helper.GenerateToken(7, "alice", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := incomingContext("x-request-id", "abc-123", "authorization", "Bearer "+token)
+	if got := call(ctx, "/user.UserService/GetUser", status.Error(codes.Internal, "boom")); got != "abc-123" {
+		t.Errorf("RequestIDFromContext() = %q, want the incoming ID", got)
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Level != logrus.ErrorLevel {
+		t.Fatalf("access log entry = %+v, want an error for codes.Internal", entry)
+	}
+	want := map[string]interface{}{
+		"rpc_type":   "unary",
+		"service":    "user.UserService",
+		"method":     "GetUser",
+		"code":       "Internal",
+		"request_id": "abc-123",
+		"client_ip":  "10.0.0.1:5000",
+		"user_id":    uint(7),
+	}
+	for key, value := range want {
+		if got := entry.Data[key]; got != value {
+			t.Errorf("%s = %v, want %v", key, got, value)
+		}
+	}
+	if got := entry.Data["headers"].(map[string]string)["authorization"]; got != "***" {
+		t.Errorf("authorization metadata = %q, want it masked", got)
+	}
+}
+
+func TestAccessLogStreamInterceptor(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	interceptor := AccessLogStreamInterceptor(log, config.AccessLogConfig{Enable: true, SampleRate: 1})
+
+	var requestID string
+	err := interceptor(nil, &testStream{ctx: incomingContext("x-request-id", "abc-123")}, &grpclib.StreamServerInfo{FullMethod: "/user.UserService/Watch"},
+		func(srv interface{}, ss grpclib.ServerStream) error {
+			requestID = RequestIDFromContext(ss.Context())
+			return errors.New("stream broke")
+		})
+	if err == nil {
+		t.Fatal("interceptor error = nil, want the handler error")
+	}
+	if requestID != "abc-123" {
+		t.Errorf("RequestIDFromContext() of the stream = %q, want the incoming ID", requestID)
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Level != logrus.ErrorLevel {
+		t.Fatalf("access log entry = %+v, want an error for codes.Unknown", entry)
+	}
+	if got := entry.Data["rpc_type"]; got != "stream" {
+		t.Errorf("rpc_type = %v, want stream", got)
+	}
+	if got := entry.Data["error"]; got != "stream broke" {
+		t.Errorf("error = %v, want the handler error", got)
+	}
+}
+
+func TestSplitFullMethod(t *testing.T) {
+	tests := []struct {
+		fullMethod, service, method string
+	}{
+		{"/user.UserService/GetUser", "user.UserService", "GetUser"},
+		{"GetUser", "unknown", "GetUser"},
+	}
+	for _, tt := range tests {
+		if service, method := splitFullMethod(tt.fullMethod); service != tt.service || method != tt.method {
+			t.Errorf("splitFullMethod(%q) = %q, %q, want %q, %q", tt.fullMethod, service, method, tt.service, tt.method)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/tlsconfig"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientIdentityUnaryInterceptor stores the identity of a verified client certificate in the
+// context, where handlers read it with tlsconfig.IdentityFromContext.
+func ClientIdentityUnaryInterceptor() grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		return handler(withClientIdentity(ctx), req)
+	}
+}
+
+// ClientIdentityStreamInterceptor is the streaming counterpart of ClientIdentityUnaryInterceptor.
+func ClientIdentityStreamInterceptor() grpclib.StreamServerInterceptor {
+	return func(srv interface{}, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: withClientIdentity(ss.Context())})
+	}
+}
+
+func withClientIdentity(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ctx
+	}
+	if id, ok := tlsconfig.IdentityFromState(&tlsInfo.State); ok {
+		return tlsconfig.ContextWithIdentity(ctx, id)
+	}
+	return ctx
+}
@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/health"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// HealthServer implements grpc.health.v1.Health on top of a health registry. The overall
+// status ("") and the status of every registered gRPC service follow readiness; the name of
+// a dependency (e.g. "database") reports that dependency alone.
+type HealthServer struct {
+	healthpb.UnimplementedHealthServer
+	registry *health.Registry
+	services map[string]bool
+	// interval is how often Watch re-evaluates the status.
+	interval time.Duration
+}
+
+// RegisterHealthServer registers the health service on server. Call it after all module
+// services are registered so that they can be queried by name. Watch streams re-evaluate
+// the status once per registry cache TTL.
+func RegisterHealthServer(server *grpclib.Server, registry *health.Registry) *HealthServer {
+	services := make(map[string]bool)
+	for name := range server.GetServiceInfo() {
+		services[name] = true
+	}
+	interval := registry.CacheTTL()
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	hs := &HealthServer{registry: registry, services: services, interval: interval}
+	healthpb.RegisterHealthServer(server, hs)
+	return hs
+}
+
+// Check returns the serving status of a service or dependency, or NOT_FOUND.
+func (s *HealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	serving, ok := s.status(ctx, req.GetService())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: serving}, nil
+}
+
+// List returns the status of the server, every gRPC service and every dependency.
+func (s *HealthServer) List(ctx context.Context, _ *healthpb.HealthListRequest) (*healthpb.HealthListResponse, error) {
+	report := s.registry.Check(ctx)
+	overall := servingStatus(report.Ready())
+
+	statuses := map[string]*healthpb.HealthCheckResponse{"": {Status: overall}}
+	for name := range s.services {
+		statuses[name] = &healthpb.HealthCheckResponse{Status: overall}
+	}
+	for name, result := range report.Checks {
+		statuses[name] = &healthpb.HealthCheckResponse{Status: servingStatus(result.Status == health.StatusUp)}
+	}
+	return &healthpb.HealthListResponse{Statuses: statuses}, nil
+}
+
+// Watch sends the current status and then every change, until the client cancels.
+func (s *HealthServer) Watch(req *healthpb.HealthCheckRequest, stream grpclib.ServerStreamingServer[healthpb.HealthCheckResponse]) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		serving, ok := s.status(stream.Context(), req.GetService())
+		if !ok {
+			serving = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+		if serving != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: serving}); err != nil {
+				return err
+			}
+			last = serving
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *HealthServer) status(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	if service == "" || s.services[service] {
+		return servingStatus(s.registry.Check(ctx).Ready()), true
+	}
+	result, ok := s.registry.CheckOne(ctx, service)
+	if !ok {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+	}
+	return servingStatus(result.Status == health.StatusUp), true
+}
+
+func servingStatus(up bool) healthpb.HealthCheckResponse_ServingStatus {
+	if up {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
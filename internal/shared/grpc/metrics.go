@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Number of completed RPCs by full method name and status code.",
+	}, []string{"method", "code"})
+
+	grpcHandlingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "RPC latency by full method name.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+// MetricsUnaryInterceptor records the count and latency of unary RPCs.
+func MetricsUnaryInterceptor() grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeRPC(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor records the count and duration of streaming RPCs.
+func MetricsStreamInterceptor() grpclib.StreamServerInterceptor {
+	return func(srv interface{}, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeRPC(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observeRPC(method string, start time.Time, err error) {
+	grpcHandled.WithLabelValues(method, status.Code(err).String()).Inc()
+	grpcHandlingDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
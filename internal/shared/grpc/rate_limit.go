@@ -0,0 +1,119 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/ratelimit"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/helper"
+	"github.com/sirupsen/logrus"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimiter applies a rate limit policy to RPCs, keyed by full method name.
+type RateLimiter struct {
+	log          *logrus.Logger
+	limiter      ratelimit.Limiter
+	policy       *ratelimit.Policy
+	apiKeyHeader string
+}
+
+// NewRateLimiter creates a RateLimiter for the gRPC policy.
+func NewRateLimiter(log *logrus.Logger, limiter ratelimit.Limiter, policy *ratelimit.Policy, apiKeyHeader string) *RateLimiter {
+	return &RateLimiter{log: log, limiter: limiter, policy: policy, apiKeyHeader: strings.ToLower(apiKeyHeader)}
+}
+
+// UnaryInterceptor rejects unary RPCs over the limit with codes.ResourceExhausted.
+func (r *RateLimiter) UnaryInterceptor() grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		if err := r.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor rejects new streams over the limit with codes.ResourceExhausted.
+func (r *RateLimiter) StreamInterceptor() grpclib.StreamServerInterceptor {
+	return func(srv interface{}, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		if err := r.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (r *RateLimiter) check(ctx context.Context, fullMethod string) error {
+	rule, ok := r.policy.RuleFor(fullMethod)
+	if !ok {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var userID, apiKey string
+	switch rule.KeyBy {
+	case ratelimit.KeyByUser:
+		userID = bearerUserID(md)
+	case ratelimit.KeyByAPIKey:
+		if vals := md.Get(r.apiKeyHeader); len(vals) > 0 {
+			apiKey = vals[0]
+		}
+	}
+	key := ratelimit.ClientKey(fullMethod, rule, peerIP(ctx), userID, apiKey)
+
+	result, err := r.limiter.Allow(ctx, key, rule)
+	if err != nil {
+		r.log.WithError(err).WithField("method", fullMethod).Warn("Rate limiter unavailable, allowing request")
+		return nil
+	}
+
+	headers := metadata.MD{}
+	for name, value := range ratelimit.Headers(rule, result) {
+		headers.Set(name, value)
+	}
+	_ = grpclib.SetHeader(ctx, headers)
+
+	if !result.Allowed {
+		return status.Error(codes.ResourceExhausted, "too many requests, please retry later")
+	}
+	return nil
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func bearerUserID(md metadata.MD) string {
+	claims, ok := bearerClaims(md)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(uint64(claims.UserID), 10)
+}
+
+// bearerClaims returns the claims of a valid bearer token in the authorization metadata.
+func bearerClaims(md metadata.MD) (*helper.JWTClaims, bool) {
+	vals := md.Get("authorization")
+	if len(vals) == 0 || !strings.HasPrefix(vals[0], "Bearer ") {
+		return nil, false
+	}
+	claims, err := helper.ValidateToken(strings.TrimPrefix(vals[0], "Bearer "))
+	if err != nil {
+		return nil, false
+	}
+	return claims, true
+}
@@ -0,0 +1,14 @@
+package grpc
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"google.golang.org/grpc/stats"
+)
+
+// TracingStatsHandler starts a server span per RPC, continuing the trace propagated in the
+// traceparent metadata. It runs before the interceptors, so that access log entries carry
+// the trace ID. Health checks are not traced.
+func TracingStatsHandler() stats.Handler {
+	return otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))
+}
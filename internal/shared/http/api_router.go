@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/azahir21/go-backend-boilerplate/pkg/apperr"
+	"github.com/azahir21/go-backend-boilerplate/pkg/httpresp"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type EndpointSpec struct {
@@ -21,6 +23,13 @@ type EndpointSpec struct {
 	Handler interface{}
 	// BindFrom: "", "json", "query" (default: auto by HTTP method)
 	BindFrom string
+	// Idempotent enables Idempotency-Key handling: a retried request with the same key
+	// replays the original response instead of running the handler again.
+	// Requires the server's idempotency middleware (server.http_server.idempotency.enable).
+	Idempotent bool
+	// Cache enables the server-side response cache for GET endpoints.
+	// Requires the server's response cache middleware (server.http_server.caching.response_cache).
+	Cache *CachePolicy
 }
 
 type APIRouterGroup struct {
@@ -34,6 +43,12 @@ func NewAPIRouterGroup(group *gin.RouterGroup) *APIRouterGroup {
 func (g *APIRouterGroup) Register(specs ...EndpointSpec) {
 	for _, s := range specs {
 		final := g.wrap(s)
+		if s.Cache != nil {
+			final = cached(s.Cache, final)
+		}
+		if s.Idempotent {
+			final = idempotent(final)
+		}
 		handlers := append(s.Middlewares, final)
 		g.group.Handle(s.Method, s.Path, handlers...)
 	}
@@ -41,6 +56,56 @@ func (g *APIRouterGroup) Register(specs ...EndpointSpec) {
 
 var ginContextType = reflect.TypeOf((*gin.Context)(nil))
 
+// idempotent applies the Idempotency installed by its middleware, if any.
+func idempotent(next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		i, ok := c.Get(idempotencyKey)
+		if !ok {
+			next(c)
+			return
+		}
+		i.(*Idempotency).wrap(c, next)
+	}
+}
+
+// cached applies the ResponseCache installed by its middleware, if any.
+func cached(policy *CachePolicy, next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rc, ok := c.Get(responseCacheKey)
+		if !ok {
+			next(c)
+			return
+		}
+		rc.(*ResponseCache).wrap(c, policy, next)
+	}
+}
+
+// hasBodyContentType reports whether the Content-Type names a body format that bindBody decodes.
+func hasBodyContentType(c *gin.Context) bool {
+	return strings.Contains(strings.ToLower(c.GetHeader("Content-Type")), "json") ||
+		httpresp.FormatOf(c.ContentType()) != httpresp.FormatJSON
+}
+
+// bindBody decodes the request body according to its Content-Type: JSON (default),
+// MessagePack, or protobuf (see httpresp.RegisterProto). It returns the format name for error messages.
+func bindBody(c *gin.Context, obj interface{}) (string, error) {
+	switch httpresp.FormatOf(c.ContentType()) {
+	case httpresp.FormatMsgPack:
+		return "MessagePack", c.ShouldBindWith(obj, binding.MsgPack)
+	case httpresp.FormatProtobuf:
+		body, err := c.GetRawData()
+		if err != nil {
+			return "protobuf", err
+		}
+		if err := httpresp.DecodeProto(body, obj); err != nil {
+			return "protobuf", err
+		}
+		return "protobuf", binding.Validator.ValidateStruct(obj)
+	default:
+		return "JSON", c.ShouldBindJSON(obj)
+	}
+}
+
 func (g *APIRouterGroup) wrap(s EndpointSpec) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		hv := reflect.ValueOf(s.Handler)
@@ -99,12 +164,12 @@ func (g *APIRouterGroup) wrap(s EndpointSpec) gin.HandlerFunc {
 						return
 					}
 				case "json", "body":
-					// only try JSON bind when there's a body / content-type indicates JSON
-					if c.Request.ContentLength == 0 && !strings.Contains(strings.ToLower(c.GetHeader("Content-Type")), "json") {
+					// only try body bind when there's a body / content-type indicates a supported format
+					if c.Request.ContentLength == 0 && !hasBodyContentType(c) {
 						continue
 					}
-					if err := c.ShouldBindJSON(reqPtr.Interface()); err != nil {
-						apperr.Respond(c, apperr.BadRequest("Invalid JSON body").WithCause(err))
+					if format, err := bindBody(c, reqPtr.Interface()); err != nil {
+						apperr.Respond(c, apperr.BadRequest("Invalid "+format+" body").WithCause(err))
 						return
 					}
 				default:
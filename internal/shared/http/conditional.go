@@ -0,0 +1,194 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalMiddleware adds ETag handling to successful GET and HEAD responses and answers
+// If-None-Match / If-Modified-Since with 304 Not Modified. Handlers may set their own ETag
+// or Last-Modified header (see SetLastModified); otherwise the ETag is a hash of the body.
+func ConditionalMiddleware(weak bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method != http.MethodGet && method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+
+		if writer.passthrough {
+			return
+		}
+		if writer.status != http.StatusOK {
+			writer.flush()
+			return
+		}
+
+		header := writer.Header()
+		etag := header.Get("ETag")
+		if etag == "" {
+			etag = computeETag(writer.body.Bytes(), weak)
+			header.Set("ETag", etag)
+		}
+
+		if notModified(c.Request, etag, header.Get("Last-Modified")) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			writer.ResponseWriter.WriteHeader(http.StatusNotModified)
+			writer.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		writer.flush()
+	}
+}
+
+// SetLastModified sets the Last-Modified header so that If-Modified-Since can be answered.
+func SetLastModified(c *gin.Context, t time.Time) {
+	if !t.IsZero() {
+		c.Header("Last-Modified", t.UTC().Format(http.TimeFormat))
+	}
+}
+
+func computeETag(body []byte, weak bool) string {
+	sum := sha256.Sum256(body)
+	tag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	if weak {
+		return "W/" + tag
+	}
+	return tag
+}
+
+// notModified evaluates the conditional headers as described in RFC 9110 section 13.2.2:
+// If-None-Match takes precedence and If-Modified-Since is only used without it.
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// etagMatches uses the weak comparison required for If-None-Match.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter holds the response until the handler chain finishes so that a
+// 304 can still replace it. Flushing (e.g. for streaming) disables buffering.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body        bytes.Buffer
+	status      int
+	written     bool
+	passthrough bool
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	w.written = true
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.WriteString(s)
+	}
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	if w.passthrough {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if w.passthrough {
+		return w.ResponseWriter.Size()
+	}
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	if w.passthrough {
+		return w.ResponseWriter.Written()
+	}
+	return w.written
+}
+
+func (w *bufferedWriter) Flush() {
+	if !w.passthrough {
+		w.flush()
+		w.passthrough = true
+	}
+	w.ResponseWriter.Flush()
+}
+
+// flush writes the buffered status and body to the client.
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	} else {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/health"
+	"github.com/azahir21/go-backend-boilerplate/pkg/httpresp"
+)
+
+// Health probe paths served by HealthHandler.
+const (
+	LivezPath   = "/livez"
+	ReadyzPath  = "/readyz"
+	HealthzPath = "/healthz"
+)
+
+// HealthHandler serves the health probes in front of next, bypassing the gin middlewares so
+// that probes are not rate limited, logged or compressed:
+//
+//   - /livez answers 200 while the process can serve requests.
+//   - /readyz answers 200 while all critical dependencies are up, 503 otherwise or once shutdown has begun.
+//   - /healthz returns the status and latency of every dependency, with 503 when the service is down.
+func HealthHandler(registry *health.Registry, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch r.URL.Path {
+		case LivezPath:
+			writeHealth(w, http.StatusOK, "alive", map[string]health.Status{"status": health.StatusUp})
+		case ReadyzPath:
+			report := registry.Check(r.Context())
+			if !report.Ready() {
+				message := "not ready"
+				if report.Draining {
+					message = "draining"
+				}
+				writeHealth(w, http.StatusServiceUnavailable, message, map[string]health.Status{"status": report.Status})
+				return
+			}
+			writeHealth(w, http.StatusOK, "ready", map[string]health.Status{"status": report.Status})
+		case HealthzPath:
+			report := registry.Check(r.Context())
+			status := http.StatusOK
+			if !report.Ready() {
+				status = http.StatusServiceUnavailable
+			}
+			writeHealth(w, status, "health report", report)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func writeHealth(w http.ResponseWriter, status int, message string, data interface{}) {
+	w.Header().Set("Content-Type", httpresp.MIMEJSON+"; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(httpresp.Response{
+		Status:  http.StatusText(status),
+		Message: message,
+		Data:    data,
+	})
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/health"
+)
+
+func TestHealthHandler(t *testing.T) {
+	var dbErr error
+	registry := health.NewRegistry(time.Second, 0)
+	registry.Register("database", health.CheckerFunc(func(ctx context.Context) error { return dbErr }), true)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := HealthHandler(registry, next)
+
+	probe := func(path string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	for path, want := range map[string]int{LivezPath: 200, ReadyzPath: 200, HealthzPath: 200, "/api/v1/ping": http.StatusTeapot} {
+		if got := probe(path); got != want {
+			t.Errorf("%s = %d, want %d", path, got, want)
+		}
+	}
+
+	dbErr = errors.New("connection refused")
+	for path, want := range map[string]int{LivezPath: 200, ReadyzPath: 503, HealthzPath: 503} {
+		if got := probe(path); got != want {
+			t.Errorf("database down: %s = %d, want %d", path, got, want)
+		}
+	}
+}
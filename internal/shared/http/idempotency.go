@@ -0,0 +1,152 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/idempotency"
+	"github.com/azahir21/go-backend-boilerplate/pkg/apperr"
+	"github.com/azahir21/go-backend-boilerplate/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client-generated key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKey       = "idempotency"
+	maxIdempotencyKeyLen = 255
+)
+
+// Idempotency applies Idempotency-Key semantics to endpoints registered with EndpointSpec.Idempotent.
+type Idempotency struct {
+	log         *logrus.Logger
+	store       idempotency.Store
+	ttl         time.Duration
+	lockTimeout time.Duration
+}
+
+// NewIdempotency creates the idempotency handler used by APIRouterGroup.
+func NewIdempotency(log *logrus.Logger, store idempotency.Store, ttl, lockTimeout time.Duration) *Idempotency {
+	return &Idempotency{log: log, store: store, ttl: ttl, lockTimeout: lockTimeout}
+}
+
+// Middleware makes the handler available to idempotent endpoints of every module.
+// Endpoints without EndpointSpec.Idempotent are unaffected.
+func (i *Idempotency) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(idempotencyKey, i)
+		c.Next()
+	}
+}
+
+// wrap runs next at most once per Idempotency-Key. Requests without the header run normally.
+func (i *Idempotency) wrap(c *gin.Context, next gin.HandlerFunc) {
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if key == "" {
+		next(c)
+		return
+	}
+	if len(key) > maxIdempotencyKeyLen {
+		apperr.Respond(c, apperr.BadRequest(fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLen)))
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		apperr.Respond(c, apperr.BadRequest("Failed to read request body").WithCause(err))
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Keys are scoped per route and per authenticated user so clients cannot collide.
+	scope := fmt.Sprintf("%s %s|%v|%s", c.Request.Method, c.FullPath(), c.Value("user_id"), key)
+	storeKey := hashHex([]byte(scope))
+	// The negotiated format is part of the request, so that a retry asking for another
+	// representation is not answered with the stored one.
+	fingerprint := hashHex(append([]byte(fmt.Sprintf("%s %s %d\n", c.Request.Method, c.Request.URL.RequestURI(), httpresp.Negotiate(c))), body...))
+
+	ctx := c.Request.Context()
+	existing, reserved, err := i.store.Reserve(ctx, storeKey, fingerprint, i.lockTimeout)
+	if err != nil {
+		i.log.WithError(err).Error("Idempotency store unavailable")
+		apperr.Respond(c, apperr.ServiceUnavailable("Unable to process idempotent request, please retry"))
+		return
+	}
+
+	if !reserved {
+		switch {
+		case existing.Fingerprint != fingerprint:
+			apperr.Respond(c, apperr.UnprocessableEntity(IdempotencyKeyHeader+" was already used with a different request"))
+		case !existing.Completed:
+			apperr.Respond(c, apperr.Conflict("A request with this "+IdempotencyKeyHeader+" is still being processed"))
+		default:
+			if existing.Location != "" {
+				c.Header("Location", existing.Location)
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(existing.Status, existing.ContentType, existing.Body)
+		}
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	defer func() {
+		c.Writer = recorder.ResponseWriter
+		// Server errors (and panics) release the key so that the client can retry.
+		status := recorder.Status()
+		if recovered := recover(); recovered != nil || status >= http.StatusInternalServerError {
+			if err := i.store.Release(ctx, storeKey); err != nil {
+				i.log.WithError(err).Warn("Failed to release idempotency key")
+			}
+			if recovered != nil {
+				panic(recovered)
+			}
+			return
+		}
+
+		record := &idempotency.Record{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Location:    recorder.Header().Get("Location"),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := i.store.Complete(ctx, storeKey, record, i.ttl); err != nil {
+			i.log.WithError(err).Warn("Failed to store idempotent response")
+		}
+	}()
+
+	next(c)
+}
+
+func hashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseRecorder captures the response body while still writing it to the client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
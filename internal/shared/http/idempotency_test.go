@@ -0,0 +1,123 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/idempotency"
+	"github.com/azahir21/go-backend-boilerplate/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type createReq struct {
+	Name string `json:"name"`
+}
+
+func newIdempotentEngine(calls *int, status int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	idem := NewIdempotency(logrus.New(), idempotency.NewMemoryStore(), time.Hour, time.Minute)
+	engine := NewServer(nil, []gin.HandlerFunc{idem.Middleware()})
+	NewAPIRouterGroup(engine.Group("/api")).Register(EndpointSpec{
+		Method:     http.MethodPost,
+		Path:       "/items",
+		Idempotent: true,
+		Handler: func(c *gin.Context, req *createReq) error {
+			*calls++
+			c.JSON(status, gin.H{"name": req.Name, "call": *calls})
+			return nil
+		},
+	})
+	return engine
+}
+
+func doPost(engine *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/items", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysOriginalResponse(t *testing.T) {
+	calls := 0
+	engine := newIdempotentEngine(&calls, http.StatusCreated)
+
+	first := doPost(engine, "abc", `{"name":"a"}`)
+	second := doPost(engine, "abc", `{"name":"a"}`)
+
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Error("replayed response should carry the Idempotent-Replayed header")
+	}
+}
+
+func TestIdempotency_RejectsDifferentPayload(t *testing.T) {
+	calls := 0
+	engine := newIdempotentEngine(&calls, http.StatusCreated)
+
+	doPost(engine, "abc", `{"name":"a"}`)
+	w := doPost(engine, "abc", `{"name":"b"}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestIdempotency_RejectsDifferentRepresentation(t *testing.T) {
+	calls := 0
+	engine := newIdempotentEngine(&calls, http.StatusCreated)
+
+	doPost(engine, "abc", `{"name":"a"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/items", strings.NewReader(`{"name":"a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", httpresp.MIMEMsgPack)
+	req.Header.Set(IdempotencyKeyHeader, "abc")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d for a retry asking for MessagePack", w.Code, http.StatusUnprocessableEntity)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	calls := 0
+	engine := newIdempotentEngine(&calls, http.StatusInternalServerError)
+
+	doPost(engine, "abc", `{"name":"a"}`)
+	doPost(engine, "abc", `{"name":"a"}`)
+
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2 (failed requests must be retryable)", calls)
+	}
+}
+
+func TestIdempotency_WithoutHeaderRunsEveryTime(t *testing.T) {
+	calls := 0
+	engine := newIdempotentEngine(&calls, http.StatusCreated)
+
+	doPost(engine, "", `{"name":"a"}`)
+	doPost(engine, "", `{"name":"a"}`)
+
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
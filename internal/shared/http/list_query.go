@@ -0,0 +1,25 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/apperr"
+	"github.com/azahir21/go-backend-boilerplate/pkg/listquery"
+	"github.com/gin-gonic/gin"
+)
+
+// ParseListQuery parses the page, cursor, limit, sort and filter query parameters against a
+// schema. On invalid parameters it responds 400 and returns false.
+func ParseListQuery(c *gin.Context, schema *listquery.Schema) (*listquery.Query, bool) {
+	q, err := listquery.Parse(schema, c.Request.URL.Query())
+	if err != nil {
+		var invalid *listquery.Error
+		if errors.As(err, &invalid) {
+			apperr.Respond(c, apperr.BadRequest(invalid.Error()))
+		} else {
+			apperr.Respond(c, apperr.BadRequest("Invalid list parameters").WithCause(err))
+		}
+		return nil, false
+	}
+	return q, true
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// CacheStatusHeader reports whether a response was served from the response cache (HIT) or not (MISS).
+	CacheStatusHeader = "X-Cache"
+
+	responseCacheKey       = "response_cache"
+	responseCacheKeyPrefix = "httpcache:"
+)
+
+// CachePolicy enables the server-side response cache for a GET endpoint.
+type CachePolicy struct {
+	// TTL overrides the configured default TTL.
+	TTL time.Duration
+	// VaryByUser keys entries by the authenticated user so that private responses are never shared.
+	VaryByUser bool
+	// Tags returns the invalidation tags of the response, e.g. cache.EntityTag("User", id).
+	// Writes that invalidate one of the tags evict the entry.
+	Tags func(c *gin.Context) []string
+}
+
+// cachedResponse is the stored form of a cached response.
+type cachedResponse struct {
+	ContentType  string `json:"content_type"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified"`
+	Body         []byte `json:"body"`
+}
+
+// ResponseCache serves responses of endpoints registered with EndpointSpec.Cache from the application cache.
+type ResponseCache struct {
+	log        *logrus.Logger
+	cache      cache.Cache
+	tags       *cache.TagStore
+	defaultTTL time.Duration
+}
+
+// NewResponseCache creates the response cache used by APIRouterGroup.
+func NewResponseCache(log *logrus.Logger, appCache cache.Cache, tags *cache.TagStore, defaultTTL time.Duration) *ResponseCache {
+	return &ResponseCache{log: log, cache: appCache, tags: tags, defaultTTL: defaultTTL}
+}
+
+// Middleware makes the response cache available to cached endpoints of every module.
+// Endpoints without EndpointSpec.Cache are unaffected.
+func (rc *ResponseCache) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(responseCacheKey, rc)
+		c.Next()
+	}
+}
+
+// wrap serves GET requests from the cache, or runs next and stores a successful response.
+func (rc *ResponseCache) wrap(c *gin.Context, policy *CachePolicy, next gin.HandlerFunc) {
+	if c.Request.Method != http.MethodGet || strings.Contains(c.GetHeader("Cache-Control"), "no-store") {
+		next(c)
+		return
+	}
+
+	var tags []string
+	if policy.Tags != nil {
+		tags = policy.Tags(c)
+	}
+	ctx := c.Request.Context()
+	key := rc.key(c, policy, rc.tags.Versions(ctx, tags))
+
+	var hit cachedResponse
+	err := rc.cache.Get(ctx, key, &hit)
+	if err == nil {
+		if hit.ETag != "" {
+			c.Header("ETag", hit.ETag)
+		}
+		c.Header("Last-Modified", hit.LastModified)
+		c.Header(CacheStatusHeader, "HIT")
+		c.Data(http.StatusOK, hit.ContentType, hit.Body)
+		return
+	}
+
+	if !errors.Is(err, cache.ErrCacheMiss) {
+		rc.log.WithError(err).Warn("Failed to read cached response")
+	}
+
+	c.Header(CacheStatusHeader, "MISS")
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	defer func() {
+		c.Writer = recorder.ResponseWriter
+	}()
+
+	next(c)
+
+	if recorder.Status() != http.StatusOK {
+		return
+	}
+	header := recorder.Header()
+	lastModified := header.Get("Last-Modified")
+	if lastModified == "" {
+		lastModified = time.Now().UTC().Format(http.TimeFormat)
+	}
+	entry := cachedResponse{
+		ContentType:  header.Get("Content-Type"),
+		ETag:         header.Get("ETag"),
+		LastModified: lastModified,
+		Body:         recorder.body.Bytes(),
+	}
+
+	ttl := policy.TTL
+	if ttl <= 0 {
+		ttl = rc.defaultTTL
+	}
+	if ttl <= 0 {
+		// A zero TTL would never expire.
+		ttl = time.Second
+	}
+	if err := rc.cache.Set(ctx, key, entry, ttl); err != nil {
+		rc.log.WithError(err).Warn("Failed to store cached response")
+	}
+}
+
+// key identifies a response by route, query, negotiated representation, user and tag versions.
+func (rc *ResponseCache) key(c *gin.Context, policy *CachePolicy, versions string) string {
+	query := c.Request.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(c.Request.URL.Path)
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		fmt.Fprintf(&b, "&%s=%s", name, strings.Join(values, ","))
+	}
+	fmt.Fprintf(&b, "|accept=%s", c.GetHeader("Accept"))
+	if policy.VaryByUser {
+		fmt.Fprintf(&b, "|user=%v", c.Value("user_id"))
+	}
+	fmt.Fprintf(&b, "|tags=%s", versions)
+	return responseCacheKeyPrefix + hashHex([]byte(b.String()))
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestCache(t *testing.T) *cache.RistrettoCache {
+	t.Helper()
+	store, err := cache.NewRistrettoCache(logrus.New(), config.RistrettoConfig{NumCounters: 1000, MaxCost: 1000, BufferItems: 64}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func newCachedEngine(calls *int, tags *cache.TagStore, store cache.Cache) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	rc := NewResponseCache(logrus.New(), store, tags, time.Minute)
+	engine := NewServer(nil, []gin.HandlerFunc{ConditionalMiddleware(false), rc.Middleware()})
+	NewAPIRouterGroup(engine.Group("/api")).Register(EndpointSpec{
+		Method: http.MethodGet,
+		Path:   "/items/:id",
+		Cache: &CachePolicy{Tags: func(c *gin.Context) []string {
+			return []string{cache.EntityTag("Item", c.Param("id"))}
+		}},
+		Handler: func(c *gin.Context) {
+			*calls++
+			SetLastModified(c, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "call": *calls})
+		},
+	})
+	return engine
+}
+
+func doGet(engine *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestResponseCache_ServesHitsUntilInvalidated(t *testing.T) {
+	calls := 0
+	store := newTestCache(t)
+	tags := cache.NewTagStore(store)
+	engine := newCachedEngine(&calls, tags, store)
+
+	first := doGet(engine, "/api/items/1", nil)
+	second := doGet(engine, "/api/items/1", nil)
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	if second.Header().Get(CacheStatusHeader) != "HIT" || second.Body.String() != first.Body.String() {
+		t.Errorf("second response = %s %s, want cached %s", second.Header().Get(CacheStatusHeader), second.Body, first.Body)
+	}
+
+	if err := tags.Invalidate(context.Background(), cache.EntityTag("Item", "1")); err != nil {
+		t.Fatal(err)
+	}
+	doGet(engine, "/api/items/1", nil)
+	if calls != 2 {
+		t.Errorf("handler called %d times after invalidation, want 2", calls)
+	}
+}
+
+func TestConditional_IfNoneMatch(t *testing.T) {
+	calls := 0
+	store := newTestCache(t)
+	engine := newCachedEngine(&calls, cache.NewTagStore(store), store)
+
+	first := doGet(engine, "/api/items/1", nil)
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+
+	w := doGet(engine, "/api/items/1", map[string]string{"If-None-Match": etag})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("status = %d body = %q, want 304 without body", w.Code, w.Body)
+	}
+
+	w = doGet(engine, "/api/items/1", map[string]string{"If-None-Match": `"other"`})
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d for a stale ETag, want 200", w.Code)
+	}
+}
+
+func TestConditional_IfModifiedSince(t *testing.T) {
+	calls := 0
+	store := newTestCache(t)
+	engine := newCachedEngine(&calls, cache.NewTagStore(store), store)
+
+	w := doGet(engine, "/api/items/1", map[string]string{"If-Modified-Since": "Tue, 02 Jan 2024 00:00:00 GMT"})
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want 304", w.Code)
+	}
+
+	w = doGet(engine, "/api/items/1", map[string]string{"If-Modified-Since": "Sun, 31 Dec 2023 00:00:00 GMT"})
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
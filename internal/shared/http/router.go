@@ -6,15 +6,20 @@ import (
 
 // HttpRouter is an interface for HTTP routers that can register routes.
 type HttpRouter interface {
-	RegisterRoutes(engine *gin.Engine)
+	// RegisterRoutes registers the router's endpoints, usually per API version via routes.Version.
+	RegisterRoutes(routes *Routes)
 }
 
-// NewServer creates a new Gin engine and registers routes from provided HttpRouters.
-func NewServer(httpRouters ...HttpRouter) *gin.Engine {
-	engine := gin.Default()
+// NewServer creates a new Gin engine, installs the global middlewares and registers
+// routes from provided HttpRouters. Middlewares are installed before any route so
+// that they apply to every module.
+func NewServer(versioning *Versioning, middlewares []gin.HandlerFunc, httpRouters ...HttpRouter) *gin.Engine {
+	engine := gin.New()
+	engine.Use(middlewares...)
 
+	routes := &Routes{engine: engine, versioning: versioning, groups: make(map[string]*gin.RouterGroup)}
 	for _, router := range httpRouters {
-		router.RegisterRoutes(engine)
+		router.RegisterRoutes(routes)
 	}
 
 	return engine
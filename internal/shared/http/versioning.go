@@ -0,0 +1,206 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// AcceptVersionHeader selects the API version of requests without a version in the path.
+	AcceptVersionHeader = "Accept-Version"
+	// APIVersionHeader reports the API version that served the request.
+	APIVersionHeader = "API-Version"
+	// DeprecationHeader (RFC 9745) and SunsetHeader (RFC 8594) are sent by deprecated versions.
+	DeprecationHeader = "Deprecation"
+	SunsetHeader      = "Sunset"
+
+	defaultVersionPrefix = "/api"
+	defaultAPIVersion    = "v1"
+)
+
+var deprecatedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_deprecated_api_requests_total",
+	Help: "Number of requests served by deprecated API versions.",
+}, []string{"version", "route"})
+
+// APIVersion is the lifecycle metadata of an API version.
+type APIVersion struct {
+	Name         string
+	DeprecatedAt time.Time
+	Sunset       time.Time
+	Link         string
+}
+
+// Deprecated reports whether the version is deprecated.
+func (v APIVersion) Deprecated() bool {
+	return !v.DeprecatedAt.IsZero()
+}
+
+// Versioning serves module endpoints per API version, under "<prefix>/<version>" or,
+// for requests without a version in the path, the version named by Accept-Version.
+type Versioning struct {
+	prefix         string
+	defaultVersion string
+	versions       map[string]APIVersion
+	// registered lists the versions modules registered endpoints for, in registration order.
+	registered []string
+}
+
+// NewVersioning creates the versioning from configuration. Prefix defaults to "/api"
+// and the default version to "v1".
+func NewVersioning(cfg config.VersioningConfig) (*Versioning, error) {
+	v := &Versioning{
+		prefix:         "/" + strings.Trim(cfg.Prefix, "/"),
+		defaultVersion: cfg.DefaultVersion,
+		versions:       make(map[string]APIVersion),
+	}
+	if v.prefix == "/" {
+		v.prefix = defaultVersionPrefix
+	}
+	if v.defaultVersion == "" {
+		v.defaultVersion = defaultAPIVersion
+	}
+
+	for _, vc := range cfg.Versions {
+		version := APIVersion{Name: vc.Name, Link: vc.Link}
+		var err error
+		if version.DeprecatedAt, err = parseOptionalTime(vc.DeprecatedAt); err != nil {
+			return nil, fmt.Errorf("invalid deprecated_at for API version %s: %w", vc.Name, err)
+		}
+		if version.Sunset, err = parseOptionalTime(vc.Sunset); err != nil {
+			return nil, fmt.Errorf("invalid sunset for API version %s: %w", vc.Name, err)
+		}
+		v.versions[vc.Name] = version
+	}
+	return v, nil
+}
+
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// Prefix returns the path prefix of versioned routes.
+func (v *Versioning) Prefix() string {
+	return v.prefix
+}
+
+// Registered returns the versions that modules registered endpoints for.
+func (v *Versioning) Registered() []APIVersion {
+	versions := make([]APIVersion, 0, len(v.registered))
+	for _, name := range v.registered {
+		versions = append(versions, v.versions[name])
+	}
+	return versions
+}
+
+// register makes the version known to request routing and returns its metadata.
+func (v *Versioning) register(name string) APIVersion {
+	version, ok := v.versions[name]
+	if !ok {
+		version = APIVersion{Name: name}
+		v.versions[name] = version
+	}
+	for _, registered := range v.registered {
+		if registered == name {
+			return version
+		}
+	}
+	v.registered = append(v.registered, name)
+	return version
+}
+
+// Handler routes requests under the prefix that carry no version in their path
+// (e.g. /api/auth/login) to the version named by Accept-Version, or to the default version.
+// Unknown Accept-Version values are left unrouted and end in 404.
+func (v *Versioning) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rest, ok := v.unversioned(r.URL.Path); ok {
+			if version, known := v.lookup(r.Header.Get(AcceptVersionHeader)); known {
+				r.URL.Path = v.prefix + "/" + version + rest
+				r.URL.RawPath = ""
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unversioned returns the path after the prefix when the path is under the prefix
+// but does not start with a known version.
+func (v *Versioning) unversioned(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, v.prefix)
+	if !ok || (rest != "" && rest[0] != '/') {
+		return "", false
+	}
+	segment, _, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+	if _, known := v.versions[segment]; known {
+		return "", false
+	}
+	return rest, true
+}
+
+// lookup resolves an Accept-Version value ("v2" or "2"); empty selects the default version.
+func (v *Versioning) lookup(requested string) (string, bool) {
+	if requested == "" {
+		requested = v.defaultVersion
+	}
+	for _, name := range []string{requested, "v" + requested} {
+		if _, ok := v.versions[name]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// middleware reports the served version and, for deprecated versions, sends the
+// Deprecation, Sunset and Link headers and counts the request.
+func (v *Versioning) middleware(version APIVersion) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(APIVersionHeader, version.Name)
+		if version.Deprecated() {
+			c.Header(DeprecationHeader, "@"+strconv.FormatInt(version.DeprecatedAt.Unix(), 10))
+			if !version.Sunset.IsZero() {
+				c.Header(SunsetHeader, version.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if version.Link != "" {
+				c.Writer.Header().Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, version.Link))
+			}
+			deprecatedRequests.WithLabelValues(version.Name, c.FullPath()).Inc()
+		}
+		c.Next()
+	}
+}
+
+// Routes is passed to HttpRouter.RegisterRoutes. Modules register their endpoints per
+// API version with Version; Engine remains available for unversioned routes such as webhooks.
+type Routes struct {
+	engine     *gin.Engine
+	versioning *Versioning
+	groups     map[string]*gin.RouterGroup
+}
+
+// Version returns the endpoint group of an API version, e.g. Version("v1") for /api/v1.
+func (r *Routes) Version(name string) *APIRouterGroup {
+	group, ok := r.groups[name]
+	if !ok {
+		version := r.versioning.register(name)
+		group = r.engine.Group(r.versioning.prefix+"/"+name, r.versioning.middleware(version))
+		r.groups[name] = group
+	}
+	return NewAPIRouterGroup(group)
+}
+
+// Engine returns the gin engine for routes outside of API versioning.
+func (r *Routes) Engine() *gin.Engine {
+	return r.engine
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/apperr"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/swaggo/swag"
+)
+
+// SwaggerHandler serves the Swagger UI of the whole API at <baseURL>/index.html and of each
+// registered version at <baseURL>/<version>/index.html. A version's doc.json only contains
+// the paths under that version. Mount it on "/swagger/*any" after registering all routers.
+func (v *Versioning) SwaggerHandler(baseURL string) gin.HandlerFunc {
+	root := ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL(baseURL+"/doc.json"))
+	perVersion := make(map[string]gin.HandlerFunc)
+	for _, version := range v.Registered() {
+		perVersion[version.Name] = ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL(baseURL+"/"+version.Name+"/doc.json"))
+	}
+
+	return func(c *gin.Context) {
+		name, rest, _ := strings.Cut(strings.TrimPrefix(c.Param("any"), "/"), "/")
+		handler, ok := perVersion[name]
+		if !ok {
+			root(c)
+			return
+		}
+		if rest != "doc.json" {
+			handler(c)
+			return
+		}
+
+		doc, err := v.VersionDoc(name)
+		if err != nil {
+			apperr.Respond(c, apperr.NotFound("API documentation is not available").WithCause(err))
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", doc)
+	}
+}
+
+// VersionDoc returns the generated Swagger document restricted to the paths of one API version.
+func (v *Versioning) VersionDoc(name string) ([]byte, error) {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		return nil, err
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, err
+	}
+
+	versionRoot := v.prefix + "/" + name
+	basePath, _ := spec["basePath"].(string)
+	paths, _ := spec["paths"].(map[string]interface{})
+	filtered := make(map[string]interface{})
+	for p, item := range paths {
+		full := path.Join("/", basePath, p)
+		if full == versionRoot || strings.HasPrefix(full, versionRoot+"/") {
+			filtered["/"+strings.TrimPrefix(strings.TrimPrefix(full, versionRoot), "/")] = item
+		}
+	}
+	spec["paths"] = filtered
+	spec["basePath"] = versionRoot
+	if info, ok := spec["info"].(map[string]interface{}); ok {
+		info["version"] = name
+	}
+	return json.Marshal(spec)
+}
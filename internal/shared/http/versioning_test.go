@@ -0,0 +1,128 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
+)
+
+type versionedRouter struct{}
+
+func (versionedRouter) RegisterRoutes(routes *Routes) {
+	for _, name := range []string{"v1", "v2"} {
+		routes.Version(name).Register(EndpointSpec{
+			Method: http.MethodGet,
+			Path:   "/items",
+			Handler: func(c *gin.Context) {
+				c.String(http.StatusOK, name)
+			},
+		})
+	}
+}
+
+func newVersionedServer(t *testing.T) (*Versioning, http.Handler) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	versioning, err := NewVersioning(config.VersioningConfig{
+		Prefix:         "/api",
+		DefaultVersion: "v2",
+		Versions: []config.APIVersionConfig{
+			{Name: "v1", DeprecatedAt: "2025-01-01T00:00:00Z", Sunset: "2026-01-01T00:00:00Z", Link: "https://example.com/v2"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := NewServer(versioning, nil, versionedRouter{})
+	return versioning, versioning.Handler(engine)
+}
+
+func serve(handler http.Handler, path, acceptVersion string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptVersion != "" {
+		req.Header.Set(AcceptVersionHeader, acceptVersion)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestVersioning_SelectsVersion(t *testing.T) {
+	_, handler := newVersionedServer(t)
+
+	tests := []struct {
+		path, acceptVersion, want string
+	}{
+		{"/api/v1/items", "", "v1"},
+		{"/api/v1/items", "v2", "v1"}, // the path wins over the header
+		{"/api/items", "v1", "v1"},
+		{"/api/items", "1", "v1"},
+		{"/api/items", "", "v2"},
+	}
+	for _, tt := range tests {
+		w := serve(handler, tt.path, tt.acceptVersion)
+		if w.Code != http.StatusOK || w.Body.String() != tt.want {
+			t.Errorf("%s (Accept-Version %q) = %d %q, want %q", tt.path, tt.acceptVersion, w.Code, w.Body, tt.want)
+		}
+		if got := w.Header().Get(APIVersionHeader); got != tt.want {
+			t.Errorf("%s: %s = %q, want %q", tt.path, APIVersionHeader, got, tt.want)
+		}
+	}
+
+	if w := serve(handler, "/api/items", "v9"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown Accept-Version: status = %d, want 404", w.Code)
+	}
+}
+
+func TestVersioning_DeprecationHeaders(t *testing.T) {
+	_, handler := newVersionedServer(t)
+
+	w := serve(handler, "/api/v1/items", "")
+	if got := w.Header().Get(DeprecationHeader); got != "@1735689600" {
+		t.Errorf("Deprecation = %q, want @1735689600", got)
+	}
+	if got := w.Header().Get(SunsetHeader); got != "Thu, 01 Jan 2026 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `<https://example.com/v2>; rel="deprecation"` {
+		t.Errorf("Link = %q", got)
+	}
+
+	w = serve(handler, "/api/v2/items", "")
+	if got := w.Header().Get(DeprecationHeader); got != "" {
+		t.Errorf("active version sent Deprecation %q", got)
+	}
+}
+
+type staticDoc string
+
+func (d staticDoc) ReadDoc() string { return string(d) }
+
+func TestVersioning_VersionDoc(t *testing.T) {
+	versioning, _ := newVersionedServer(t)
+	swag.Register(swag.Name, staticDoc(`{
+		"info": {"version": "1.0"},
+		"basePath": "/api",
+		"paths": {"/v1/items": {}, "/v2/items": {}, "/v2/other": {}}
+	}`))
+
+	doc, err := versioning.VersionDoc("v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec struct {
+		BasePath string                     `json:"basePath"`
+		Paths    map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(doc, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.BasePath != "/api/v2" || len(spec.Paths) != 2 || spec.Paths["/items"] == nil || spec.Paths["/other"] == nil {
+		t.Errorf("v2 doc = basePath %q paths %v", spec.BasePath, spec.Paths)
+	}
+}
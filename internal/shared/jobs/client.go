@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Handler runs a job. An error makes the worker run the job again later, so handlers must be
+// idempotent; wrap the error with Permanent when retrying cannot help.
+type Handler func(ctx context.Context, task Task) error
+
+// HandlerOption customizes the handling of a job type.
+type HandlerOption func(*handler)
+
+// WithConcurrency limits the jobs of the type a worker runs at the same time, within
+// jobs.concurrency.
+func WithConcurrency(n int) HandlerOption {
+	return func(h *handler) { h.concurrency = n }
+}
+
+type handler struct {
+	run         Handler
+	concurrency int
+}
+
+// Client enqueues jobs and holds the handlers registered by modules, which workers run.
+type Client struct {
+	queue       Queue
+	maxAttempts int
+
+	mu       sync.RWMutex
+	handlers map[string]handler
+}
+
+// NewClient creates a client of queue. maxAttempts is the default of enqueued jobs.
+func NewClient(queue Queue, maxAttempts int) *Client {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	return &Client{queue: queue, maxAttempts: maxAttempts, handlers: make(map[string]handler)}
+}
+
+// HandleFunc registers the handler of a job type. It panics if the type already has one.
+func (c *Client) HandleFunc(jobType string, h Handler, opts ...HandlerOption) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, dup := c.handlers[jobType]; dup {
+		panic("jobs: HandleFunc called twice for job type " + jobType)
+	}
+	registered := handler{run: h}
+	for _, opt := range opts {
+		opt(&registered)
+	}
+	c.handlers[jobType] = registered
+}
+
+// Handle registers a handler of a job type receiving the decoded payload. A payload that
+// does not decode into T fails the job permanently.
+func Handle[T any](c *Client, jobType string, fn func(ctx context.Context, payload T) error, opts ...HandlerOption) {
+	c.HandleFunc(jobType, func(ctx context.Context, task Task) error {
+		var payload T
+		if err := task.Decode(&payload); err != nil {
+			return Permanent(fmt.Errorf("invalid payload: %w", err))
+		}
+		return fn(ctx, payload)
+	}, opts...)
+}
+
+// Enqueue queues a job of a registered type with payload encoded as JSON. Inside
+// UnitOfWork.Do the job is enqueued only if the transaction commits.
+func (c *Client) Enqueue(ctx context.Context, jobType string, payload any, opts ...EnqueueOption) error {
+	if _, ok := c.handler(jobType); !ok {
+		return fmt.Errorf("jobs: no handler is registered for job type %q", jobType)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s job: %w", jobType, err)
+	}
+	j := Job{Type: jobType, Payload: data, MaxAttempts: c.maxAttempts}
+	for _, opt := range opts {
+		opt(&j)
+	}
+	return c.queue.Enqueue(ctx, j)
+}
+
+func (c *Client) handler(jobType string) (handler, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	h, ok := c.handlers[jobType]
+	return h, ok
+}
+
+// types returns the registered job types, sorted.
+func (c *Client) types() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	types := make([]string, 0, len(c.handlers))
+	for t := range c.handlers {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,84 @@
+// Package jobs runs background jobs: modules register typed handlers, usecases enqueue jobs,
+// inside UnitOfWork.Do to enqueue them only if the transaction commits, and workers run them
+// with a concurrency limit, retrying failures with exponential backoff. Jobs are queued in
+// redis or in the SQL database.
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Statuses of jobs in the queue.
+const (
+	StatusPending = "pending"
+	StatusDead    = "dead"
+)
+
+// Job is a job to enqueue.
+type Job struct {
+	Type string
+	// Payload is the JSON encoded argument of the handler.
+	Payload []byte
+	// RunAt is when the job is due; zero runs it right away.
+	RunAt       time.Time
+	MaxAttempts int
+}
+
+// Task is a job claimed by a worker.
+type Task struct {
+	ID      string
+	Type    string
+	Payload json.RawMessage
+	// Attempt counts the runs of the job, starting at 1.
+	Attempt     int
+	MaxAttempts int
+}
+
+// Decode unmarshals the payload into v.
+func (t Task) Decode(v any) error {
+	return json.Unmarshal(t.Payload, v)
+}
+
+// EnqueueOption customizes an enqueued job.
+type EnqueueOption func(*Job)
+
+// WithDelay runs the job after d.
+func WithDelay(d time.Duration) EnqueueOption {
+	return func(j *Job) { j.RunAt = time.Now().UTC().Add(d) }
+}
+
+// WithRunAt runs the job at t.
+func WithRunAt(t time.Time) EnqueueOption {
+	return func(j *Job) { j.RunAt = t.UTC() }
+}
+
+// WithMaxAttempts sets how many runs of the job fail before it is dead, instead of
+// jobs.max_attempts.
+func WithMaxAttempts(n int) EnqueueOption {
+	return func(j *Job) { j.MaxAttempts = n }
+}
+
+// permanentError marks a failure that retries cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps a handler error so that the job is dead right away instead of retried,
+// e.g. when its payload is invalid.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
@@ -0,0 +1,261 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/enttest"
+	"github.com/azahir21/go-backend-boilerplate/ent/job"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+type welcome struct {
+	Email string `json:"email"`
+}
+
+// testQueue is a SQL queue and a worker sharing a clock.
+type testQueue struct {
+	ent    *ent.Client
+	uow    unitofwork.UnitOfWork
+	client *Client
+	worker *Worker
+	clock  time.Time
+}
+
+func newTestQueue(t *testing.T, cfg config.JobsConfig) *testQueue {
+	t.Helper()
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	tq := &testQueue{ent: client, uow: unitofwork.NewUnitOfWork(client, log), clock: time.Now().UTC()}
+	queue := NewSQLQueue(client)
+	queue.now = func() time.Time { return tq.clock }
+	tq.client = NewClient(queue, cfg.MaxAttempts)
+	worker, err := NewWorker(tq.client, log, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worker.now = queue.now
+	tq.worker = worker
+	return tq
+}
+
+func (tq *testQueue) runOnce(t *testing.T) int {
+	t.Helper()
+	n, err := tq.worker.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestClient_Enqueue(t *testing.T) {
+	ctx := context.Background()
+	tq := newTestQueue(t, config.JobsConfig{MaxAttempts: 3})
+	Handle(tq.client, "user.send_welcome", func(context.Context, welcome) error { return nil })
+
+	if err := tq.client.Enqueue(ctx, "user.unknown", nil); err == nil {
+		t.Error("Enqueue() of a job type without handler succeeded")
+	}
+
+	_ = tq.uow.Do(ctx, func(ctx context.Context, _ unitofwork.UnitOfWork) error {
+		if err := tq.client.Enqueue(ctx, "user.send_welcome", welcome{Email: "alice@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+		return errors.New("abort")
+	})
+	if n := tq.ent.Job.Query().CountX(ctx); n != 0 {
+		t.Errorf("%d jobs enqueued despite the rollback", n)
+	}
+
+	err := tq.uow.Do(ctx, func(ctx context.Context, _ unitofwork.UnitOfWork) error {
+		return tq.client.Enqueue(ctx, "user.send_welcome", welcome{Email: "alice@example.com"}, WithDelay(time.Hour))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := tq.ent.Job.Query().OnlyX(ctx)
+	if j.MaxAttempts != 3 || !j.RunAt.After(time.Now().Add(59*time.Minute)) {
+		t.Errorf("job = %d max attempts at %s, want 3 in an hour", j.MaxAttempts, j.RunAt)
+	}
+}
+
+func TestWorker_RunsJobs(t *testing.T) {
+	ctx := context.Background()
+	tq := newTestQueue(t, config.JobsConfig{})
+	var mu sync.Mutex
+	var sent []string
+	Handle(tq.client, "user.send_welcome", func(_ context.Context, w welcome) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, w.Email)
+		return nil
+	})
+	for _, email := range []string{"alice@example.com", "bob@example.com"} {
+		if err := tq.client.Enqueue(ctx, "user.send_welcome", welcome{Email: email}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tq.client.Enqueue(ctx, "user.send_welcome", welcome{Email: "carol@example.com"}, WithRunAt(tq.clock.Add(time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := tq.runOnce(t); n != 2 {
+		t.Errorf("ran %d jobs, want the 2 due", n)
+	}
+	if len(sent) != 2 {
+		t.Errorf("sent %v", sent)
+	}
+	if n := tq.ent.Job.Query().CountX(ctx); n != 1 {
+		t.Errorf("%d jobs left, want the delayed one", n)
+	}
+	tq.clock = tq.clock.Add(time.Minute)
+	if n := tq.runOnce(t); n != 1 {
+		t.Errorf("ran %d jobs once due, want 1", n)
+	}
+}
+
+func TestWorker_RetriesFailedJobs(t *testing.T) {
+	ctx := context.Background()
+	tq := newTestQueue(t, config.JobsConfig{MaxAttempts: 2, RetryBackoff: "1m"})
+	tq.client.HandleFunc("report.generate", func(context.Context, Task) error {
+		return errors.New("storage unavailable")
+	})
+	tq.client.HandleFunc("report.invalid", func(context.Context, Task) error {
+		return Permanent(errors.New("unknown report"))
+	})
+	if err := tq.client.Enqueue(ctx, "report.generate", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := tq.client.Enqueue(ctx, "report.invalid", nil, WithMaxAttempts(5)); err != nil {
+		t.Fatal(err)
+	}
+
+	tq.runOnce(t)
+	retried := tq.ent.Job.Query().Where(job.TypeEQ("report.generate")).OnlyX(ctx)
+	if retried.Status != StatusPending || retried.Attempts != 1 || !retried.RunAt.Equal(tq.clock.Add(time.Minute)) {
+		t.Errorf("failed job = %s after %d attempts at %s, want pending in a minute", retried.Status, retried.Attempts, retried.RunAt)
+	}
+	invalid := tq.ent.Job.Query().Where(job.TypeEQ("report.invalid")).OnlyX(ctx)
+	if invalid.Status != StatusDead || invalid.LastError != "unknown report" {
+		t.Errorf("permanently failed job = %s (%s), want dead", invalid.Status, invalid.LastError)
+	}
+
+	if n := tq.runOnce(t); n != 0 {
+		t.Errorf("ran %d jobs before the retry backoff elapsed", n)
+	}
+	tq.clock = tq.clock.Add(time.Minute)
+	tq.runOnce(t)
+	dead := tq.ent.Job.GetX(ctx, retried.ID)
+	if dead.Status != StatusDead || dead.Attempts != 2 || !strings.Contains(dead.LastError, "storage unavailable") {
+		t.Errorf("job = %s after %d attempts, want dead after 2", dead.Status, dead.Attempts)
+	}
+}
+
+func TestWorker_ConcurrencyLimits(t *testing.T) {
+	ctx := context.Background()
+	tq := newTestQueue(t, config.JobsConfig{Concurrency: 3})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	running := map[string]int{}
+	block := func(_ context.Context, task Task) error {
+		mu.Lock()
+		running[task.Type]++
+		mu.Unlock()
+		<-release
+		return nil
+	}
+	tq.client.HandleFunc("email.send", block, WithConcurrency(1))
+	tq.client.HandleFunc("report.generate", block)
+	for i := 0; i < 3; i++ {
+		for _, jobType := range []string{"email.send", "report.generate"} {
+			if err := tq.client.Enqueue(ctx, jobType, i); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	n, err := tq.worker.dispatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	tq.worker.wg.Wait()
+	if n != 3 || running["email.send"] != 1 || running["report.generate"] != 2 {
+		t.Errorf("started %d jobs: %v, want 1 email within its limit and 2 reports within the worker's", n, running)
+	}
+	if left := tq.ent.Job.Query().CountX(ctx); left != 3 {
+		t.Errorf("%d jobs left, want 3", left)
+	}
+}
+
+func TestSQLQueue_ClaimSkipsClaimedJobs(t *testing.T) {
+	ctx := context.Background()
+	tq := newTestQueue(t, config.JobsConfig{})
+	tq.client.HandleFunc("email.send", func(context.Context, Task) error { return nil })
+	if err := tq.client.Enqueue(ctx, "email.send", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := tq.client.queue.Claim(ctx, "email.send", 10, time.Minute)
+	if err != nil || len(first) != 1 || first[0].Attempt != 1 {
+		t.Fatalf("Claim() = %v, %v, want the job at its first attempt", first, err)
+	}
+	if again, err := tq.client.queue.Claim(ctx, "email.send", 10, time.Minute); err != nil || len(again) != 0 {
+		t.Errorf("Claim() of a claimed job = %v, %v, want none", again, err)
+	}
+	// The worker is gone: the job runs again once its lease expires
+	tq.clock = tq.clock.Add(time.Minute)
+	if again, err := tq.client.queue.Claim(ctx, "email.send", 10, time.Minute); err != nil || len(again) != 1 || again[0].Attempt != 2 {
+		t.Errorf("Claim() after the lease = %v, %v, want the job at its second attempt", again, err)
+	}
+}
+
+func TestRedisQueue_StagesJobsOfTransactions(t *testing.T) {
+	ctx := context.Background()
+	tq := newTestQueue(t, config.JobsConfig{})
+	// Jobs enqueued in a transaction never reach redis before the commit
+	queue := NewRedisQueue(logrus.New(), nil, tq.ent)
+
+	_ = tq.uow.Do(ctx, func(ctx context.Context, _ unitofwork.UnitOfWork) error {
+		if err := queue.Enqueue(ctx, Job{Type: "email.send", Payload: []byte(`{}`)}); err != nil {
+			t.Fatal(err)
+		}
+		return errors.New("abort")
+	})
+	if n := tq.ent.Job.Query().CountX(ctx); n != 0 {
+		t.Fatalf("%d jobs staged despite the rollback", n)
+	}
+
+	if err := tq.uow.Do(ctx, func(ctx context.Context, _ unitofwork.UnitOfWork) error {
+		return queue.Enqueue(ctx, Job{Type: "email.send", Payload: []byte(`{}`), MaxAttempts: 3})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if staged := tq.ent.Job.Query().OnlyX(ctx); staged.Type != "email.send" || staged.MaxAttempts != 3 {
+		t.Errorf("staged job = %+v, want the job enqueued in the transaction", staged)
+	}
+}
+
+func TestNewWorker_InvalidConfig(t *testing.T) {
+	for _, cfg := range []config.JobsConfig{
+		{PollInterval: "often"},
+		{RetryBackoff: "0s"},
+		{Timeout: "-1m"},
+	} {
+		if _, err := NewWorker(NewClient(nil, 0), logrus.New(), cfg); err == nil {
+			t.Errorf("NewWorker(%+v) succeeded, want an error", cfg)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/sirupsen/logrus"
+)
+
+// Queue stores jobs until a worker claims them.
+type Queue interface {
+	// Enqueue stores jobs. Inside UnitOfWork.Do they are enqueued only if the transaction
+	// commits.
+	Enqueue(ctx context.Context, jobs ...Job) error
+	// Claim returns up to limit due jobs of the given type and hides them from other workers
+	// for lease, after which an unfinished job runs again. Claiming counts an attempt.
+	Claim(ctx context.Context, jobType string, limit int, lease time.Duration) ([]Task, error)
+	// Complete removes a job that succeeded.
+	Complete(ctx context.Context, task Task) error
+	// Retry schedules a failed job to run again at the given time.
+	Retry(ctx context.Context, task Task, at time.Time, cause error) error
+	// Kill keeps a job that is out of attempts as dead, for inspection.
+	Kill(ctx context.Context, task Task, cause error) error
+}
+
+// NewQueue creates a Queue backed by the configured cache or database.
+// A redis cache gives a queue in redis, with jobs enqueued in transactions staged in the SQL
+// database; otherwise jobs are queued in the SQL database, which must then be enabled.
+func NewQueue(log *logrus.Logger, client *ent.Client, appCache cache.Cache) (Queue, error) {
+	if redisCache, ok := appCache.(*cache.RedisCache); ok {
+		log.Info("Job queue initialized with redis backend")
+		return NewRedisQueue(log, redisCache.Client(), client), nil
+	}
+	if client != nil {
+		log.Info("Job queue initialized with SQL backend")
+		return NewSQLQueue(client), nil
+	}
+	return nil, errors.New("the job queue needs the SQL database or a redis cache")
+}
@@ -0,0 +1,201 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Redis keys of the queue: a sorted set of job IDs per type scored by the time they are due
+// (in milliseconds), a hash per job, a sorted set of dead jobs and the ID sequence.
+const (
+	keyPrefix   = "jobs:"
+	queuePrefix = keyPrefix + "queue:"
+	jobPrefix   = keyPrefix + "job:"
+	deadKey     = keyPrefix + "dead"
+	sequenceKey = keyPrefix + "seq"
+)
+
+// forwardBatchSize is the number of staged jobs moved to redis at a time.
+const forwardBatchSize = 100
+
+// forwardLease hides staged jobs from other forwarders while they are moved to redis.
+const forwardLease = time.Minute
+
+// enqueueScript stores a job under the next ID of the sequence and queues it.
+// KEYS[1] = sequence key, KEYS[2] = queue key; ARGV[1] = job key prefix; ARGV[2] = type;
+// ARGV[3] = payload; ARGV[4] = max attempts; ARGV[5] = now (ms); ARGV[6] = due time (ms).
+// Returns the job ID.
+var enqueueScript = redis.NewScript(`
+local id = redis.call('INCR', KEYS[1])
+redis.call('HSET', ARGV[1] .. id, 'type', ARGV[2], 'payload', ARGV[3], 'attempts', 0,
+  'max_attempts', ARGV[4], 'created_at', ARGV[5])
+redis.call('ZADD', KEYS[2], ARGV[6], id)
+return id
+`)
+
+// claimScript claims due jobs atomically by moving their score past the lease.
+// KEYS[1] = queue key; ARGV[1] = now (ms); ARGV[2] = end of the lease (ms); ARGV[3] = limit;
+// ARGV[4] = job key prefix.
+// Returns {id, payload, attempt, max_attempts} per claimed job.
+var claimScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[3]))
+local claimed = {}
+for _, id in ipairs(ids) do
+  local key = ARGV[4] .. id
+  if redis.call('EXISTS', key) == 0 then
+    redis.call('ZREM', KEYS[1], id)
+  else
+    redis.call('ZADD', KEYS[1], ARGV[2], id)
+    local attempt = redis.call('HINCRBY', key, 'attempts', 1)
+    local fields = redis.call('HMGET', key, 'payload', 'max_attempts')
+    table.insert(claimed, {id, fields[1], attempt, fields[2]})
+  end
+end
+return claimed
+`)
+
+// RedisQueue queues jobs in redis, shared by all replicas. Redis cannot take part in a SQL
+// transaction, so jobs enqueued inside UnitOfWork.Do are staged in the jobs table of the
+// transaction instead, and moved to redis by the workers once they are due. A staged job whose
+// forwarder dies before deleting it is pushed again.
+type RedisQueue struct {
+	client  *redis.Client
+	staging *SQLQueue
+	log     *logrus.Logger
+	now     func() time.Time
+}
+
+// NewRedisQueue creates a queue that shares the given redis client, staging the jobs enqueued
+// in transactions in the database of client, if any.
+func NewRedisQueue(log *logrus.Logger, redisClient *redis.Client, client *ent.Client) *RedisQueue {
+	q := &RedisQueue{client: redisClient, log: log, now: func() time.Time { return time.Now().UTC() }}
+	if client != nil {
+		q.staging = NewSQLQueue(client)
+	}
+	return q
+}
+
+// Enqueue pushes jobs, or stages them in the transaction carried by ctx if any.
+func (q *RedisQueue) Enqueue(ctx context.Context, jobs ...Job) error {
+	if !unitofwork.InTransaction(ctx) {
+		return q.push(ctx, jobs)
+	}
+	if q.staging == nil {
+		return errors.New("jobs: enqueuing in a transaction needs the SQL database")
+	}
+	return q.staging.Enqueue(ctx, jobs...)
+}
+
+func (q *RedisQueue) push(ctx context.Context, jobs []Job) error {
+	now := q.now()
+	for _, j := range jobs {
+		runAt := j.RunAt
+		if runAt.IsZero() {
+			runAt = now
+		}
+		err := enqueueScript.Run(ctx, q.client, []string{sequenceKey, queuePrefix + j.Type},
+			jobPrefix, j.Type, j.Payload, j.MaxAttempts, now.UnixMilli(), runAt.UnixMilli()).Err()
+		if err != nil {
+			return fmt.Errorf("failed to enqueue %s job: %w", j.Type, err)
+		}
+	}
+	return nil
+}
+
+// forward moves the due jobs of the type staged in the SQL database to redis.
+func (q *RedisQueue) forward(ctx context.Context, jobType string) error {
+	for {
+		staged, err := q.staging.Claim(ctx, jobType, forwardBatchSize, forwardLease)
+		if err != nil {
+			return err
+		}
+		for _, task := range staged {
+			if err := q.push(ctx, []Job{{Type: task.Type, Payload: task.Payload, MaxAttempts: task.MaxAttempts}}); err != nil {
+				return err
+			}
+			if err := q.staging.Complete(ctx, task); err != nil {
+				return fmt.Errorf("failed to delete staged job %s: %w", task.ID, err)
+			}
+		}
+		if len(staged) < forwardBatchSize {
+			return nil
+		}
+	}
+}
+
+// Claim forwards the staged jobs of the type, then runs claimScript on its queue.
+func (q *RedisQueue) Claim(ctx context.Context, jobType string, limit int, lease time.Duration) ([]Task, error) {
+	if q.staging != nil {
+		if err := q.forward(ctx, jobType); err != nil {
+			q.log.WithContext(ctx).WithError(err).Warnf("Failed to move staged %s jobs to redis", jobType)
+		}
+	}
+	now := q.now()
+	res, err := claimScript.Run(ctx, q.client, []string{queuePrefix + jobType},
+		now.UnixMilli(), now.Add(lease).UnixMilli(), limit, jobPrefix).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim %s jobs: %w", jobType, err)
+	}
+	tasks := make([]Task, 0, len(res))
+	for _, item := range res {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected claim result %v", item)
+		}
+		id, _ := fields[0].(string)
+		payload, _ := fields[1].(string)
+		attempt, _ := fields[2].(int64)
+		maxAttempts, _ := fields[3].(string)
+		n, err := strconv.Atoi(maxAttempts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_attempts of job %s: %q", id, maxAttempts)
+		}
+		tasks = append(tasks, Task{
+			ID:          id,
+			Type:        jobType,
+			Payload:     []byte(payload),
+			Attempt:     int(attempt),
+			MaxAttempts: n,
+		})
+	}
+	return tasks, nil
+}
+
+// Complete removes the job from its queue and deletes it.
+func (q *RedisQueue) Complete(ctx context.Context, task Task) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, queuePrefix+task.Type, task.ID)
+		pipe.Del(ctx, jobPrefix+task.ID)
+		return nil
+	})
+	return err
+}
+
+// Retry moves the score of the job to at.
+func (q *RedisQueue) Retry(ctx context.Context, task Task, at time.Time, cause error) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, jobPrefix+task.ID, "last_error", cause.Error())
+		pipe.ZAdd(ctx, queuePrefix+task.Type, redis.Z{Score: float64(at.UnixMilli()), Member: task.ID})
+		return nil
+	})
+	return err
+}
+
+// Kill moves the job from its queue to the dead jobs.
+func (q *RedisQueue) Kill(ctx context.Context, task Task, cause error) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, jobPrefix+task.ID, "status", StatusDead, "last_error", cause.Error())
+		pipe.ZRem(ctx, queuePrefix+task.Type, task.ID)
+		pipe.ZAdd(ctx, deadKey, redis.Z{Score: float64(q.now().UnixMilli()), Member: task.ID})
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/job"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
+)
+
+// SQLQueue queues jobs in the jobs table. Jobs enqueued inside UnitOfWork.Do are written in
+// its transaction.
+type SQLQueue struct {
+	client *ent.Client
+	now    func() time.Time
+}
+
+// NewSQLQueue creates a queue in the database of client.
+func NewSQLQueue(client *ent.Client) *SQLQueue {
+	return &SQLQueue{client: client, now: func() time.Time { return time.Now().UTC() }}
+}
+
+// Enqueue inserts jobs, in the transaction carried by ctx if any.
+func (q *SQLQueue) Enqueue(ctx context.Context, jobs ...Job) error {
+	client := unitofwork.ClientFromContext(ctx, q.client)
+	now := q.now()
+	builders := make([]*ent.JobCreate, len(jobs))
+	for i, j := range jobs {
+		runAt := j.RunAt
+		if runAt.IsZero() {
+			runAt = now
+		}
+		builders[i] = client.Job.Create().
+			SetType(j.Type).
+			SetPayload(j.Payload).
+			SetMaxAttempts(j.MaxAttempts).
+			SetRunAt(runAt).
+			SetCreatedAt(now)
+	}
+	if err := client.Job.CreateBulk(builders...).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to enqueue jobs: %w", err)
+	}
+	return nil
+}
+
+// Claim reads due jobs and claims each with a conditional update moving run_at past the
+// lease, so that a job read by several workers is claimed by one.
+func (q *SQLQueue) Claim(ctx context.Context, jobType string, limit int, lease time.Duration) ([]Task, error) {
+	now := q.now()
+	due, err := q.client.Job.Query().
+		Where(job.TypeEQ(jobType), job.StatusEQ(StatusPending), job.RunAtLTE(now)).
+		Order(ent.Asc(job.FieldRunAt), ent.Asc(job.FieldID)).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read due jobs: %w", err)
+	}
+	tasks := make([]Task, 0, len(due))
+	for _, j := range due {
+		claimed, err := q.client.Job.Update().
+			Where(job.ID(j.ID), job.StatusEQ(StatusPending), job.RunAtLTE(now)).
+			SetRunAt(now.Add(lease)).
+			AddAttempts(1).
+			Save(ctx)
+		if err != nil {
+			return tasks, fmt.Errorf("failed to claim job %d: %w", j.ID, err)
+		}
+		if claimed == 0 {
+			continue
+		}
+		tasks = append(tasks, Task{
+			ID:          strconv.Itoa(j.ID),
+			Type:        j.Type,
+			Payload:     j.Payload,
+			Attempt:     j.Attempts + 1,
+			MaxAttempts: j.MaxAttempts,
+		})
+	}
+	return tasks, nil
+}
+
+// Complete deletes the job.
+func (q *SQLQueue) Complete(ctx context.Context, task Task) error {
+	id, err := strconv.Atoi(task.ID)
+	if err != nil {
+		return fmt.Errorf("invalid job ID %q", task.ID)
+	}
+	return q.client.Job.DeleteOneID(id).Exec(ctx)
+}
+
+// Retry moves run_at of the job to at.
+func (q *SQLQueue) Retry(ctx context.Context, task Task, at time.Time, cause error) error {
+	id, err := strconv.Atoi(task.ID)
+	if err != nil {
+		return fmt.Errorf("invalid job ID %q", task.ID)
+	}
+	return q.client.Job.UpdateOneID(id).SetRunAt(at).SetLastError(cause.Error()).Exec(ctx)
+}
+
+// Kill marks the job dead.
+func (q *SQLQueue) Kill(ctx context.Context, task Task, cause error) error {
+	id, err := strconv.Atoi(task.ID)
+	if err != nil {
+		return fmt.Errorf("invalid job ID %q", task.ID)
+	}
+	return q.client.Job.UpdateOneID(id).SetStatus(StatusDead).SetLastError(cause.Error()).Exec(ctx)
+}
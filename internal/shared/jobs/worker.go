@@ -0,0 +1,272 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var tracer = otel.Tracer("github.com/azahir21/go-backend-boilerplate/internal/shared/jobs")
+
+var (
+	processed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "jobs_processed_total",
+		Help: "Job runs by job type and outcome (completed, retry, dead).",
+	}, []string{"job_type", "outcome"})
+
+	runDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "jobs_run_duration_seconds",
+		Help:    "Duration of job runs by job type.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"job_type"})
+)
+
+// Defaults of the settings left empty in the jobs configuration.
+const (
+	defaultConcurrency  = 10
+	defaultPollInterval = time.Second
+	defaultMaxAttempts  = 10
+	defaultRetryBackoff = 5 * time.Second
+	defaultTimeout      = 5 * time.Minute
+)
+
+// maxRetryBackoff caps the exponential wait between runs of a job.
+const maxRetryBackoff = time.Hour
+
+// leaseMargin is how long a claimed job stays hidden from other workers after its timeout,
+// so that it runs again only if its worker is gone.
+const leaseMargin = time.Minute
+
+// Worker runs the jobs of the registered types: it claims due jobs while it has free slots,
+// runs each with a timeout and completes, retries or kills it depending on the outcome.
+type Worker struct {
+	client *Client
+	log    *logrus.Logger
+
+	concurrency  int
+	pollInterval time.Duration
+	retryBackoff time.Duration
+	timeout      time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	running map[string]int
+	total   int
+	wg      sync.WaitGroup
+	wake    chan struct{}
+
+	stopping chan struct{}
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewWorker creates a worker of the jobs of client from the jobs configuration.
+func NewWorker(client *Client, log *logrus.Logger, cfg config.JobsConfig) (*Worker, error) {
+	w := &Worker{
+		client:      client,
+		log:         log,
+		concurrency: cfg.Concurrency,
+		now:         func() time.Time { return time.Now().UTC() },
+		running:     make(map[string]int),
+		wake:        make(chan struct{}, 1),
+	}
+	if w.concurrency <= 0 {
+		w.concurrency = defaultConcurrency
+	}
+	for _, setting := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+		def   time.Duration
+	}{
+		{"jobs.poll_interval", cfg.PollInterval, &w.pollInterval, defaultPollInterval},
+		{"jobs.retry_backoff", cfg.RetryBackoff, &w.retryBackoff, defaultRetryBackoff},
+		{"jobs.timeout", cfg.Timeout, &w.timeout, defaultTimeout},
+	} {
+		*setting.dst = setting.def
+		if setting.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(setting.value)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("invalid %s %q", setting.name, setting.value)
+		}
+		*setting.dst = v
+	}
+	return w, nil
+}
+
+// Start runs the worker in the background until Stop.
+func (w *Worker) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.stopping, w.cancel, w.done = make(chan struct{}), cancel, make(chan struct{})
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops claiming jobs and waits for the running ones. If ctx ends first, they are
+// cancelled; their jobs run again once their lease expires.
+func (w *Worker) Stop(ctx context.Context) error {
+	if w.done == nil {
+		return nil
+	}
+	close(w.stopping)
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		<-w.done
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) run(ctx context.Context) {
+	defer close(w.done)
+	defer w.cancel()
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.dispatch(ctx); err != nil && ctx.Err() == nil {
+			w.log.WithError(err).Warn("Failed to claim jobs")
+		}
+		// Claim again on the next poll, or as soon as a slot frees up
+		select {
+		case <-w.stopping:
+			w.wg.Wait()
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+// RunOnce claims the due jobs that fit in the free slots, runs them and waits for them. It
+// returns the number of jobs run.
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	n, err := w.dispatch(ctx)
+	w.wg.Wait()
+	return n, err
+}
+
+// dispatch claims jobs of every registered type up to the free slots of the worker and of
+// the type, and starts them.
+func (w *Worker) dispatch(ctx context.Context) (int, error) {
+	lease := w.timeout + leaseMargin
+	started := 0
+	var errs []error
+	for _, jobType := range w.client.types() {
+		h, _ := w.client.handler(jobType)
+		free := w.free(jobType, h.concurrency)
+		if free == 0 {
+			continue
+		}
+		tasks, err := w.client.queue.Claim(ctx, jobType, free, lease)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, task := range tasks {
+			w.mu.Lock()
+			w.running[jobType]++
+			w.total++
+			w.mu.Unlock()
+			w.wg.Add(1)
+			go func() {
+				defer w.finish(jobType)
+				w.process(ctx, h, task)
+			}()
+			started++
+		}
+	}
+	return started, errors.Join(errs...)
+}
+
+// free returns the number of jobs of the type the worker can start.
+func (w *Worker) free(jobType string, limit int) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	free := w.concurrency - w.total
+	if limit > 0 && limit-w.running[jobType] < free {
+		free = limit - w.running[jobType]
+	}
+	return max(free, 0)
+}
+
+func (w *Worker) finish(jobType string) {
+	w.mu.Lock()
+	w.running[jobType]--
+	w.total--
+	w.mu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+	w.wg.Done()
+}
+
+// process runs a claimed job and records the outcome.
+func (w *Worker) process(ctx context.Context, h handler, task Task) {
+	start := time.Now()
+	runCtx, cancel := context.WithTimeout(ctx, w.timeout)
+	runCtx, span := tracer.Start(runCtx, "jobs.run")
+	span.SetAttributes(
+		attribute.String("job.type", task.Type),
+		attribute.String("job.id", task.ID),
+		attribute.Int("job.attempt", task.Attempt),
+	)
+	runErr := call(runCtx, task, h.run)
+	tracing.End(span, runErr)
+	cancel()
+	runDuration.WithLabelValues(task.Type).Observe(time.Since(start).Seconds())
+	if runErr != nil && ctx.Err() != nil {
+		// Stopping: the lease expires and the job runs again
+		return
+	}
+
+	log := w.log.WithContext(ctx).WithFields(logrus.Fields{
+		"job_id":   task.ID,
+		"job_type": task.Type,
+		"attempt":  task.Attempt,
+	})
+	var err error
+	switch {
+	case runErr == nil:
+		err = w.client.queue.Complete(ctx, task)
+		processed.WithLabelValues(task.Type, "completed").Inc()
+	case IsPermanent(runErr) || task.Attempt >= task.MaxAttempts:
+		err = w.client.queue.Kill(ctx, task, runErr)
+		processed.WithLabelValues(task.Type, "dead").Inc()
+		log.WithError(runErr).Error("Job failed for the last time")
+	default:
+		backoff := w.retryBackoff << (task.Attempt - 1)
+		if backoff > maxRetryBackoff || backoff <= 0 {
+			backoff = maxRetryBackoff
+		}
+		err = w.client.queue.Retry(ctx, task, w.now().Add(backoff), runErr)
+		processed.WithLabelValues(task.Type, "retry").Inc()
+		log.WithError(runErr).Warnf("Job failed, retrying in %s", backoff)
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to record the outcome of a job")
+	}
+}
+
+// call runs h, turning a panic into an error.
+func call(ctx context.Context, task Task, h Handler) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+		}
+	}()
+	return h(ctx, task)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const accessLogFieldsKey = "access_log_fields"
+
+// AccessLogMiddleware logs every request through logrus once the handler chain completes.
+// It records method, route template, status, latency, response size, client IP,
+// user ID and request ID, honouring the sampling, skip-path and masking settings.
+func AccessLogMiddleware(log *logrus.Logger, cfg config.AccessLogConfig) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(cfg.SkipPaths))
+	for _, p := range cfg.SkipPaths {
+		skip[p] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		if !cfg.Enable {
+			return
+		}
+
+		route := c.FullPath()
+		if _, ok := skip[route]; ok {
+			return
+		}
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			return
+		}
+
+		status := c.Writer.Status()
+		if status < http.StatusBadRequest && !logger.ShouldSample(cfg.SampleRate) {
+			return
+		}
+
+		fields := logrus.Fields{
+			"transport":  "http",
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"route":      route,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      c.Writer.Size(),
+			"client_ip":  c.ClientIP(),
+			"user_agent": c.Request.UserAgent(),
+			"request_id": GetRequestID(c),
+		}
+		if userID, ok := c.Get(userIDKey); ok {
+			fields["user_id"] = userID
+		}
+		if query := logger.MaskQuery(c.Request.URL.Query(), cfg.MaskQueryParams); query != "" {
+			fields["query"] = query
+		}
+		if cfg.LogHeaders {
+			fields["headers"] = logger.MaskValues(c.Request.Header, cfg.MaskHeaders)
+		}
+		if extra, ok := c.Get(accessLogFieldsKey); ok {
+			for k, v := range extra.(logrus.Fields) {
+				fields[k] = v
+			}
+		}
+		if len(c.Errors) > 0 {
+			fields["errors"] = c.Errors.String()
+		}
+
+		entry := log.WithContext(c.Request.Context()).WithFields(fields)
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("HTTP request")
+		case status >= http.StatusBadRequest:
+			entry.Warn("HTTP request")
+		default:
+			entry.Info("HTTP request")
+		}
+	}
+}
+
+// AddAccessLogField attaches an extra field to the access log entry of the current request,
+// e.g. the GraphQL operation name.
+func AddAccessLogField(c *gin.Context, key string, value interface{}) {
+	fields, ok := c.Get(accessLogFieldsKey)
+	if !ok {
+		fields = logrus.Fields{}
+		c.Set(accessLogFieldsKey, fields)
+	}
+	fields.(logrus.Fields)[key] = value
+}
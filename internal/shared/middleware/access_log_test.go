@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestAccessLog(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(RequestIDMiddleware(), AccessLogMiddleware(log, config.AccessLogConfig{
+		Enable:          true,
+		SampleRate:      0,
+		SkipPaths:       []string{"/health"},
+		LogHeaders:      true,
+		MaskHeaders:     []string{"Authorization"},
+		MaskQueryParams: []string{"token"},
+	}))
+	engine.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+	engine.GET("/users/:id", func(c *gin.Context) {
+		AddAccessLogField(c, "operation", "user")
+		c.Status(http.StatusNotFound)
+	})
+	engine.GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/health", "/ok"} {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	if n := len(hook.AllEntries()); n != 0 {
+		t.Fatalf("skipped and unsampled requests logged %d entries, want none", n)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1?token=secret&page=2", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set(RequestIDHeader, "abc-123")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Level != logrus.WarnLevel {
+		t.Fatalf("access log entry = %+v, want a warning for a 404", entry)
+	}
+	want := map[string]interface{}{
+		"route":      "/users/:id",
+		"status":     http.StatusNotFound,
+		"request_id": "abc-123",
+		"query":      "page=2&token=***",
+		"operation":  "user",
+	}
+	for key, value := range want {
+		if got := entry.Data[key]; got != value {
+			t.Errorf("%s = %v, want %v", key, got, value)
+		}
+	}
+	if got := entry.Data["headers"].(map[string]string)["Authorization"]; got != "***" {
+		t.Errorf("Authorization header = %q, want it masked", got)
+	}
+}
+
+func TestAccessLog_Disabled(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(AccessLogMiddleware(log, config.AccessLogConfig{SampleRate: 1}))
+	engine.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if n := len(hook.AllEntries()); n != 0 {
+		t.Errorf("disabled access log wrote %d entries, want none", n)
+	}
+}
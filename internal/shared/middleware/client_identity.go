@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/azahir21/go-backend-boilerplate/pkg/tlsconfig"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIdentityMiddleware stores the identity of a verified client certificate in the request
+// context, where handlers and resolvers read it with tlsconfig.IdentityFromContext.
+func ClientIdentityMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if id, ok := tlsconfig.IdentityFromState(c.Request.TLS); ok {
+			c.Request = c.Request.WithContext(tlsconfig.ContextWithIdentity(c.Request.Context(), id))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,283 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported content encodings.
+const (
+	EncodingGzip = "gzip"
+	EncodingZstd = "zstd"
+)
+
+var supportedEncodings = map[string]bool{EncodingGzip: true, EncodingZstd: true}
+
+// incompressibleTypes lists content type prefixes that are already compressed.
+var incompressibleTypes = []string{"image/", "video/", "audio/", "application/zip", "application/gzip", "application/zstd", "font/woff"}
+
+// encoder is implemented by the gzip and zstd writers.
+type encoder interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+	Flush() error
+}
+
+// CompressionMiddleware compresses responses of at least cfg.MinSize bytes with the
+// first encoding of cfg.Algorithms that the client accepts. Smaller responses,
+// already-encoded responses and incompressible content types are sent as is.
+func CompressionMiddleware(cfg config.CompressionConfig) gin.HandlerFunc {
+	var algorithms []string
+	for _, algorithm := range cfg.Algorithms {
+		if supportedEncodings[algorithm] {
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+	if len(algorithms) == 0 {
+		algorithms = []string{EncodingZstd, EncodingGzip}
+	}
+	pools := map[string]*sync.Pool{
+		EncodingGzip: {New: func() interface{} {
+			w, _ := gzip.NewWriterLevel(io.Discard, gzipLevel(cfg.Level))
+			return w
+		}},
+		EncodingZstd: {New: func() interface{} {
+			w, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderLevel(zstdLevel(cfg.Level)), zstd.WithEncoderConcurrency(1))
+			return w
+		}},
+	}
+
+	return func(c *gin.Context) {
+		// The representation depends on Accept-Encoding even when it ends up uncompressed.
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), algorithms)
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			status:         http.StatusOK,
+			encoding:       encoding,
+			pool:           pools[encoding],
+			minSize:        cfg.MinSize,
+		}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			if recovered := recover(); recovered != nil {
+				// Let the recovery middleware write its error response instead of the partial one.
+				writer.release()
+				panic(recovered)
+			}
+			writer.finish()
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding returns the first server-preferred encoding with a non-zero
+// quality in the Accept-Encoding header, or "" for an identity response.
+func negotiateEncoding(header string, algorithms []string) string {
+	if header == "" {
+		return ""
+	}
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+	for _, algorithm := range algorithms {
+		q, ok := accepted[algorithm]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > 0 {
+			return algorithm
+		}
+	}
+	return ""
+}
+
+func gzipLevel(level string) int {
+	switch level {
+	case "fastest":
+		return gzip.BestSpeed
+	case "best":
+		return gzip.BestCompression
+	default:
+		return gzip.DefaultCompression
+	}
+}
+
+func zstdLevel(level string) zstd.EncoderLevel {
+	switch level {
+	case "fastest":
+		return zstd.SpeedFastest
+	case "best":
+		return zstd.SpeedBestCompression
+	default:
+		return zstd.SpeedDefault
+	}
+}
+
+// compressWriter buffers the response until it reaches minSize, then switches to
+// compressed streaming. Responses that never reach minSize are written uncompressed.
+type compressWriter struct {
+	gin.ResponseWriter
+	buf      bytes.Buffer
+	status   int
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+	enc      encoder
+	decided  bool
+	written  bool
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	if w.decided {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	w.written = true
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	w.written = true
+	if !w.decided {
+		w.buf.Write(b)
+		if w.buf.Len() >= w.minSize {
+			if err := w.decide(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(b), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Status() int {
+	if w.decided {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *compressWriter) Written() bool {
+	return w.written || w.ResponseWriter.Written()
+}
+
+// Flush compresses whatever has been buffered so far, so that streaming responses keep streaming.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(w.buf.Len() > 0)
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide writes the status line and buffered body, compressed when requested and eligible.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.Header()
+	if compress && w.compressible(header) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		// The encoded representation is not byte-identical, so strong ETags become weak.
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		w.enc = w.pool.Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) compressible(header http.Header) bool {
+	if header.Get("Content-Encoding") != "" || w.status < http.StatusOK ||
+		w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	for _, prefix := range incompressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// finish writes small responses uncompressed and closes the encoder.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		if !w.written && w.status == http.StatusOK {
+			// Nothing was written (e.g. the connection was hijacked); leave the response alone.
+			return
+		}
+		_ = w.decide(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+	}
+	w.release()
+}
+
+// release drops any buffered output and returns the encoder to its pool.
+func (w *compressWriter) release() {
+	w.buf.Reset()
+	if w.enc != nil {
+		w.enc.Reset(io.Discard)
+		w.pool.Put(w.enc)
+		w.enc = nil
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+func newCompressedEngine(body string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(CompressionMiddleware(config.CompressionConfig{MinSize: 64, Algorithms: []string{"zstd", "gzip"}}))
+	engine.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, body)
+	})
+	return engine
+}
+
+func get(engine *gin.Engine, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompression_NegotiatesEncoding(t *testing.T) {
+	body := strings.Repeat("compressible ", 100)
+	engine := newCompressedEngine(body)
+
+	tests := []struct {
+		acceptEncoding string
+		want           string
+		decode         func(io.Reader) (io.Reader, error)
+	}{
+		{"gzip, zstd", "zstd", func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) }},
+		{"gzip", "gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"zstd;q=0, gzip", "gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+	}
+	for _, tt := range tests {
+		w := get(engine, tt.acceptEncoding)
+		if got := w.Header().Get("Content-Encoding"); got != tt.want {
+			t.Errorf("Accept-Encoding %q: Content-Encoding = %q, want %q", tt.acceptEncoding, got, tt.want)
+			continue
+		}
+		r, err := tt.decode(w.Body)
+		if err != nil {
+			t.Fatalf("decoder: %v", err)
+		}
+		decoded, err := io.ReadAll(r)
+		if err != nil || string(decoded) != body {
+			t.Errorf("Accept-Encoding %q: decoded body mismatch (err %v)", tt.acceptEncoding, err)
+		}
+	}
+}
+
+func TestCompression_SkipsSmallResponses(t *testing.T) {
+	w := get(newCompressedEngine("small"), "gzip")
+
+	if enc := w.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want identity for a body below min_size", enc)
+	}
+	if w.Body.String() != "small" {
+		t.Errorf("body = %q, want small", w.Body)
+	}
+}
+
+func TestCompression_IdentityWithoutAcceptEncoding(t *testing.T) {
+	w := get(newCompressedEngine(strings.Repeat("x", 1000)), "")
+
+	if enc := w.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want identity", enc)
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// unmatchedRoute labels requests that matched no route, so that arbitrary paths do not
+// create new time series.
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests by server, method, route template and status code.",
+	}, []string{"server", "method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by server, method and route template.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"server", "method", "route"})
+)
+
+// MetricsMiddleware records the count and latency of requests per route template.
+// server distinguishes the REST and GraphQL servers ("rest", "graphql").
+func MetricsMiddleware(server string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+		httpRequests.WithLabelValues(server, method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpRequestDuration.WithLabelValues(server, method, route).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(MetricsMiddleware("test"))
+	engine.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/no/such/path"} {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := testutil.ToFloat64(httpRequests.WithLabelValues("test", "GET", "/users/:id", "204")); got != 2 {
+		t.Errorf("requests for /users/:id = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(httpRequests.WithLabelValues("test", "GET", unmatchedRoute, "404")); got != 1 {
+		t.Errorf("unmatched requests = %v, want 1", got)
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/ratelimit"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/helper"
+	"github.com/azahir21/go-backend-boilerplate/pkg/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimiter applies a rate limit policy to gin requests.
+type RateLimiter struct {
+	log          *logrus.Logger
+	limiter      ratelimit.Limiter
+	policy       *ratelimit.Policy
+	apiKeyHeader string
+}
+
+// NewRateLimiter creates a RateLimiter for one transport's policy.
+func NewRateLimiter(log *logrus.Logger, limiter ratelimit.Limiter, policy *ratelimit.Policy, apiKeyHeader string) *RateLimiter {
+	return &RateLimiter{log: log, limiter: limiter, policy: policy, apiKeyHeader: apiKeyHeader}
+}
+
+// Middleware limits requests per route, where the route is "METHOD /route/template".
+func (r *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !r.Check(c, c.Request.Method+" "+c.FullPath()) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Check applies the rule for route to the current client, writes the RateLimit-* headers
+// and responds with 429 Too Many Requests when the limit is exceeded.
+// It returns false when the request must not proceed. Limiter failures are logged
+// and the request is allowed through (fail open).
+func (r *RateLimiter) Check(c *gin.Context, route string) bool {
+	rule, ok := r.policy.RuleFor(route)
+	if !ok {
+		return true
+	}
+
+	var userID, apiKey string
+	switch rule.KeyBy {
+	case ratelimit.KeyByUser:
+		userID = bearerUserID(c.GetHeader(authorizationHeader))
+	case ratelimit.KeyByAPIKey:
+		apiKey = c.GetHeader(r.apiKeyHeader)
+	}
+	key := ratelimit.ClientKey(route, rule, c.ClientIP(), userID, apiKey)
+
+	result, err := r.limiter.Allow(c.Request.Context(), key, rule)
+	if err != nil {
+		r.log.WithError(err).WithField("route", route).Warn("Rate limiter unavailable, allowing request")
+		return true
+	}
+
+	for name, value := range ratelimit.Headers(rule, result) {
+		c.Header(name, value)
+	}
+	if !result.Allowed {
+		apperr.Respond(c, apperr.TooManyRequests("Too many requests, please retry later"))
+		return false
+	}
+	return true
+}
+
+// bearerUserID extracts the user ID from a valid bearer token, or returns "" when absent or invalid.
+func bearerUserID(authHeader string) string {
+	if !strings.HasPrefix(authHeader, bearerPrefix) {
+		return ""
+	}
+	claims, err := helper.ValidateToken(strings.TrimPrefix(authHeader, bearerPrefix))
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(claims.UserID), 10)
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader is the header used to propagate request IDs.
+	RequestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+)
+
+// RequestIDMiddleware ensures every request carries a request ID.
+// An incoming X-Request-ID header is reused; otherwise a new UUID is generated.
+// The ID is stored in the context and echoed back in the response header.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the request ID set by RequestIDMiddleware, if any.
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
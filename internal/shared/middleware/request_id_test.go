@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(RequestIDMiddleware())
+	var seen string
+	engine.GET("/", func(c *gin.Context) {
+		seen = GetRequestID(c)
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		reused   bool
+	}{
+		{"generated", "", false},
+		{"reused", "abc-123", true},
+		{"too long", strings.Repeat("a", 129), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			got := w.Header().Get(RequestIDHeader)
+			if got == "" || got != seen {
+				t.Fatalf("response request ID = %q, context request ID = %q, want the same non-empty ID", got, seen)
+			}
+			if reused := got == tt.incoming; reused != tt.reused {
+				t.Errorf("request ID = %q, reused = %v, want %v", got, reused, tt.reused)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// TracingMiddleware starts a server span per request, named after the route template, and
+// continues the trace of an incoming W3C traceparent header. It must run before
+// AccessLogMiddleware and the recovery middleware so that their entries carry the trace ID.
+func TracingMiddleware(service string) gin.HandlerFunc {
+	return otelgin.Middleware(service)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/azahir21/go-backend-boilerplate/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing_AccessLogCarriesTraceID(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(t.Context())
+
+	log, hook := test.NewNullLogger()
+	log.AddHook(logger.TraceHook{})
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(
+		otelgin.Middleware("test", otelgin.WithTracerProvider(provider), otelgin.WithPropagators(propagation.TraceContext{})),
+		AccessLogMiddleware(log, config.AccessLogConfig{Enable: true, SampleRate: 1}),
+	)
+	engine.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "/users/:id" {
+		t.Fatalf("spans = %+v", spans)
+	}
+	entry := hook.LastEntry()
+	if entry == nil || entry.Level != logrus.InfoLevel {
+		t.Fatalf("access log entry = %+v", entry)
+	}
+	if got := entry.Data["trace_id"]; got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace_id = %v, want the incoming trace", got)
+	}
+	if got := entry.Data["span_id"]; got != spans[0].SpanContext.SpanID().String() {
+		t.Errorf("span_id = %v, want %s", got, spans[0].SpanContext.SpanID())
+	}
+}
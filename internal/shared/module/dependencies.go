@@ -5,7 +5,11 @@ import (
 	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
 	"github.com/azahir21/go-backend-boilerplate/infrastructure/db/mongo"
 	"github.com/azahir21/go-backend-boilerplate/infrastructure/external"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/lock"
 	"github.com/azahir21/go-backend-boilerplate/infrastructure/storage"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/jobs"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/outbox"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/scheduler"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
 	"github.com/sirupsen/logrus"
 )
@@ -17,7 +21,20 @@ type Dependencies struct {
 	DBClient    *ent.Client
 	MongoClient *mongo.Client
 	Cache       cache.Cache
+	// CacheTags invalidates tagged cache entries; nil when the cache is disabled.
+	// SQL writes through ent invalidate automatically, other repositories call it after writes.
+	CacheTags cache.Invalidator
+	// Locks coordinates work across replicas with distributed locks.
+	Locks       *lock.Locker
 	Storage     storage.Storage
 	EmailClient external.EmailClient
 	UoW         unitofwork.UnitOfWork
+	// Publisher writes domain events to the outbox inside UnitOfWork.Do.
+	Publisher *outbox.Publisher
+	// Events delivers the domain events published to the outbox to in-process subscribers.
+	Events *outbox.Bus
+	// Jobs enqueues background jobs and registers their handlers; nil when jobs are disabled.
+	Jobs *jobs.Client
+	// Scheduler runs recurring tasks; nil when the scheduler is disabled.
+	Scheduler *scheduler.Scheduler
 }
@@ -0,0 +1,268 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+)
+
+// Infrastructure is a shared dependency a module can require.
+type Infrastructure string
+
+const (
+	SQL     Infrastructure = "sql"
+	Mongo   Infrastructure = "mongo"
+	Cache   Infrastructure = "cache"
+	Storage Infrastructure = "storage"
+	Email   Infrastructure = "email"
+	Jobs    Infrastructure = "jobs"
+)
+
+// describe returns a readable name of the infrastructure and the setting enabling it.
+func (i Infrastructure) describe() (string, string) {
+	switch i {
+	case SQL:
+		return "the SQL database", "database.enable"
+	case Mongo:
+		return "MongoDB", "mongo.enable"
+	case Cache:
+		return "the cache", "cache.enable"
+	case Storage:
+		return "file storage", "storage.enable"
+	case Email:
+		return "email", "email.enable"
+	case Jobs:
+		return "the job queue", "jobs.enable"
+	default:
+		return string(i), ""
+	}
+}
+
+// available reports whether the infrastructure was initialized.
+func (i Infrastructure) available(deps *Dependencies) bool {
+	switch i {
+	case SQL:
+		return deps.DBClient != nil
+	case Mongo:
+		return deps.MongoClient != nil
+	case Cache:
+		return deps.Cache != nil
+	case Storage:
+		return deps.Storage != nil
+	case Email:
+		return deps.EmailClient != nil
+	case Jobs:
+		return deps.Jobs != nil
+	default:
+		return false
+	}
+}
+
+// Definition declares a module: the infrastructure and other modules it requires and the
+// constructors of the delivery layers it provides. A nil constructor means the module does
+// not serve that delivery layer. Subscribe, if set, subscribes the module to domain events
+// through deps.Events, and Jobs registers its job handlers through deps.Jobs; a module with
+// Jobs must require the Jobs infrastructure or check that deps.Jobs is set. Schedule registers recurring tasks through
+// deps.Scheduler; it is only called when the scheduler is enabled.
+type Definition struct {
+	Name      string
+	Requires  []Infrastructure
+	DependsOn []string
+	HTTP      func(deps *Dependencies) HTTPModule
+	GRPC      func(deps *Dependencies) GRPCModule
+	GraphQL   func(deps *Dependencies) GraphQLModule
+	Subscribe func(deps *Dependencies)
+	Jobs      func(deps *Dependencies)
+	Schedule  func(deps *Dependencies)
+}
+
+// Modules are the handlers of the enabled modules, in dependency order.
+type Modules struct {
+	// Enabled names the enabled modules.
+	Enabled []string
+	HTTP    []HTTPModule
+	GRPC    []GRPCModule
+	GraphQL []GraphQLModule
+}
+
+// Registry holds module definitions. Modules add themselves to the default registry with
+// Register from an init function of their config package.
+type Registry struct {
+	mu   sync.Mutex
+	defs map[string]Definition
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{defs: make(map[string]Definition)}
+}
+
+// Register adds a module definition. It panics if the name is empty or already registered.
+func (r *Registry) Register(def Definition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if def.Name == "" {
+		panic("module: Register called with an empty name")
+	}
+	if _, dup := r.defs[def.Name]; dup {
+		panic("module: Register called twice for module " + def.Name)
+	}
+	r.defs[def.Name] = def
+}
+
+// Definitions returns the registered definitions sorted by name.
+func (r *Registry) Definitions() []Definition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defs := make([]Definition, 0, len(r.defs))
+	for _, def := range r.defs {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// Build constructs the handlers of the modules enabled in settings; modules without
+// settings are enabled. It fails, listing every problem, when settings name an unknown
+// module, when an enabled module requires disabled infrastructure or depends on a disabled
+// or unknown module, or when dependencies form a cycle. Dependencies are built before the
+// modules depending on them.
+func (r *Registry) Build(settings map[string]config.ModuleConfig, deps *Dependencies) (*Modules, error) {
+	defs := r.Definitions()
+	byName := make(map[string]Definition, len(defs))
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+	enabled := func(name string) bool {
+		s, ok := settings[name]
+		return !ok || s.Enable
+	}
+
+	var errs []error
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := byName[name]; !ok {
+			errs = append(errs, fmt.Errorf("modules.%s: no module named %q is registered", name, name))
+		}
+	}
+
+	for _, def := range defs {
+		if !enabled(def.Name) {
+			continue
+		}
+		for _, infra := range def.Requires {
+			if !infra.available(deps) {
+				what, setting := infra.describe()
+				errs = append(errs, fmt.Errorf("module %q requires %s, which is disabled (%s); enable it or set modules.%s.enable to false", def.Name, what, setting, def.Name))
+			}
+		}
+		for _, dep := range def.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				errs = append(errs, fmt.Errorf("module %q depends on module %q, which is not registered", def.Name, dep))
+			} else if !enabled(dep) {
+				errs = append(errs, fmt.Errorf("module %q depends on module %q, which is disabled (modules.%s.enable)", def.Name, dep, dep))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	order, err := dependencyOrder(defs, enabled)
+	if err != nil {
+		return nil, err
+	}
+	modules := &Modules{}
+	for _, def := range order {
+		modules.Enabled = append(modules.Enabled, def.Name)
+		if def.Subscribe != nil {
+			def.Subscribe(deps)
+		}
+		if def.Jobs != nil {
+			def.Jobs(deps)
+		}
+		if def.Schedule != nil && deps.Scheduler != nil {
+			def.Schedule(deps)
+		}
+		if def.HTTP != nil {
+			modules.HTTP = append(modules.HTTP, def.HTTP(deps))
+		}
+		if def.GRPC != nil {
+			modules.GRPC = append(modules.GRPC, def.GRPC(deps))
+		}
+		if def.GraphQL != nil {
+			modules.GraphQL = append(modules.GraphQL, def.GraphQL(deps))
+		}
+	}
+	return modules, nil
+}
+
+// dependencyOrder sorts the enabled definitions so that each comes after its dependencies,
+// and by name otherwise.
+func dependencyOrder(defs []Definition, enabled func(string) bool) ([]Definition, error) {
+	byName := make(map[string]Definition, len(defs))
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var order []Definition
+	var visit func(def Definition, path []string) error
+	visit = func(def Definition, path []string) error {
+		switch state[def.Name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("module dependency cycle: %s", strings.Join(append(path, def.Name), " -> "))
+		}
+		state[def.Name] = visiting
+		deps := append([]string(nil), def.DependsOn...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := visit(byName[dep], append(path, def.Name)); err != nil {
+				return err
+			}
+		}
+		state[def.Name] = done
+		order = append(order, def)
+		return nil
+	}
+	for _, def := range defs {
+		if !enabled(def.Name) {
+			continue
+		}
+		if err := visit(def, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds a module definition to the default registry. It panics if the name is
+// empty or already registered.
+func Register(def Definition) {
+	defaultRegistry.Register(def)
+}
+
+// Definitions returns the definitions of the default registry sorted by name.
+func Definitions() []Definition {
+	return defaultRegistry.Definitions()
+}
+
+// Build constructs the enabled modules of the default registry; see Registry.Build.
+func Build(settings map[string]config.ModuleConfig, deps *Dependencies) (*Modules, error) {
+	return defaultRegistry.Build(settings, deps)
+}
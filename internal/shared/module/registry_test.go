@@ -0,0 +1,160 @@
+package module
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	sharedHttp "github.com/azahir21/go-backend-boilerplate/internal/shared/http"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+)
+
+type testHTTPModule string
+
+func (m testHTTPModule) Name() string                       { return string(m) }
+func (m testHTTPModule) HTTPHandler() sharedHttp.HttpRouter { return nil }
+
+func definition(name string, dependsOn ...string) Definition {
+	return Definition{
+		Name:      name,
+		DependsOn: dependsOn,
+		HTTP:      func(*Dependencies) HTTPModule { return testHTTPModule(name) },
+	}
+}
+
+func TestRegistry_Build(t *testing.T) {
+	r := NewRegistry()
+	r.Register(definition("user"))
+	r.Register(definition("orders", "user", "catalog"))
+	r.Register(definition("catalog"))
+	r.Register(definition("reports"))
+
+	modules, err := r.Build(map[string]config.ModuleConfig{"reports": {Enable: false}, "user": {Enable: true}}, &Dependencies{})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if got := strings.Join(modules.Enabled, ","); got != "catalog,user,orders" {
+		t.Errorf("Enabled = %s, want dependencies first: catalog,user,orders", got)
+	}
+	if len(modules.HTTP) != 3 || modules.HTTP[2].Name() != "orders" {
+		t.Errorf("HTTP = %v, want the three enabled modules in dependency order", modules.HTTP)
+	}
+	if len(modules.GRPC) != 0 || len(modules.GraphQL) != 0 {
+		t.Errorf("modules without gRPC and GraphQL constructors provided %v and %v", modules.GRPC, modules.GraphQL)
+	}
+}
+
+func TestRegistry_BuildErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		defs     []Definition
+		settings map[string]config.ModuleConfig
+		deps     *Dependencies
+		want     []string
+	}{
+		{
+			name: "missing infrastructure",
+			defs: []Definition{{Name: "user", Requires: []Infrastructure{SQL, Cache}}},
+			deps: &Dependencies{},
+			want: []string{
+				`module "user" requires the SQL database, which is disabled (database.enable)`,
+				`module "user" requires the cache, which is disabled (cache.enable)`,
+			},
+		},
+		{
+			name:     "disabled dependency",
+			defs:     []Definition{definition("user"), definition("orders", "user")},
+			settings: map[string]config.ModuleConfig{"user": {Enable: false}},
+			want:     []string{`module "orders" depends on module "user", which is disabled (modules.user.enable)`},
+		},
+		{
+			name: "unknown dependency",
+			defs: []Definition{definition("orders", "user")},
+			want: []string{`module "orders" depends on module "user", which is not registered`},
+		},
+		{
+			name:     "unknown module in settings",
+			defs:     []Definition{definition("user")},
+			settings: map[string]config.ModuleConfig{"usr": {Enable: true}},
+			want:     []string{`modules.usr: no module named "usr" is registered`},
+		},
+		{
+			name: "cycle",
+			defs: []Definition{definition("a", "b"), definition("b", "c"), definition("c", "a")},
+			want: []string{"module dependency cycle: a -> b -> c -> a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			for _, def := range tt.defs {
+				r.Register(def)
+			}
+			deps := tt.deps
+			if deps == nil {
+				deps = &Dependencies{}
+			}
+			_, err := r.Build(tt.settings, deps)
+			if err == nil {
+				t.Fatal("Build() succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Build() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_BuildSkipsDisabledRequirements(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Definition{Name: "user", Requires: []Infrastructure{SQL}})
+
+	modules, err := r.Build(map[string]config.ModuleConfig{"user": {Enable: false}}, &Dependencies{})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(modules.Enabled) != 0 {
+		t.Errorf("Enabled = %v, want none", modules.Enabled)
+	}
+
+	r = NewRegistry()
+	r.Register(Definition{Name: "user", Requires: []Infrastructure{SQL}})
+	if _, err := r.Build(nil, &Dependencies{DBClient: &ent.Client{}}); err != nil {
+		t.Errorf("Build() with the SQL database error = %v", err)
+	}
+}
+
+func TestRegistry_RegisterDuplicate(t *testing.T) {
+	r := NewRegistry()
+	r.Register(definition("user"))
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() of a duplicate name did not panic")
+		}
+	}()
+	r.Register(definition("user"))
+}
+
+func TestRegistry_BuildSubscribesEnabledModules(t *testing.T) {
+	var subscribed, jobHandlers []string
+	r := NewRegistry()
+	for _, name := range []string{"user", "reports"} {
+		def := definition(name)
+		def.Subscribe = func(*Dependencies) { subscribed = append(subscribed, name) }
+		def.Jobs = func(*Dependencies) { jobHandlers = append(jobHandlers, name) }
+		def.Schedule = func(*Dependencies) { t.Errorf("module %s registered tasks without a scheduler", name) }
+		r.Register(def)
+	}
+
+	if _, err := r.Build(map[string]config.ModuleConfig{"reports": {Enable: false}}, &Dependencies{}); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(subscribed) != 1 || subscribed[0] != "user" {
+		t.Errorf("subscribed = %v, want only the enabled module", subscribed)
+	}
+	if len(jobHandlers) != 1 || jobHandlers[0] != "user" {
+		t.Errorf("registered job handlers of %v, want only the enabled module", jobHandlers)
+	}
+}
@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// AllEvents subscribes a handler to every event type.
+const AllEvents = "*"
+
+// Handler handles a delivered event. Events are delivered at least once, so handlers must be
+// idempotent; an error makes the dispatcher deliver the event again later.
+type Handler func(ctx context.Context, msg Message) error
+
+// Sink delivers events outside the process, e.g. to a webhook or a message broker.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, msg Message) error
+}
+
+type subscription struct {
+	name    string
+	handler Handler
+}
+
+// Bus delivers events to the in-process subscribers of their type and to every sink.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription
+	sinks         []Sink
+}
+
+// NewBus creates a bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{subscriptions: make(map[string][]subscription)}
+}
+
+// Subscribe registers a handler of events of the given type, or of all events with
+// AllEvents. name identifies the handler in logs and errors.
+func (b *Bus) Subscribe(eventType, name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[eventType] = append(b.subscriptions[eventType], subscription{name: name, handler: h})
+}
+
+// AddSink registers a sink receiving every event.
+func (b *Bus) AddSink(s Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, s)
+}
+
+// Deliver passes msg to its subscribers and the sinks. It calls all of them even if some
+// fail, and returns their joined errors; a failed delivery is retried as a whole.
+func (b *Bus) Deliver(ctx context.Context, msg Message) error {
+	b.mu.RLock()
+	subs := append(append([]subscription(nil), b.subscriptions[msg.Type]...), b.subscriptions[AllEvents]...)
+	sinks := append([]Sink(nil), b.sinks...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subs {
+		if err := call(ctx, msg, s.handler); err != nil {
+			errs = append(errs, fmt.Errorf("subscriber %s: %w", s.name, err))
+		}
+	}
+	for _, s := range sinks {
+		if err := call(ctx, msg, s.Deliver); err != nil {
+			errs = append(errs, fmt.Errorf("sink %s: %w", s.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// call runs h, turning a panic into an error.
+func call(ctx context.Context, msg Message, h Handler) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+		}
+	}()
+	return h(ctx, msg)
+}
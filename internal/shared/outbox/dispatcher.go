@@ -0,0 +1,306 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/outboxevent"
+	"github.com/azahir21/go-backend-boilerplate/ent/predicate"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var tracer = otel.Tracer("github.com/azahir21/go-backend-boilerplate/internal/shared/outbox")
+
+var deliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "outbox_deliveries_total",
+	Help: "Outbox event deliveries by event type and outcome (delivered, retry, dead).",
+}, []string{"event_type", "outcome"})
+
+// Defaults of the settings left empty in the outbox configuration.
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+	defaultMaxAttempts  = 10
+	defaultRetryBackoff = time.Second
+	defaultClaimTimeout = time.Minute
+	defaultWebhookWait  = 10 * time.Second
+)
+
+// maxRetryBackoff caps the exponential wait between deliveries of an event.
+const maxRetryBackoff = time.Hour
+
+// Dispatcher delivers pending outbox events through a bus.
+//
+// Events are read in publication order. An event is claimed before delivery by moving its
+// available_at past the claim timeout, so that dispatchers of other replicas skip it. Later
+// events of an aggregate wait while an earlier one is claimed or waiting for a retry; a
+// dead-lettered event no longer holds them back.
+type Dispatcher struct {
+	client *ent.Client
+	bus    *Bus
+	log    *logrus.Logger
+
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	retryBackoff time.Duration
+	claimTimeout time.Duration
+	retention    time.Duration
+	now          func() time.Time
+
+	stopping chan struct{}
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewDispatcher creates a dispatcher from the outbox configuration.
+func NewDispatcher(client *ent.Client, bus *Bus, log *logrus.Logger, cfg config.OutboxConfig) (*Dispatcher, error) {
+	d := &Dispatcher{
+		client:      client,
+		bus:         bus,
+		log:         log,
+		batchSize:   cfg.BatchSize,
+		maxAttempts: cfg.MaxAttempts,
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+	if d.batchSize <= 0 {
+		d.batchSize = defaultBatchSize
+	}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = defaultMaxAttempts
+	}
+	for _, setting := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+		def   time.Duration
+	}{
+		{"outbox.poll_interval", cfg.PollInterval, &d.pollInterval, defaultPollInterval},
+		{"outbox.retry_backoff", cfg.RetryBackoff, &d.retryBackoff, defaultRetryBackoff},
+		{"outbox.claim_timeout", cfg.ClaimTimeout, &d.claimTimeout, defaultClaimTimeout},
+		{"outbox.retention", cfg.Retention, &d.retention, 0},
+	} {
+		*setting.dst = setting.def
+		if setting.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(setting.value)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid %s %q", setting.name, setting.value)
+		}
+		*setting.dst = v
+	}
+	if d.pollInterval == 0 {
+		return nil, fmt.Errorf("invalid outbox.poll_interval %q", cfg.PollInterval)
+	}
+	return d, nil
+}
+
+// Start runs the dispatcher in the background until Stop.
+func (d *Dispatcher) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.stopping, d.cancel, d.done = make(chan struct{}), cancel, make(chan struct{})
+	go d.run(ctx)
+	return nil
+}
+
+// Stop lets the dispatcher finish the current batch. If ctx ends first, deliveries in progress
+// are cancelled; their events are retried once their claim expires.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	if d.done == nil {
+		return nil
+	}
+	close(d.stopping)
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-d.done
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	defer close(d.done)
+	defer d.cancel()
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for {
+		n, err := d.DispatchOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.log.WithError(err).Warn("Failed to dispatch outbox events")
+		}
+		if n < d.batchSize || err != nil {
+			select {
+			case <-d.stopping:
+				return
+			case <-ticker.C:
+			}
+			continue
+		}
+		// A full batch: keep draining the backlog
+		select {
+		case <-d.stopping:
+			return
+		default:
+		}
+	}
+}
+
+// DispatchOnce delivers one batch of the events available now, and deletes delivered events
+// older than the retention. It returns the number of events claimed.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	now := d.now()
+	if d.retention > 0 {
+		if _, err := d.client.OutboxEvent.Delete().
+			Where(outboxevent.StatusEQ(StatusDelivered), outboxevent.DeliveredAtLT(now.Add(-d.retention))).
+			Exec(ctx); err != nil {
+			return 0, fmt.Errorf("failed to delete delivered events: %w", err)
+		}
+	}
+
+	events, err := d.client.OutboxEvent.Query().
+		Where(
+			outboxevent.StatusEQ(StatusPending),
+			outboxevent.AvailableAtLTE(now),
+			notBehindWaitingEvent(now),
+		).
+		Order(ent.Asc(outboxevent.FieldID)).
+		Limit(d.batchSize).
+		All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read pending events: %w", err)
+	}
+
+	// Aggregates with an event that was claimed elsewhere or is scheduled for a retry meanwhile
+	blocked := make(map[[2]string]bool)
+	claimedEvents := 0
+	for _, e := range events {
+		aggregate := [2]string{e.AggregateType, e.AggregateID}
+		if blocked[aggregate] {
+			continue
+		}
+		claimed, err := d.client.OutboxEvent.Update().
+			Where(outboxevent.ID(e.ID), outboxevent.StatusEQ(StatusPending), outboxevent.AvailableAtLTE(now)).
+			SetAvailableAt(now.Add(d.claimTimeout)).
+			Save(ctx)
+		if err != nil {
+			return claimedEvents, fmt.Errorf("failed to claim event %d: %w", e.ID, err)
+		}
+		if claimed == 0 {
+			blocked[aggregate] = true
+			continue
+		}
+		claimedEvents++
+		retrying, err := d.deliver(ctx, e)
+		if err != nil {
+			return claimedEvents, err
+		}
+		if retrying {
+			blocked[aggregate] = true
+		}
+	}
+	return claimedEvents, nil
+}
+
+// notBehindWaitingEvent excludes the events queued behind an earlier pending event of their
+// aggregate that waits for a retry or is claimed elsewhere, so that the events of an aggregate
+// are delivered in order and waiting aggregates do not fill the batch.
+func notBehindWaitingEvent(now time.Time) predicate.OutboxEvent {
+	return func(s *sql.Selector) {
+		earlier := sql.Table(outboxevent.Table).As("earlier")
+		s.Where(sql.NotExists(
+			sql.Select(earlier.C(outboxevent.FieldID)).
+				From(earlier).
+				Where(sql.And(
+					sql.ColumnsEQ(earlier.C(outboxevent.FieldAggregateType), s.C(outboxevent.FieldAggregateType)),
+					sql.ColumnsEQ(earlier.C(outboxevent.FieldAggregateID), s.C(outboxevent.FieldAggregateID)),
+					sql.ColumnsLT(earlier.C(outboxevent.FieldID), s.C(outboxevent.FieldID)),
+					sql.EQ(earlier.C(outboxevent.FieldStatus), StatusPending),
+					sql.GT(earlier.C(outboxevent.FieldAvailableAt), now),
+				)),
+		))
+	}
+}
+
+// deliver passes a claimed event to the bus and records the outcome. It reports whether the
+// event is scheduled for a retry.
+func (d *Dispatcher) deliver(ctx context.Context, e *ent.OutboxEvent) (bool, error) {
+	msg := toMessage(e)
+	spanCtx, span := tracer.Start(ctx, "outbox.deliver")
+	span.SetAttributes(
+		attribute.String("outbox.event_type", msg.Type),
+		attribute.Int("outbox.event_id", msg.ID),
+		attribute.Int("outbox.attempt", msg.Attempt),
+	)
+	deliverErr := d.bus.Deliver(spanCtx, msg)
+	tracing.End(span, deliverErr)
+	if deliverErr != nil && ctx.Err() != nil {
+		// Stopping: the claim expires and the event is delivered again
+		return false, ctx.Err()
+	}
+
+	now := d.now()
+	update := d.client.OutboxEvent.UpdateOneID(e.ID).SetAttempts(msg.Attempt)
+	log := d.log.WithContext(ctx).WithFields(logrus.Fields{
+		"event_id":   msg.ID,
+		"event_type": msg.Type,
+		"attempt":    msg.Attempt,
+	})
+	retrying := false
+	switch {
+	case deliverErr == nil:
+		update.SetStatus(StatusDelivered).SetDeliveredAt(now).ClearLastError()
+		deliveries.WithLabelValues(msg.Type, "delivered").Inc()
+	case msg.Attempt >= d.maxAttempts:
+		update.SetStatus(StatusDead).SetLastError(deliverErr.Error())
+		deliveries.WithLabelValues(msg.Type, "dead").Inc()
+		log.WithError(deliverErr).Error("Outbox event dead-lettered after its last delivery attempt")
+	default:
+		backoff := d.retryBackoff << (msg.Attempt - 1)
+		if backoff > maxRetryBackoff || backoff <= 0 {
+			backoff = maxRetryBackoff
+		}
+		update.SetAvailableAt(now.Add(backoff)).SetLastError(deliverErr.Error())
+		deliveries.WithLabelValues(msg.Type, "retry").Inc()
+		log.WithError(deliverErr).Warnf("Outbox event delivery failed, retrying in %s", backoff)
+		retrying = true
+	}
+	if err := update.Exec(ctx); err != nil {
+		return retrying, fmt.Errorf("failed to record the delivery of event %d: %w", e.ID, err)
+	}
+	return retrying, nil
+}
+
+// DeadLetters returns up to limit dead-lettered events, oldest first.
+func DeadLetters(ctx context.Context, client *ent.Client, limit int) ([]*ent.OutboxEvent, error) {
+	return client.OutboxEvent.Query().
+		Where(outboxevent.StatusEQ(StatusDead)).
+		Order(ent.Asc(outboxevent.FieldID)).
+		Limit(limit).
+		All(ctx)
+}
+
+// Requeue moves dead-lettered events back to pending with a fresh number of attempts, all of
+// them if no IDs are given. It returns the number of requeued events.
+func Requeue(ctx context.Context, client *ent.Client, ids ...int) (int, error) {
+	update := client.OutboxEvent.Update().Where(outboxevent.StatusEQ(StatusDead))
+	if len(ids) > 0 {
+		update.Where(outboxevent.IDIn(ids...))
+	}
+	return update.
+		SetStatus(StatusPending).
+		SetAttempts(0).
+		SetAvailableAt(time.Now().UTC()).
+		Save(ctx)
+}
@@ -0,0 +1,113 @@
+// Package outbox implements a transactional outbox: domain events are written to the
+// outbox_events table in the transaction of the change they describe, and a dispatcher
+// delivers them to in-process subscribers and external sinks at least once, in order per
+// aggregate, with retries and dead-lettering.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
+)
+
+// Statuses of outbox events.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// ErrNoTransaction is returned by Publish outside UnitOfWork.Do.
+var ErrNoTransaction = errors.New("outbox: events must be published inside UnitOfWork.Do")
+
+// Event is a domain event, e.g. a registered user.
+type Event struct {
+	// AggregateType and AggregateID identify the entity the event is about. Events of the
+	// same aggregate are delivered in the order they were published.
+	AggregateType string
+	AggregateID   string
+	// Type names the event, e.g. "user.registered". Subscribers select events by type.
+	Type string
+	// Payload is encoded as JSON.
+	Payload any
+}
+
+// Message is an event read back from the outbox for delivery.
+type Message struct {
+	ID            int             `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	// Attempt counts the deliveries of the event, starting at 1.
+	Attempt int `json:"attempt"`
+}
+
+// Decode unmarshals the payload into v.
+func (m Message) Decode(v any) error {
+	return json.Unmarshal(m.Payload, v)
+}
+
+// Publisher writes domain events to the outbox. Modules get it as module.Dependencies.Publisher.
+type Publisher struct {
+	enabled bool
+}
+
+// NewPublisher creates a Publisher. A disabled publisher (outbox.enable off) discards events,
+// as no dispatcher would ever deliver them.
+func NewPublisher(enabled bool) *Publisher {
+	return &Publisher{enabled: enabled}
+}
+
+// Publish writes events to the outbox in the transaction carried by ctx, so that they are
+// delivered if and only if the transaction commits. It returns ErrNoTransaction outside
+// UnitOfWork.Do.
+func (p *Publisher) Publish(ctx context.Context, events ...Event) error {
+	if !unitofwork.InTransaction(ctx) {
+		return ErrNoTransaction
+	}
+	if !p.enabled {
+		return nil
+	}
+	client := unitofwork.ClientFromContext(ctx, nil)
+	now := time.Now().UTC()
+	builders := make([]*ent.OutboxEventCreate, len(events))
+	for i, e := range events {
+		if e.Type == "" || e.AggregateType == "" {
+			return fmt.Errorf("outbox: event %d has no type or aggregate type", i)
+		}
+		payload, err := json.Marshal(e.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s event: %w", e.Type, err)
+		}
+		builders[i] = client.OutboxEvent.Create().
+			SetAggregateType(e.AggregateType).
+			SetAggregateID(e.AggregateID).
+			SetEventType(e.Type).
+			SetPayload(payload).
+			SetAvailableAt(now).
+			SetCreatedAt(now)
+	}
+	if err := client.OutboxEvent.CreateBulk(builders...).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to write events to the outbox: %w", err)
+	}
+	return nil
+}
+
+func toMessage(e *ent.OutboxEvent) Message {
+	return Message{
+		ID:            e.ID,
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateID,
+		Type:          e.EventType,
+		Payload:       e.Payload,
+		OccurredAt:    e.CreatedAt,
+		Attempt:       e.Attempts + 1,
+	}
+}
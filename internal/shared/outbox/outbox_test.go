@@ -0,0 +1,307 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/enttest"
+	"github.com/azahir21/go-backend-boilerplate/ent/outboxevent"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestClient(t *testing.T) (*ent.Client, unitofwork.UnitOfWork, *logrus.Logger) {
+	t.Helper()
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return client, unitofwork.NewUnitOfWork(client, log), log
+}
+
+func publish(t *testing.T, uow unitofwork.UnitOfWork, events ...Event) {
+	t.Helper()
+	if err := uow.Do(context.Background(), func(ctx context.Context, _ unitofwork.UnitOfWork) error {
+		return NewPublisher(true).Publish(ctx, events...)
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+	client, uow, _ := newTestClient(t)
+	publisher := NewPublisher(true)
+
+	if err := publisher.Publish(ctx, Event{AggregateType: "user", AggregateID: "1", Type: "user.registered"}); !errors.Is(err, ErrNoTransaction) {
+		t.Errorf("Publish() outside a transaction error = %v, want ErrNoTransaction", err)
+	}
+
+	_ = uow.Do(ctx, func(ctx context.Context, _ unitofwork.UnitOfWork) error {
+		if err := publisher.Publish(ctx, Event{AggregateType: "user", AggregateID: "1", Type: "user.registered"}); err != nil {
+			t.Fatal(err)
+		}
+		return errors.New("abort")
+	})
+	if n := client.OutboxEvent.Query().CountX(ctx); n != 0 {
+		t.Errorf("%d events stored despite the rollback", n)
+	}
+
+	publish(t, uow, Event{AggregateType: "user", AggregateID: "1", Type: "user.registered", Payload: map[string]string{"name": "alice"}})
+	e := client.OutboxEvent.Query().OnlyX(ctx)
+	var payload map[string]string
+	if err := toMessage(e).Decode(&payload); err != nil || payload["name"] != "alice" {
+		t.Errorf("payload = %v, %v, want name alice", payload, err)
+	}
+	if e.Status != StatusPending {
+		t.Errorf("status = %s, want %s", e.Status, StatusPending)
+	}
+
+	if err := uow.Do(ctx, func(ctx context.Context, _ unitofwork.UnitOfWork) error {
+		return NewPublisher(false).Publish(ctx, Event{AggregateType: "user", AggregateID: "2", Type: "user.registered"})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n := client.OutboxEvent.Query().CountX(ctx); n != 1 {
+		t.Errorf("%d events stored, want the event of the disabled publisher discarded", n)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	client, uow, log := newTestClient(t)
+	publish(t, uow,
+		Event{AggregateType: "user", AggregateID: "1", Type: "user.registered"},
+		Event{AggregateType: "user", AggregateID: "2", Type: "user.registered"},
+		Event{AggregateType: "user", AggregateID: "1", Type: "user.renamed"},
+	)
+
+	bus := NewBus()
+	var delivered []string
+	failUser1 := true
+	bus.Subscribe(AllEvents, "recorder", func(_ context.Context, msg Message) error {
+		if msg.AggregateID == "1" && failUser1 {
+			return errors.New("unavailable")
+		}
+		delivered = append(delivered, msg.AggregateID+":"+msg.Type)
+		return nil
+	})
+	d, err := NewDispatcher(client, bus, log, config.OutboxConfig{MaxAttempts: 2, RetryBackoff: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Now().UTC()
+	d.now = func() time.Time { return clock }
+
+	// The failure of user 1 holds back its later event, but not user 2
+	if _, err := d.DispatchOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(delivered, ","); got != "2:user.registered" {
+		t.Errorf("delivered = %s, want 2:user.registered", got)
+	}
+
+	// Not yet due for a retry
+	if _, err := d.DispatchOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 1 {
+		t.Errorf("delivered %v before the retry backoff elapsed", delivered)
+	}
+
+	// The second failure dead-letters the event, which releases the next one
+	clock = clock.Add(2 * time.Minute)
+	failUser1 = false
+	bus.Subscribe("user.registered", "failing", func(_ context.Context, msg Message) error {
+		if msg.AggregateID == "1" {
+			return errors.New("still unavailable")
+		}
+		return nil
+	})
+	if _, err := d.DispatchOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(delivered, ","); got != "2:user.registered,1:user.registered,1:user.renamed" {
+		t.Errorf("delivered = %s", got)
+	}
+	dead, err := DeadLetters(ctx, client, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || dead[0].Attempts != 2 || !strings.Contains(dead[0].LastError, "still unavailable") {
+		t.Fatalf("dead letters = %+v, want the first event of user 1 after 2 attempts", dead)
+	}
+
+	if n, err := Requeue(ctx, client); err != nil || n != 1 {
+		t.Fatalf("Requeue() = %d, %v, want 1", n, err)
+	}
+	requeued := client.OutboxEvent.GetX(ctx, dead[0].ID)
+	if requeued.Status != StatusPending || requeued.Attempts != 0 {
+		t.Errorf("requeued event = %s after %d attempts, want pending with none", requeued.Status, requeued.Attempts)
+	}
+}
+
+func TestDispatcher_SkipsClaimedEvents(t *testing.T) {
+	ctx := context.Background()
+	client, uow, log := newTestClient(t)
+	publish(t, uow, Event{AggregateType: "user", AggregateID: "1", Type: "user.registered"})
+
+	calls := 0
+	bus := NewBus()
+	bus.Subscribe(AllEvents, "counter", func(context.Context, Message) error { calls++; return nil })
+	d, err := NewDispatcher(client, bus, log, config.OutboxConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another replica claimed the event
+	client.OutboxEvent.Update().SetAvailableAt(time.Now().UTC().Add(time.Minute)).ExecX(ctx)
+	if _, err := d.DispatchOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Errorf("a claimed event was delivered %d times", calls)
+	}
+}
+
+func TestDispatcher_SkipsWaitingEvents(t *testing.T) {
+	ctx := context.Background()
+	client, uow, log := newTestClient(t)
+	publish(t, uow,
+		Event{AggregateType: "user", AggregateID: "1", Type: "user.registered"},
+		Event{AggregateType: "user", AggregateID: "2", Type: "user.registered"},
+		Event{AggregateType: "user", AggregateID: "2", Type: "user.renamed"},
+		Event{AggregateType: "user", AggregateID: "3", Type: "user.registered"},
+	)
+
+	var delivered []string
+	bus := NewBus()
+	bus.Subscribe(AllEvents, "recorder", func(_ context.Context, msg Message) error {
+		delivered = append(delivered, msg.AggregateID+":"+msg.Type)
+		return nil
+	})
+	d, err := NewDispatcher(client, bus, log, config.OutboxConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.batchSize = 2
+
+	// A full batch of events backing off before a retry, one holding back the next event of
+	// its aggregate
+	clock := time.Now().UTC()
+	d.now = func() time.Time { return clock }
+	client.OutboxEvent.Update().
+		Where(outboxevent.AggregateIDIn("1", "2"), outboxevent.EventTypeEQ("user.registered")).
+		SetAvailableAt(clock.Add(time.Minute)).
+		ExecX(ctx)
+
+	n, err := d.DispatchOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(delivered, ","); n != 1 || got != "3:user.registered" {
+		t.Errorf("DispatchOnce() claimed %d events and delivered %s, want only the due event of user 3", n, got)
+	}
+}
+
+func TestDispatcher_Retention(t *testing.T) {
+	ctx := context.Background()
+	client, uow, log := newTestClient(t)
+	publish(t, uow, Event{AggregateType: "user", AggregateID: "1", Type: "user.registered"})
+
+	d, err := NewDispatcher(client, NewBus(), log, config.OutboxConfig{Retention: "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Now().UTC()
+	d.now = func() time.Time { return clock }
+	if _, err := d.DispatchOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if e := client.OutboxEvent.Query().OnlyX(ctx); e.Status != StatusDelivered {
+		t.Fatalf("status = %s, want %s", e.Status, StatusDelivered)
+	}
+	clock = clock.Add(2 * time.Hour)
+	if _, err := d.DispatchOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := client.OutboxEvent.Query().CountX(ctx); n != 0 {
+		t.Errorf("%d delivered events kept past the retention", n)
+	}
+}
+
+func TestNewDispatcher_InvalidConfig(t *testing.T) {
+	for _, cfg := range []config.OutboxConfig{
+		{PollInterval: "often"},
+		{PollInterval: "0s"},
+		{RetryBackoff: "-1s"},
+		{Retention: "a week"},
+	} {
+		if _, err := NewDispatcher(nil, NewBus(), logrus.New(), cfg); err == nil {
+			t.Errorf("NewDispatcher(%+v) succeeded, want an error", cfg)
+		}
+	}
+}
+
+func TestBus_Deliver(t *testing.T) {
+	bus := NewBus()
+	var calls []string
+	bus.Subscribe("user.registered", "welcome", func(context.Context, Message) error {
+		calls = append(calls, "welcome")
+		return nil
+	})
+	bus.Subscribe("user.deleted", "cleanup", func(context.Context, Message) error {
+		calls = append(calls, "cleanup")
+		return nil
+	})
+	bus.Subscribe(AllEvents, "audit", func(context.Context, Message) error {
+		calls = append(calls, "audit")
+		panic("audit log full")
+	})
+
+	err := bus.Deliver(context.Background(), Message{Type: "user.registered"})
+	if err == nil || !strings.Contains(err.Error(), "subscriber audit: panic: audit log full") {
+		t.Errorf("Deliver() error = %v, want the panic of the audit subscriber", err)
+	}
+	if got := strings.Join(calls, ","); got != "welcome,audit" {
+		t.Errorf("called %s, want welcome,audit", got)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var got *http.Request
+	var body string
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got, body = r, string(b)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(config.OutboxWebhookConfig{URL: server.URL, Timeout: "1s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := Message{ID: 7, Type: "user.registered", Payload: []byte(`{"id":1}`)}
+	if err := sink.Deliver(context.Background(), msg); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if got.Header.Get("Idempotency-Key") != "7" || !strings.Contains(body, `"payload":{"id":1}`) {
+		t.Errorf("request = %v %s, want the event as JSON with its ID as idempotency key", got.Header, body)
+	}
+
+	status = http.StatusBadGateway
+	if err := sink.Deliver(context.Background(), msg); err == nil {
+		t.Error("Deliver() succeeded on a 502 response")
+	}
+}
@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+)
+
+// WebhookSink posts every event as JSON to an HTTP endpoint. The event ID is sent in the
+// Idempotency-Key header, so that the receiver can discard redeliveries.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a sink from the webhook configuration of the outbox.
+func NewWebhookSink(cfg config.OutboxWebhookConfig) (*WebhookSink, error) {
+	timeout := defaultWebhookWait
+	if cfg.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(cfg.Timeout); err != nil {
+			return nil, fmt.Errorf("invalid outbox.webhook.timeout: %w", err)
+		}
+	}
+	return &WebhookSink{url: cfg.URL, client: &http.Client{Timeout: timeout}}, nil
+}
+
+// Name returns the name of the sink.
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Deliver posts msg and fails unless the endpoint answers with a 2xx status.
+func (s *WebhookSink) Deliver(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", strconv.Itoa(msg.ID))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered %s", s.url, resp.Status)
+	}
+	return nil
+}
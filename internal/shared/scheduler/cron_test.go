@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// Wednesday 15 January 2025, 10:07:30 UTC
+	from := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"* * * * *", "2025-01-15T10:08:00Z"},
+		{"*/15 * * * *", "2025-01-15T10:15:00Z"},
+		{"5/20 * * * *", "2025-01-15T10:25:00Z"},
+		{"0 3 * * *", "2025-01-16T03:00:00Z"},
+		{"30 9-17 * * mon-fri", "2025-01-15T10:30:00Z"},
+		{"0 0 * * sat,sun", "2025-01-18T00:00:00Z"},
+		{"0 0 * * 7", "2025-01-19T00:00:00Z"},
+		{"0 12 1 * *", "2025-02-01T12:00:00Z"},
+		{"0 0 29 feb *", "2028-02-29T00:00:00Z"},
+		// Either a restricted day of month or a restricted day of week
+		{"0 0 20 * fri", "2025-01-17T00:00:00Z"},
+		{"@hourly", "2025-01-15T11:00:00Z"},
+		{"@daily", "2025-01-16T00:00:00Z"},
+		{"@weekly", "2025-01-19T00:00:00Z"},
+		{"@monthly", "2025-02-01T00:00:00Z"},
+		{"@yearly", "2026-01-01T00:00:00Z"},
+		{"@every 90s", "2025-01-15T10:09:00Z"},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(from).Format(time.RFC3339); got != tt.want {
+			t.Errorf("ParseSchedule(%q).Next() = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseSchedule_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+7", 7*60*60)
+	s, err := ParseSchedule("0 3 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2025, time.January, 15, 10, 0, 0, 0, time.UTC).In(loc)
+	if got := s.Next(from).UTC().Format(time.RFC3339); got != "2025-01-15T20:00:00Z" {
+		t.Errorf("Next() = %s, want 03:00 in UTC+7", got)
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"10-5 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every 1ms",
+		"@every later",
+		"@often",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestParseSchedule_Never(t *testing.T) {
+	s, err := ParseSchedule("0 0 31 feb *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Next() = %s for a date that does not exist, want the zero time", next)
+	}
+}
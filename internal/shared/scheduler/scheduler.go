@@ -0,0 +1,437 @@
+// Package scheduler runs recurring tasks registered by modules with cron expressions. Every
+// replica runs a scheduler; the one holding the leader lock runs the tasks, each under a
+// lock of its own so that a run is never overlapped by another, including a manual one. Runs
+// are recorded in the scheduled_runs table.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/scheduledrun"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/lock"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var tracer = otel.Tracer("github.com/azahir21/go-backend-boilerplate/internal/shared/scheduler")
+
+var (
+	runs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduler_runs_total",
+		Help: "Runs of scheduled tasks by task and outcome (succeeded, failed, skipped).",
+	}, []string{"task", "outcome"})
+
+	leaderGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "scheduler_leader",
+		Help: "1 if this instance holds the scheduler leader lease.",
+	})
+)
+
+// Triggers and statuses of runs.
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// ErrTaskRunning is returned by Run while the task runs elsewhere.
+var ErrTaskRunning = errors.New("scheduler: the task is already running")
+
+// Defaults of the settings left empty in the scheduler configuration.
+const (
+	defaultLeaseTTL = 30 * time.Second
+	defaultTimeout  = time.Hour
+)
+
+// tickInterval is how often the scheduler renews its lease and checks for due tasks.
+const tickInterval = time.Second
+
+// taskLeaseMargin keeps the lease of a task past its timeout, so that a run cancelled at the
+// timeout has finished before another can start.
+const taskLeaseMargin = time.Minute
+
+// Names of the locks of the leader and of each task.
+const (
+	leaderLock     = "scheduler:leader"
+	taskLockPrefix = "scheduler:task:"
+)
+
+// Func is the work of a task. A run is not retried on failure; the task runs again at its
+// next scheduled time.
+type Func func(ctx context.Context) error
+
+// Task is a registered task.
+type Task struct {
+	Name     string
+	Spec     string
+	Timeout  time.Duration
+	schedule Schedule
+	run      Func
+}
+
+// Next returns the first scheduled run time of the task after t.
+func (t *Task) Next(after time.Time) time.Time {
+	return t.schedule.Next(after)
+}
+
+// TaskOption customizes a registered task.
+type TaskOption func(*Task)
+
+// WithTimeout cancels the context of the task after d, instead of scheduler.timeout.
+func WithTimeout(d time.Duration) TaskOption {
+	return func(t *Task) { t.Timeout = d }
+}
+
+// Scheduler runs the registered tasks on their schedules while it leads.
+type Scheduler struct {
+	client   *ent.Client
+	locker   *lock.Locker
+	log      *logrus.Logger
+	instance string
+
+	location  *time.Location
+	leaseTTL  time.Duration
+	timeout   time.Duration
+	retention time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	tasks map[string]*Task
+	wg    sync.WaitGroup
+
+	// State of the loop
+	leader bool
+	// lease is the leader lease, kept while a renewal fails in case the lease survives
+	lease     *lock.Lease
+	renewedAt time.Time
+	next      map[string]time.Time
+
+	stopping chan struct{}
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// New creates a scheduler recording its runs with client and taking its locks from locker.
+// With a history retention it registers the scheduler.purge_history task.
+func New(client *ent.Client, locker *lock.Locker, log *logrus.Logger, cfg config.SchedulerConfig) (*Scheduler, error) {
+	location := time.UTC
+	if cfg.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid scheduler.timezone: %w", err)
+		}
+	}
+	hostname, _ := os.Hostname()
+	s := &Scheduler{
+		client:   client,
+		locker:   locker,
+		log:      log,
+		instance: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		location: location,
+		now:      func() time.Time { return time.Now().UTC() },
+		tasks:    make(map[string]*Task),
+	}
+	for _, setting := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+		def   time.Duration
+	}{
+		{"scheduler.lease_ttl", cfg.LeaseTTL, &s.leaseTTL, defaultLeaseTTL},
+		{"scheduler.timeout", cfg.Timeout, &s.timeout, defaultTimeout},
+		{"scheduler.history_retention", cfg.HistoryRetention, &s.retention, 0},
+	} {
+		*setting.dst = setting.def
+		if setting.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(setting.value)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid %s %q", setting.name, setting.value)
+		}
+		*setting.dst = v
+	}
+	if s.leaseTTL < 3*tickInterval {
+		return nil, fmt.Errorf("invalid scheduler.lease_ttl %q: it must be at least %s", cfg.LeaseTTL, 3*tickInterval)
+	}
+	if s.retention > 0 {
+		s.Register("scheduler.purge_history", "@daily", s.purgeHistory)
+	}
+	return s, nil
+}
+
+// Register adds a task running fn on the schedule given by spec; see ParseSchedule. It
+// panics if the name is taken or spec is invalid.
+func (s *Scheduler) Register(name, spec string, fn Func, opts ...TaskOption) {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		panic(fmt.Sprintf("scheduler: task %s: %v", name, err))
+	}
+	t := &Task{Name: name, Spec: spec, Timeout: s.timeout, schedule: schedule, run: fn}
+	for _, opt := range opts {
+		opt(t)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, dup := s.tasks[name]; dup {
+		panic("scheduler: Register called twice for task " + name)
+	}
+	s.tasks[name] = t
+}
+
+// Tasks returns the registered tasks sorted by name.
+func (s *Scheduler) Tasks() []*Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := make([]*Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name < tasks[j].Name })
+	return tasks
+}
+
+// Location returns the location in which schedules are evaluated.
+func (s *Scheduler) Location() *time.Location {
+	return s.location
+}
+
+// Start runs the scheduler in the background until Stop.
+func (s *Scheduler) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopping, s.cancel, s.done = make(chan struct{}), cancel, make(chan struct{})
+	go s.loop(ctx)
+	return nil
+}
+
+// Stop stops starting tasks, waits for the running ones and releases the leader lock. If
+// ctx ends first, the running tasks are cancelled.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.done == nil {
+		return nil
+	}
+	close(s.stopping)
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.done)
+	defer s.cancel()
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	for {
+		s.tick(ctx)
+		select {
+		case <-s.stopping:
+			s.wg.Wait()
+			if s.leader {
+				s.setLeader(false)
+			}
+			if s.lease != nil {
+				if err := s.lease.Release(context.WithoutCancel(ctx)); err != nil && !errors.Is(err, lock.ErrNotHeld) {
+					s.log.WithError(err).Warn("Failed to release the scheduler leader lock")
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick renews or takes the leader lease when due, and starts the due tasks while leading.
+func (s *Scheduler) tick(ctx context.Context) {
+	now := s.now().In(s.location)
+	if !s.leader || now.Sub(s.renewedAt) >= s.leaseTTL/3 {
+		held := s.lead(ctx)
+		if held {
+			s.renewedAt = now
+		}
+		if held != s.leader {
+			s.setLeader(held)
+			if held {
+				s.next = make(map[string]time.Time)
+				for _, t := range s.Tasks() {
+					s.next[t.Name] = t.Next(now)
+				}
+			}
+		}
+	}
+	if !s.leader {
+		return
+	}
+
+	for _, t := range s.Tasks() {
+		next, ok := s.next[t.Name]
+		if !ok {
+			// Registered after the leadership began
+			s.next[t.Name] = t.Next(now)
+			continue
+		}
+		if next.IsZero() || now.Before(next) {
+			continue
+		}
+		s.next[t.Name] = t.Next(now)
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			if err := s.run(ctx, t, TriggerSchedule); errors.Is(err, ErrTaskRunning) {
+				s.log.WithField("task", t.Name).Warn("Scheduled task skipped: its previous run has not finished")
+			}
+		}()
+	}
+}
+
+// lead renews the leader lease, or takes the leader lock if this instance has no lease, and
+// reports whether the instance holds the lock.
+func (s *Scheduler) lead(ctx context.Context) bool {
+	if s.lease != nil {
+		err := s.lease.Renew(ctx)
+		if err == nil {
+			return true
+		}
+		if !errors.Is(err, lock.ErrNotHeld) {
+			// The lease may outlive the failure; the next tick renews it again
+			s.log.WithError(err).Warn("Failed to renew the scheduler leader lease")
+			return false
+		}
+		s.lease = nil
+	}
+	lease, err := s.locker.TryLock(ctx, leaderLock, s.leaseTTL)
+	if err != nil {
+		if !errors.Is(err, lock.ErrLocked) {
+			s.log.WithError(err).Warn("Failed to acquire the scheduler leader lock")
+		}
+		return false
+	}
+	s.lease = lease
+	return true
+}
+
+func (s *Scheduler) setLeader(leader bool) {
+	s.leader = leader
+	if leader {
+		leaderGauge.Set(1)
+		s.log.Infof("Instance %s leads the scheduler", s.instance)
+	} else {
+		leaderGauge.Set(0)
+		s.log.Infof("Instance %s no longer leads the scheduler", s.instance)
+	}
+}
+
+// Run runs the task now, whether or not this instance leads, and returns its error. It
+// returns ErrTaskRunning if the task is running elsewhere.
+func (s *Scheduler) Run(ctx context.Context, name string) error {
+	s.mu.Lock()
+	t, ok := s.tasks[name]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("scheduler: no task named %q is registered", name)
+	}
+	return s.run(ctx, t, TriggerManual)
+}
+
+// run runs a task under its lock and records the run.
+func (s *Scheduler) run(ctx context.Context, t *Task, trigger string) error {
+	lease, err := s.locker.TryLock(ctx, taskLockPrefix+t.Name, t.Timeout+taskLeaseMargin)
+	if errors.Is(err, lock.ErrLocked) {
+		runs.WithLabelValues(t.Name, "skipped").Inc()
+		return ErrTaskRunning
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lease.Release(context.WithoutCancel(ctx)); err != nil {
+			s.log.WithError(err).Warnf("Failed to release the lock of task %s", t.Name)
+		}
+	}()
+
+	record, err := s.client.ScheduledRun.Create().
+		SetTask(t.Name).
+		SetTrigger(trigger).
+		SetInstance(s.instance).
+		SetStartedAt(s.now()).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to record the run of task %s: %w", t.Name, err)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, t.Timeout)
+	runCtx, span := tracer.Start(runCtx, "scheduler.run")
+	span.SetAttributes(
+		attribute.String("scheduler.task", t.Name),
+		attribute.String("scheduler.trigger", trigger),
+	)
+	runErr := call(runCtx, t.run)
+	tracing.End(span, runErr)
+	cancel()
+
+	log := s.log.WithContext(ctx).WithFields(logrus.Fields{"task": t.Name, "trigger": trigger})
+	update := s.client.ScheduledRun.UpdateOne(record).SetFinishedAt(s.now())
+	if runErr != nil {
+		update.SetStatus(StatusFailed).SetError(runErr.Error())
+		runs.WithLabelValues(t.Name, "failed").Inc()
+		log.WithError(runErr).Error("Scheduled task failed")
+	} else {
+		update.SetStatus(StatusSucceeded)
+		runs.WithLabelValues(t.Name, "succeeded").Inc()
+		log.Info("Scheduled task succeeded")
+	}
+	if err := update.Exec(context.WithoutCancel(ctx)); err != nil {
+		log.WithError(err).Error("Failed to record the outcome of a scheduled task")
+	}
+	return runErr
+}
+
+// History returns up to limit runs, of the task if not empty, newest first.
+func (s *Scheduler) History(ctx context.Context, task string, limit int) ([]*ent.ScheduledRun, error) {
+	query := s.client.ScheduledRun.Query()
+	if task != "" {
+		query.Where(scheduledrun.TaskEQ(task))
+	}
+	return query.Order(ent.Desc(scheduledrun.FieldStartedAt), ent.Desc(scheduledrun.FieldID)).Limit(limit).All(ctx)
+}
+
+// purgeHistory deletes the runs started before the retention.
+func (s *Scheduler) purgeHistory(ctx context.Context) error {
+	n, err := s.client.ScheduledRun.Delete().
+		Where(scheduledrun.StartedAtLT(s.now().Add(-s.retention))).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	s.log.Infof("Deleted %d scheduled runs older than %s", n, s.retention)
+	return nil
+}
+
+// call runs fn, turning a panic into an error.
+func call(ctx context.Context, fn Func) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+		}
+	}()
+	return fn(ctx)
+}
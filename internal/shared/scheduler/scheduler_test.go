@@ -0,0 +1,237 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/enttest"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/lock"
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestClient(t *testing.T) *ent.Client {
+	t.Helper()
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// clockBackend is an in-memory lock backend whose leases expire by *clock.
+type clockBackend struct {
+	clock  *time.Time
+	locks  map[string]string
+	expiry map[string]time.Time
+	fence  int64
+}
+
+func newClockBackend(clock *time.Time) *clockBackend {
+	return &clockBackend{clock: clock, locks: make(map[string]string), expiry: make(map[string]time.Time)}
+}
+
+func (b *clockBackend) Acquire(_ context.Context, name, token string, ttl time.Duration) (int64, error) {
+	if _, ok := b.locks[name]; ok && b.clock.Before(b.expiry[name]) {
+		return 0, lock.ErrLocked
+	}
+	b.locks[name], b.expiry[name] = token, b.clock.Add(ttl)
+	b.fence++
+	return b.fence, nil
+}
+
+func (b *clockBackend) Renew(_ context.Context, name, token string, ttl time.Duration) error {
+	if b.locks[name] != token || !b.clock.Before(b.expiry[name]) {
+		return lock.ErrNotHeld
+	}
+	b.expiry[name] = b.clock.Add(ttl)
+	return nil
+}
+
+func (b *clockBackend) Release(_ context.Context, name, token string) error {
+	if b.locks[name] != token || !b.clock.Before(b.expiry[name]) {
+		return lock.ErrNotHeld
+	}
+	delete(b.locks, name)
+	return nil
+}
+
+// newTestScheduler creates a scheduler named instance whose clock follows *clock, taking its
+// locks from backend.
+func newTestScheduler(t *testing.T, client *ent.Client, backend lock.Backend, instance string, clock *time.Time, cfg config.SchedulerConfig) *Scheduler {
+	t.Helper()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	s, err := New(client, lock.New(backend), log, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.instance = instance
+	s.now = func() time.Time { return *clock }
+	return s
+}
+
+func TestScheduler_OnlyLeaderRunsTasks(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	clock := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+	var runs atomic.Int32
+	backend := newClockBackend(&clock)
+	schedulers := make([]*Scheduler, 2)
+	for i, instance := range []string{"replica-1", "replica-2"} {
+		schedulers[i] = newTestScheduler(t, client, backend, instance, &clock, config.SchedulerConfig{})
+		schedulers[i].Register("report.send", "*/10 * * * *", func(context.Context) error {
+			runs.Add(1)
+			return nil
+		})
+	}
+	tickAll := func() {
+		for _, s := range schedulers {
+			s.tick(ctx)
+			s.wg.Wait()
+		}
+	}
+
+	tickAll()
+	if !schedulers[0].leader || schedulers[1].leader {
+		t.Fatalf("leaders = %v, %v, want only the first replica", schedulers[0].leader, schedulers[1].leader)
+	}
+	clock = clock.Add(2*time.Minute + 30*time.Second)
+	tickAll()
+	tickAll()
+	if n := runs.Load(); n != 1 {
+		t.Fatalf("task ran %d times at 10:10, want once", n)
+	}
+	run := client.ScheduledRun.Query().OnlyX(ctx)
+	if run.Task != "report.send" || run.Trigger != TriggerSchedule || run.Instance != "replica-1" || run.Status != StatusSucceeded || run.FinishedAt == nil {
+		t.Errorf("run = %+v, want a finished scheduled run on replica-1", run)
+	}
+
+	// The leader dies: the other replica takes over once the lease expires
+	clock = clock.Add(defaultLeaseTTL)
+	schedulers[1].tick(ctx)
+	if !schedulers[1].leader {
+		t.Fatal("the second replica did not take over the expired leader lease")
+	}
+	clock = clock.Add(10 * time.Minute)
+	schedulers[1].tick(ctx)
+	schedulers[1].wg.Wait()
+	if n := runs.Load(); n != 2 {
+		t.Errorf("task ran %d times, want twice", n)
+	}
+}
+
+func TestScheduler_FollowerWaitsForLeaderLock(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	clock := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+	backend := lock.NewMemoryBackend()
+	runs := make(map[string]int)
+	schedulers := make([]*Scheduler, 2)
+	for i, instance := range []string{"replica-1", "replica-2"} {
+		schedulers[i] = newTestScheduler(t, client, backend, instance, &clock, config.SchedulerConfig{})
+		schedulers[i].Register("report.send", "*/10 * * * *", func(context.Context) error {
+			runs[instance]++
+			return nil
+		})
+	}
+	leader, follower := schedulers[0], schedulers[1]
+
+	leader.tick(ctx)
+	follower.tick(ctx)
+	clock = clock.Add(2*time.Minute + 30*time.Second)
+	follower.tick(ctx)
+	follower.wg.Wait()
+	if follower.leader || runs["replica-2"] != 0 {
+		t.Fatalf("follower leads = %v and ran the task %d times while the leader holds the lock", follower.leader, runs["replica-2"])
+	}
+	leader.tick(ctx)
+	leader.wg.Wait()
+	if runs["replica-1"] != 1 {
+		t.Fatalf("leader ran the task %d times, want once", runs["replica-1"])
+	}
+
+	// The leader stops and releases its lock, the follower takes over
+	if err := leader.lease.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	follower.tick(ctx)
+	if !follower.leader {
+		t.Error("the follower did not take the released leader lock")
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	clock := time.Now().UTC()
+	backend := newClockBackend(&clock)
+	s := newTestScheduler(t, client, backend, "replica-1", &clock, config.SchedulerConfig{})
+	s.Register("report.send", "@daily", func(context.Context) error { return errors.New("mail server down") })
+	s.Register("report.panic", "@daily", func(context.Context) error { panic("nil report") })
+
+	if err := s.Run(ctx, "report.unknown"); err == nil {
+		t.Error("Run() of an unknown task succeeded")
+	}
+	if err := s.Run(ctx, "report.send"); err == nil || err.Error() != "mail server down" {
+		t.Errorf("Run() error = %v, want the error of the task", err)
+	}
+	if err := s.Run(ctx, "report.panic"); err == nil || err.Error() != "panic: nil report" {
+		t.Errorf("Run() error = %v, want the panic of the task", err)
+	}
+	history, err := s.History(ctx, "report.send", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Trigger != TriggerManual || history[0].Status != StatusFailed || history[0].Error != "mail server down" {
+		t.Errorf("history = %+v, want one failed manual run", history)
+	}
+
+	// Another replica is running the task
+	if _, err := lock.New(backend).TryLock(ctx, taskLockPrefix+"report.send", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Run(ctx, "report.send"); !errors.Is(err, ErrTaskRunning) {
+		t.Errorf("Run() error = %v, want ErrTaskRunning", err)
+	}
+}
+
+func TestScheduler_PurgesHistory(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	clock := time.Now().UTC()
+	s := newTestScheduler(t, client, newClockBackend(&clock), "replica-1", &clock, config.SchedulerConfig{HistoryRetention: "24h"})
+	s.Register("report.send", "@daily", func(context.Context) error { return nil })
+	if err := s.Run(ctx, "report.send"); err != nil {
+		t.Fatal(err)
+	}
+
+	clock = clock.Add(25 * time.Hour)
+	if err := s.Run(ctx, "scheduler.purge_history"); err != nil {
+		t.Fatal(err)
+	}
+	history, err := s.History(ctx, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Task != "scheduler.purge_history" {
+		t.Errorf("history = %+v, want only the purge run", history)
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	for _, cfg := range []config.SchedulerConfig{
+		{Timezone: "Mars/Olympus"},
+		{LeaseTTL: "1s"},
+		{Timeout: "soon"},
+		{HistoryRetention: "-1h"},
+	} {
+		if _, err := New(nil, nil, logrus.New(), cfg); err == nil {
+			t.Errorf("New(%+v) succeeded, want an error", cfg)
+		}
+	}
+}
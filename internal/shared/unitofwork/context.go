@@ -0,0 +1,56 @@
+package unitofwork
+
+import (
+	"context"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+)
+
+// txState is the transaction carried by the context of a Do call. depth counts the nested
+// Do calls running in savepoints of the transaction, which share its afterCommit functions.
+type txState struct {
+	tx          *ent.Tx
+	client      *ent.Client
+	depth       int
+	afterCommit *[]func(context.Context)
+}
+
+type txKey struct{}
+
+func withTx(ctx context.Context, state *txState) context.Context {
+	return context.WithValue(ctx, txKey{}, state)
+}
+
+func txFromContext(ctx context.Context) *txState {
+	state, _ := ctx.Value(txKey{}).(*txState)
+	return state
+}
+
+// InTransaction reports whether ctx carries the transaction of a Do call.
+func InTransaction(ctx context.Context) bool {
+	return txFromContext(ctx) != nil
+}
+
+// ClientFromContext returns the client of the transaction carried by ctx, or fallback outside
+// transactions. Repositories use it so that calls made with the context of a Do call join
+// the transaction, whichever client the repository was created with.
+func ClientFromContext(ctx context.Context, fallback *ent.Client) *ent.Client {
+	if state := txFromContext(ctx); state != nil {
+		return state.client
+	}
+	return fallback
+}
+
+// AfterCommit runs fn once the transaction carried by ctx has committed, or right away
+// outside transactions. Functions registered in a savepoint that is rolled back are dropped
+// with it. They run in registration order with the context of the outermost Do call; use it
+// for side effects outside the database, e.g. enqueuing to redis, that must not happen if
+// the transaction rolls back.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	state := txFromContext(ctx)
+	if state == nil {
+		fn(ctx)
+		return
+	}
+	*state.afterCommit = append(*state.afterCommit, fn)
+}
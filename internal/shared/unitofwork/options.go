@@ -0,0 +1,55 @@
+package unitofwork
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// txOptions configure the transactions started by Do.
+type txOptions struct {
+	isolation  sql.IsolationLevel
+	readOnly   bool
+	maxRetries int
+}
+
+// TxOption configures a transaction. Options given to NewUnitOfWork are the defaults of every
+// transaction, and options given to Do override them. They apply to transactions only, not to
+// the savepoints of nested Do calls.
+type TxOption func(*txOptions)
+
+// WithIsolation sets the isolation level of the transaction. sql.LevelDefault uses the
+// default of the database.
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *txOptions) { o.isolation = level }
+}
+
+// ReadOnly starts a read-only transaction on databases supporting it.
+func ReadOnly() TxOption {
+	return func(o *txOptions) { o.readOnly = true }
+}
+
+// WithMaxRetries sets how many times a transaction failing on a serialization failure or a
+// deadlock is run again. Each attempt calls fn again, so fn should have no side effects
+// outside the database.
+func WithMaxRetries(n int) TxOption {
+	return func(o *txOptions) { o.maxRetries = n }
+}
+
+// ParseIsolation parses an isolation level as written in database.transaction.isolation. An
+// empty string is the default of the database.
+func ParseIsolation(s string) (sql.IsolationLevel, error) {
+	switch s {
+	case "", "default":
+		return sql.LevelDefault, nil
+	case "read_uncommitted":
+		return sql.LevelReadUncommitted, nil
+	case "read_committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable_read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	default:
+		return 0, fmt.Errorf("unknown isolation level %q (want read_uncommitted, read_committed, repeatable_read or serializable)", s)
+	}
+}
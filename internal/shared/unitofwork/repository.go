@@ -0,0 +1,49 @@
+package unitofwork
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+)
+
+// RepositoryFactory creates a repository backed by an ent client. Inside UnitOfWork.Do the
+// client is bound to the transaction.
+type RepositoryFactory[T any] func(client *ent.Client) T
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[reflect.Type]func(*ent.Client) any)
+)
+
+// RegisterRepository registers the factory of repositories of type T, usually an interface
+// declared by the module owning the repository. Modules call it from an init function of
+// their config package. It panics if a factory for T is already registered.
+func RegisterRepository[T any](factory RepositoryFactory[T]) {
+	key := typeOf[T]()
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, dup := factories[key]; dup {
+		panic("unitofwork: RegisterRepository called twice for " + key.String())
+	}
+	factories[key] = func(client *ent.Client) any { return factory(client) }
+}
+
+// Repo returns the repository of type T for the unit of work. If the unit of work is
+// transactional, the repository uses the transaction. It panics if no factory for T is
+// registered, which means the module providing it is not imported.
+func Repo[T any](uow UnitOfWork) T {
+	key := typeOf[T]()
+	factoriesMu.RLock()
+	factory, ok := factories[key]
+	factoriesMu.RUnlock()
+	if !ok {
+		panic(fmt.Sprintf("unitofwork: no repository registered for %s; is the module providing it imported?", key))
+	}
+	return factory(uow.Client()).(T)
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
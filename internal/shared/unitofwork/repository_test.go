@@ -0,0 +1,85 @@
+package unitofwork
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/sirupsen/logrus"
+)
+
+type testRepository interface {
+	Count(ctx context.Context) (int, error)
+	Create(ctx context.Context, username string) error
+}
+
+type testUserRepository struct {
+	client *ent.Client
+}
+
+func (r testUserRepository) Count(ctx context.Context) (int, error) {
+	return ClientFromContext(ctx, r.client).User.Query().Count(ctx)
+}
+
+func (r testUserRepository) Create(ctx context.Context, username string) error {
+	return ClientFromContext(ctx, r.client).User.Create().
+		SetUsername(username).
+		SetEmail(username + "@example.com").
+		SetPassword("secret").
+		Exec(ctx)
+}
+
+func init() {
+	RegisterRepository(func(client *ent.Client) testRepository { return testUserRepository{client: client} })
+}
+
+func TestRepo_UsesTransaction(t *testing.T) {
+	ctx := context.Background()
+	uow, _ := newTestUnitOfWork(t)
+
+	errAbort := errors.New("abort")
+	err := uow.Do(ctx, func(ctx context.Context, txUow UnitOfWork) error {
+		if err := Repo[testRepository](txUow).Create(ctx, "alice"); err != nil {
+			return err
+		}
+		if n, err := Repo[testRepository](txUow).Count(ctx); err != nil || n != 1 {
+			t.Errorf("Count() in the transaction = %d, %v, want 1", n, err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Do() error = %v, want %v", err, errAbort)
+	}
+	if n, err := Repo[testRepository](uow).Count(ctx); err != nil || n != 0 {
+		t.Errorf("Count() after rollback = %d, %v, want 0", n, err)
+	}
+
+	if err := uow.Do(ctx, func(ctx context.Context, txUow UnitOfWork) error {
+		return Repo[testRepository](txUow).Create(ctx, "bob")
+	}); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if n, err := Repo[testRepository](uow).Count(ctx); err != nil || n != 1 {
+		t.Errorf("Count() after commit = %d, %v, want 1", n, err)
+	}
+}
+
+func TestRepo_Unregistered(t *testing.T) {
+	type unregistered interface{}
+	defer func() {
+		if recover() == nil {
+			t.Error("Repo() of an unregistered type did not panic")
+		}
+	}()
+	Repo[unregistered](NewUnitOfWork(nil, logrus.New()))
+}
+
+func TestRegisterRepository_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterRepository() of a registered type did not panic")
+		}
+	}()
+	RegisterRepository(func(client *ent.Client) testRepository { return nil })
+}
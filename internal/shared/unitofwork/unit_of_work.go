@@ -2,44 +2,111 @@ package unitofwork
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"math/rand/v2"
+	"time"
 
 	"github.com/azahir21/go-backend-boilerplate/ent"
-	"github.com/azahir21/go-backend-boilerplate/internal/user/repository"
-	userRepoImpl "github.com/azahir21/go-backend-boilerplate/internal/user/repository/implementation"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/db"
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+var tracer = otel.Tracer("github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork")
+
+var transactionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "unitofwork_transaction_duration_seconds",
+	Help:    "Duration of UnitOfWork.Do transactions by outcome (commit, rollback, error).",
+	Buckets: prometheus.DefBuckets,
+}, []string{"outcome"})
+
+// retryBackoff is the wait before the first retry of a transaction; it doubles with each
+// further retry, with jitter so that conflicting transactions do not collide again.
+const retryBackoff = 20 * time.Millisecond
+
 // UnitOfWork defines the interface for a unit of work pattern.
 // It manages database transactions and provides access to repositories
-// within the transaction boundary.
+// within the transaction boundary through Repo.
 type UnitOfWork interface {
-	// Do executes a function within a database transaction.
+	// Do executes a function within a database transaction carried by the context passed
+	// to fn, which repositories join through ClientFromContext.
 	// If the function returns an error, the transaction is rolled back.
 	// If the function succeeds, the transaction is committed.
-	Do(ctx context.Context, fn func(txUow UnitOfWork) error) error
+	// Called with the context of another Do call, it runs fn in a SAVEPOINT of that
+	// transaction instead, so that an error rolls back only the work of fn.
+	Do(ctx context.Context, fn func(ctx context.Context, txUow UnitOfWork) error, opts ...TxOption) error
 
-	// UserRepository returns the user repository for this unit of work.
-	// If called within a transaction, it returns a transactional repository.
-	UserRepository() repository.UserRepository
-	// Add other repository getters here as needed
+	// Client returns the ent client of this unit of work.
+	// If called within a transaction, the client is bound to the transaction.
+	Client() *ent.Client
 }
 
 type unitOfWork struct {
-	client *ent.Client
-	tx     *ent.Tx
+	client   *ent.Client
+	tx       *txState
+	log      *logrus.Logger
+	defaults []TxOption
 }
 
-// NewUnitOfWork creates a new UnitOfWork instance.
-func NewUnitOfWork(client *ent.Client) UnitOfWork {
-	return &unitOfWork{client: client}
+// NewUnitOfWork creates a new UnitOfWork instance. opts are the defaults of its transactions.
+func NewUnitOfWork(client *ent.Client, log *logrus.Logger, opts ...TxOption) UnitOfWork {
+	return &unitOfWork{client: client, log: log, defaults: opts}
 }
 
 // Do executes a function within a database transaction.
-// It handles automatic rollback on errors and panic recovery.
-func (u *unitOfWork) Do(ctx context.Context, fn func(txUow UnitOfWork) error) error {
-	tx, err := u.client.Tx(ctx)
+// It handles automatic rollback on errors and panic recovery, and runs the transaction again
+// when it fails on a serialization failure or a deadlock, up to the configured retries.
+// Nested calls run in savepoints; see UnitOfWork.Do.
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context, txUow UnitOfWork) error, opts ...TxOption) error {
+	if parent := txFromContext(ctx); parent != nil {
+		return u.savepoint(ctx, parent, fn)
+	}
+	if u.tx != nil {
+		return u.savepoint(withTx(ctx, u.tx), u.tx, fn)
+	}
+
+	var o txOptions
+	for _, opt := range append(u.defaults[:len(u.defaults):len(u.defaults)], opts...) {
+		opt(&o)
+	}
+	for attempt := 1; ; attempt++ {
+		err := u.transaction(ctx, fn, o)
+		if err == nil || attempt > o.maxRetries || !db.IsRetryable(err) {
+			return err
+		}
+		delay := retryBackoff << (attempt - 1)
+		delay += rand.N(delay)
+		u.log.WithContext(ctx).WithError(err).Warnf("Transaction conflicted, retrying in %s (retry %d of %d)", delay, attempt, o.maxRetries)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// transaction runs fn in a new transaction.
+// The transaction is traced as a "unitofwork.Do" span, which is the parent of the statement
+// spans of repository calls made with the context passed to fn.
+func (u *unitOfWork) transaction(ctx context.Context, fn func(ctx context.Context, txUow UnitOfWork) error, o txOptions) error {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "unitofwork.Do")
+	observe := func(outcome string, err error) {
+		transactionDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+		span.SetAttributes(attribute.String("unitofwork.outcome", outcome))
+		tracing.End(span, err)
+	}
+
+	tx, err := u.client.BeginTx(ctx, &sql.TxOptions{Isolation: o.isolation, ReadOnly: o.readOnly})
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		err = fmt.Errorf("failed to begin transaction: %w", err)
+		observe("error", err)
+		return err
 	}
 
 	// Ensure transaction is rolled back on panic
@@ -47,33 +114,82 @@ func (u *unitOfWork) Do(ctx context.Context, fn func(txUow UnitOfWork) error) er
 		if v := recover(); v != nil {
 			if rollbackErr := tx.Rollback(); rollbackErr != nil {
 				// Log rollback error but re-panic with original
-				fmt.Printf("failed to rollback transaction after panic: %v\n", rollbackErr)
+				u.log.WithContext(ctx).WithError(rollbackErr).Error("Failed to roll back transaction after panic")
 			}
+			observe("rollback", fmt.Errorf("panic: %v", v))
 			panic(v)
 		}
 	}()
 
 	// Create transactional unit of work
-	txUow := &unitOfWork{client: tx.Client(), tx: tx}
+	state := &txState{tx: tx, client: tx.Client(), afterCommit: new([]func(context.Context))}
+	txUow := &unitOfWork{client: state.client, tx: state, log: u.log, defaults: u.defaults}
 
 	// Execute the function
-	if err := fn(txUow); err != nil {
+	if err := fn(withTx(ctx, state), txUow); err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			return fmt.Errorf("transaction failed (rollback also failed: %v): %w", rollbackErr, err)
+			err = fmt.Errorf("transaction failed (rollback also failed: %v): %w", rollbackErr, err)
+			observe("error", err)
+			return err
 		}
-		return fmt.Errorf("transaction failed: %w", err)
+		err = fmt.Errorf("transaction failed: %w", err)
+		observe("rollback", err)
+		return err
 	}
 
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		err = fmt.Errorf("failed to commit transaction: %w", err)
+		observe("error", err)
+		return err
 	}
 
+	observe("commit", nil)
+	for _, fn := range *state.afterCommit {
+		fn(ctx)
+	}
+	return nil
+}
+
+// savepoint runs fn in a savepoint of the parent transaction. An error or panic in fn rolls
+// back to the savepoint; the parent transaction goes on and decides whether to commit.
+func (u *unitOfWork) savepoint(ctx context.Context, parent *txState, fn func(ctx context.Context, txUow UnitOfWork) error) error {
+	state := &txState{tx: parent.tx, client: parent.client, depth: parent.depth + 1, afterCommit: parent.afterCommit}
+	name := fmt.Sprintf("uow_savepoint_%d", state.depth)
+	registered := len(*state.afterCommit)
+	if _, err := state.client.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	rollback := func() error {
+		*state.afterCommit = (*state.afterCommit)[:registered]
+		_, err := state.client.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+		return err
+	}
+
+	defer func() {
+		if v := recover(); v != nil {
+			if rollbackErr := rollback(); rollbackErr != nil {
+				u.log.WithContext(ctx).WithError(rollbackErr).Error("Failed to roll back to savepoint after panic")
+			}
+			panic(v)
+		}
+	}()
+
+	txUow := &unitOfWork{client: state.client, tx: state, log: u.log, defaults: u.defaults}
+	if err := fn(withTx(ctx, state), txUow); err != nil {
+		if rollbackErr := rollback(); rollbackErr != nil {
+			return fmt.Errorf("savepoint failed (rollback also failed: %v): %w", rollbackErr, err)
+		}
+		return fmt.Errorf("savepoint rolled back: %w", err)
+	}
+	if _, err := state.client.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
 	return nil
 }
 
-// UserRepository returns a repository instance.
-// If this unit of work is transactional, the repository will use the transaction.
-func (u *unitOfWork) UserRepository() repository.UserRepository {
-	return userRepoImpl.NewUserRepository(u.client)
+// Client returns the ent client of this unit of work.
+// If this unit of work is transactional, the client will use the transaction.
+func (u *unitOfWork) Client() *ent.Client {
+	return u.client
 }
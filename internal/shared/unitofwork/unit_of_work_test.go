@@ -0,0 +1,199 @@
+package unitofwork
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/enttest"
+	"github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestUnitOfWork(t *testing.T, opts ...TxOption) (UnitOfWork, *ent.Client) {
+	t.Helper()
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewUnitOfWork(client, log, opts...), client
+}
+
+func countUsers(t *testing.T, client *ent.Client) int {
+	t.Helper()
+	n, err := client.User.Query().Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestDo_ContextJoinsTransaction(t *testing.T) {
+	uow, client := newTestUnitOfWork(t)
+	repo := testUserRepository{client: client}
+
+	err := uow.Do(context.Background(), func(ctx context.Context, _ UnitOfWork) error {
+		if !InTransaction(ctx) {
+			t.Error("InTransaction() = false inside Do")
+		}
+		if err := repo.Create(ctx, "alice"); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	if err == nil {
+		t.Fatal("Do() succeeded, want the error of fn")
+	}
+	if n := countUsers(t, client); n != 0 {
+		t.Errorf("a repository created outside Do wrote %d users despite the rollback", n)
+	}
+}
+
+func TestDo_NestedSavepoints(t *testing.T) {
+	uow, client := newTestUnitOfWork(t)
+	repo := testUserRepository{client: client}
+
+	errInner := errors.New("inner failed")
+	err := uow.Do(context.Background(), func(ctx context.Context, txUow UnitOfWork) error {
+		if err := repo.Create(ctx, "alice"); err != nil {
+			return err
+		}
+		err := uow.Do(ctx, func(ctx context.Context, _ UnitOfWork) error {
+			if err := repo.Create(ctx, "bob"); err != nil {
+				return err
+			}
+			return errInner
+		})
+		if !errors.Is(err, errInner) {
+			t.Errorf("nested Do() error = %v, want %v", err, errInner)
+		}
+		// Do on the transactional unit of work nests as well
+		return txUow.Do(context.Background(), func(ctx context.Context, _ UnitOfWork) error {
+			return repo.Create(ctx, "carol")
+		})
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	var names []string
+	for _, u := range client.User.Query().Order(ent.Asc("id")).AllX(context.Background()) {
+		names = append(names, u.Username)
+	}
+	if len(names) != 2 || names[0] != "alice" || names[1] != "carol" {
+		t.Errorf("committed users = %v, want [alice carol] without the rolled back savepoint", names)
+	}
+}
+
+func TestAfterCommit(t *testing.T) {
+	uow, _ := newTestUnitOfWork(t)
+	var ran []string
+	record := func(name string) func(context.Context) {
+		return func(context.Context) { ran = append(ran, name) }
+	}
+
+	AfterCommit(context.Background(), record("outside"))
+	_ = uow.Do(context.Background(), func(ctx context.Context, _ UnitOfWork) error {
+		AfterCommit(ctx, record("rolled back"))
+		return errors.New("abort")
+	})
+	err := uow.Do(context.Background(), func(ctx context.Context, _ UnitOfWork) error {
+		AfterCommit(ctx, record("outer"))
+		_ = uow.Do(ctx, func(ctx context.Context, _ UnitOfWork) error {
+			AfterCommit(ctx, record("savepoint rolled back"))
+			return errors.New("abort")
+		})
+		if err := uow.Do(ctx, func(ctx context.Context, _ UnitOfWork) error {
+			AfterCommit(ctx, record("savepoint"))
+			return nil
+		}); err != nil {
+			return err
+		}
+		if len(ran) != 1 {
+			t.Errorf("ran %v before the commit", ran)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if got := fmt.Sprint(ran); got != "[outside outer savepoint]" {
+		t.Errorf("ran %s, want [outside outer savepoint]", got)
+	}
+}
+
+func TestDo_RetriesConflicts(t *testing.T) {
+	busy := sqlite3.Error{Code: sqlite3.ErrBusy}
+	tests := []struct {
+		name         string
+		maxRetries   int
+		failures     int
+		err          error
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "succeeds after retries", maxRetries: 3, failures: 2, err: busy, wantAttempts: 3},
+		{name: "gives up", maxRetries: 1, failures: 5, err: busy, wantAttempts: 2, wantErr: true},
+		{name: "other errors are not retried", maxRetries: 3, failures: 5, err: errors.New("invalid"), wantAttempts: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, _ := newTestUnitOfWork(t, WithMaxRetries(tt.maxRetries))
+			attempts := 0
+			err := uow.Do(context.Background(), func(ctx context.Context, _ UnitOfWork) error {
+				attempts++
+				if attempts <= tt.failures {
+					return tt.err
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Do() error = %v, want error %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("fn ran %d times, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestDo_PanicRollsBack(t *testing.T) {
+	uow, client := newTestUnitOfWork(t)
+	repo := testUserRepository{client: client}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Do() did not re-panic")
+			}
+		}()
+		_ = uow.Do(context.Background(), func(ctx context.Context, _ UnitOfWork) error {
+			if err := repo.Create(ctx, "alice"); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+	if n := countUsers(t, client); n != 0 {
+		t.Errorf("%d users committed despite the panic", n)
+	}
+}
+
+func TestParseIsolation(t *testing.T) {
+	for s, want := range map[string]sql.IsolationLevel{
+		"":                sql.LevelDefault,
+		"read_committed":  sql.LevelReadCommitted,
+		"repeatable_read": sql.LevelRepeatableRead,
+		"serializable":    sql.LevelSerializable,
+	} {
+		if got, err := ParseIsolation(s); err != nil || got != want {
+			t.Errorf("ParseIsolation(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseIsolation("snapshot"); err == nil {
+		t.Error("ParseIsolation(snapshot) succeeded, want an error")
+	}
+}
@@ -3,9 +3,7 @@ package config
 import (
 	sharedGraphQL "github.com/azahir21/go-backend-boilerplate/internal/shared/graphql"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
-	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
 	graphqlDelivery "github.com/azahir21/go-backend-boilerplate/internal/user/delivery/graphql"
-	userRepoImpl "github.com/azahir21/go-backend-boilerplate/internal/user/repository/implementation"
 	userUsecase "github.com/azahir21/go-backend-boilerplate/internal/user/usecase"
 )
 
@@ -17,14 +15,11 @@ type GraphQLConfig struct {
 
 // NewGraphQLConfig creates a new GraphQLConfig with all dependencies injected.
 func NewGraphQLConfig(deps *module.Dependencies) *GraphQLConfig {
-	// Initialize repository
-	userRepo := userRepoImpl.NewUserRepository(deps.DBClient)
-
-	// Initialize unit of work
-	uow := unitofwork.NewUnitOfWork(deps.DBClient)
+	// Use the application's unit of work, configured from database.transaction
+	uow := deps.UoW
 
 	// Initialize usecase
-	usecase := userUsecase.NewUserUsecase(userRepo, uow)
+	usecase := userUsecase.NewUserUsecase(uow, deps.Publisher, welcomeEmailJobs(deps))
 
 	// Initialize schema builder
 	schemaBuilder := graphqlDelivery.NewUserSchemaBuilder(deps.Log, usecase)
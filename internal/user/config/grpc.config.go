@@ -2,10 +2,8 @@ package config
 
 import (
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
-	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
 	grpcDelivery "github.com/azahir21/go-backend-boilerplate/internal/user/delivery/grpc"
 	proto "github.com/azahir21/go-backend-boilerplate/internal/user/delivery/grpc/gen"
-	userRepoImpl "github.com/azahir21/go-backend-boilerplate/internal/user/repository/implementation"
 	userUsecase "github.com/azahir21/go-backend-boilerplate/internal/user/usecase"
 	"google.golang.org/grpc"
 )
@@ -18,14 +16,11 @@ type GRPCConfig struct {
 
 // NewGRPCConfig creates a new GRPCConfig with all dependencies injected.
 func NewGRPCConfig(deps *module.Dependencies) *GRPCConfig {
-	// Initialize repository
-	userRepo := userRepoImpl.NewUserRepository(deps.DBClient)
-
-	// Initialize unit of work
-	uow := unitofwork.NewUnitOfWork(deps.DBClient)
+	// Use the application's unit of work, configured from database.transaction
+	uow := deps.UoW
 
 	// Initialize usecase
-	usecase := userUsecase.NewUserUsecase(userRepo, uow)
+	usecase := userUsecase.NewUserUsecase(uow, deps.Publisher, welcomeEmailJobs(deps))
 
 	// Initialize handler
 	handler := grpcDelivery.NewUserHandler(deps.Log, usecase)
@@ -3,9 +3,7 @@ package config
 import (
 	sharedHttp "github.com/azahir21/go-backend-boilerplate/internal/shared/http"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
-	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
 	restDelivery "github.com/azahir21/go-backend-boilerplate/internal/user/delivery/http"
-	userRepoImpl "github.com/azahir21/go-backend-boilerplate/internal/user/repository/implementation"
 	userUsecase "github.com/azahir21/go-backend-boilerplate/internal/user/usecase"
 )
 
@@ -17,14 +15,11 @@ type HTTPConfig struct {
 
 // NewHTTPConfig creates a new HTTPConfig with all dependencies injected.
 func NewHTTPConfig(deps *module.Dependencies) *HTTPConfig {
-	// Initialize repository
-	userRepo := userRepoImpl.NewUserRepository(deps.DBClient)
-
-	// Initialize unit of work
-	uow := unitofwork.NewUnitOfWork(deps.DBClient)
+	// Use the application's unit of work, configured from database.transaction
+	uow := deps.UoW
 
 	// Initialize usecase
-	usecase := userUsecase.NewUserUsecase(userRepo, uow)
+	usecase := userUsecase.NewUserUsecase(uow, deps.Publisher, welcomeEmailJobs(deps))
 
 	// Initialize handler
 	handler := restDelivery.NewUserHandler(deps.Log, usecase)
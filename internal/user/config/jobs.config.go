@@ -0,0 +1,30 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/jobs"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
+	userUsecase "github.com/azahir21/go-backend-boilerplate/internal/user/usecase"
+)
+
+// registerJobs registers the job handlers of the user module. Welcome emails need both the
+// job queue and the email client.
+func registerJobs(deps *module.Dependencies) {
+	if welcomeEmailJobs(deps) == nil {
+		return
+	}
+	jobs.Handle(deps.Jobs, userUsecase.JobSendWelcomeEmail, func(ctx context.Context, email userUsecase.WelcomeEmail) error {
+		return deps.EmailClient.SendEmail(ctx, email.Email, "Welcome!", fmt.Sprintf("Hi %s, your account is ready.", email.Username))
+	})
+}
+
+// welcomeEmailJobs returns the client the usecase enqueues welcome emails with, or nil when
+// jobs or email are disabled.
+func welcomeEmailJobs(deps *module.Dependencies) *jobs.Client {
+	if deps.Jobs == nil || deps.EmailClient == nil {
+		return nil
+	}
+	return deps.Jobs
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
+	userRepoImpl "github.com/azahir21/go-backend-boilerplate/internal/user/repository/implementation"
+)
+
+func init() {
+	unitofwork.RegisterRepository(userRepoImpl.NewUserRepository)
+	module.Register(module.Definition{
+		Name:     "user",
+		Requires: []module.Infrastructure{module.SQL},
+		HTTP:     func(deps *module.Dependencies) module.HTTPModule { return NewHTTPConfig(deps) },
+		GRPC:     func(deps *module.Dependencies) module.GRPCModule { return NewGRPCConfig(deps) },
+		GraphQL:  func(deps *module.Dependencies) module.GraphQLModule { return NewGraphQLConfig(deps) },
+		Jobs:     registerJobs,
+		Schedule: registerTasks,
+	})
+}
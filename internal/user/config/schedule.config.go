@@ -0,0 +1,23 @@
+package config
+
+import (
+	"context"
+
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/module"
+	userUsecase "github.com/azahir21/go-backend-boilerplate/internal/user/usecase"
+)
+
+// registerTasks registers the recurring tasks of the user module.
+func registerTasks(deps *module.Dependencies) {
+	usecase := userUsecase.NewUserUsecase(deps.UoW, deps.Publisher, nil)
+
+	// Purge soft-deleted users every night
+	deps.Scheduler.Register("user.purge_deleted", "0 3 * * *", func(ctx context.Context) error {
+		n, err := usecase.PurgeDeletedUsers(ctx)
+		if err != nil {
+			return err
+		}
+		deps.Log.Infof("Purged %d users deleted more than %s ago", n, userUsecase.DeletedUserRetention)
+		return nil
+	})
+}
@@ -36,7 +36,7 @@ func (r *UserResolver) GetUserResolver(p graphql.ResolveParams) (interface{}, er
 
 	user, err := r.userUsecase.GetProfile(ctx, uint(id))
 	if err != nil {
-		r.log.Errorf("failed to get user profile for ID %d: %v", id, err)
+		r.log.WithContext(ctx).Errorf("failed to get user profile for ID %d: %v", id, err)
 		return nil, err
 	}
 
@@ -66,7 +66,7 @@ func (r *UserResolver) RegisterUserResolver(p graphql.ResolveParams) (interface{
 
 	authResponse, err := r.userUsecase.Register(ctx, req)
 	if err != nil {
-		r.log.Errorf("failed to register user %s: %v", username, err)
+		r.log.WithContext(ctx).Errorf("failed to register user %s: %v", username, err)
 		return nil, err
 	}
 
@@ -94,7 +94,7 @@ func (r *UserResolver) LoginUserResolver(p graphql.ResolveParams) (interface{},
 
 	authResponse, err := r.userUsecase.Login(ctx, req)
 	if err != nil {
-		r.log.Errorf("failed to login user %s: %v", username, err)
+		r.log.WithContext(ctx).Errorf("failed to login user %s: %v", username, err)
 		return nil, err
 	}
 
@@ -31,7 +31,7 @@ func (h *UserHandler) Register(ctx context.Context, req *proto.RegisterRequest)
 
 	authResponse, err := h.userUsecase.Register(ctx, registerReq)
 	if err != nil {
-		h.log.Errorf("gRPC Register failed for user %s: %v", req.Username, err)
+		h.log.WithContext(ctx).Errorf("gRPC Register failed for user %s: %v", req.Username, err)
 		return nil, err
 	}
 
@@ -54,7 +54,7 @@ func (h *UserHandler) Login(ctx context.Context, req *proto.LoginRequest) (*prot
 
 	authResponse, err := h.userUsecase.Login(ctx, loginReq)
 	if err != nil {
-		h.log.Errorf("gRPC Login failed for user %s: %v", req.Username, err)
+		h.log.WithContext(ctx).Errorf("gRPC Login failed for user %s: %v", req.Username, err)
 		return nil, err
 	}
 
@@ -72,7 +72,7 @@ func (h *UserHandler) Login(ctx context.Context, req *proto.LoginRequest) (*prot
 func (h *UserHandler) GetProfile(ctx context.Context, req *proto.GetProfileRequest) (*proto.User, error) {
 	user, err := h.userUsecase.GetProfile(ctx, uint(req.UserId))
 	if err != nil {
-		h.log.Errorf("gRPC GetProfile failed for user ID %d: %v", req.UserId, err)
+		h.log.WithContext(ctx).Errorf("gRPC GetProfile failed for user ID %d: %v", req.UserId, err)
 		return nil, err
 	}
 
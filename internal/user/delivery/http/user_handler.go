@@ -3,8 +3,11 @@ package http
 import (
 	"net/http"
 
+	"github.com/azahir21/go-backend-boilerplate/infrastructure/cache"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/entity"
 	api "github.com/azahir21/go-backend-boilerplate/internal/shared/http"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/middleware"
+	proto "github.com/azahir21/go-backend-boilerplate/internal/user/delivery/grpc/gen"
 	"github.com/azahir21/go-backend-boilerplate/internal/user/delivery/http/dto"
 	"github.com/azahir21/go-backend-boilerplate/internal/user/usecase"
 	"github.com/azahir21/go-backend-boilerplate/pkg/apperr"
@@ -26,18 +29,31 @@ func NewUserHandler(log *logrus.Logger, userUsecase usecase.UserUsecase) *UserHa
 }
 
 // RegisterRoutes implements the HttpRouter interface.
-func (h *UserHandler) RegisterRoutes(engine *gin.Engine) {
-	v1 := engine.Group("/api/v1")
-	grp := api.NewAPIRouterGroup(v1)
-	grp.Register(
+func (h *UserHandler) RegisterRoutes(routes *api.Routes) {
+	// Protobuf clients reuse the gRPC messages for request and response bodies
+	httpresp.RegisterProto[dto.RegisterRequest](&proto.RegisterRequest{})
+	httpresp.RegisterProto[dto.LoginRequest](&proto.LoginRequest{})
+	httpresp.RegisterProto[dto.AuthResponse](&proto.AuthResponse{})
+	httpresp.RegisterProto[entity.User](&proto.User{})
+
+	routes.Version("v1").Register(
 		api.EndpointSpec{Method: http.MethodGet, Path: "/ping", Handler: h.Ping},
-		api.EndpointSpec{Method: http.MethodPost, Path: "/auth/register", Handler: h.Register},
+		api.EndpointSpec{Method: http.MethodPost, Path: "/auth/register", Handler: h.Register, Idempotent: true},
 		api.EndpointSpec{Method: http.MethodPost, Path: "/auth/login", Handler: h.Login},
-		api.EndpointSpec{Method: http.MethodGet, Path: "/auth/profile", Handler: h.GetProfile, Middlewares: []gin.HandlerFunc{middleware.AuthMiddleware()}},
+		api.EndpointSpec{Method: http.MethodGet, Path: "/auth/profile", Handler: h.GetProfile, Middlewares: []gin.HandlerFunc{middleware.AuthMiddleware()}, Cache: profileCache},
 		api.EndpointSpec{Method: http.MethodGet, Path: "/admin/test", Handler: h.AdminOnly, Middlewares: []gin.HandlerFunc{middleware.AuthMiddleware(), middleware.AdminMiddleware()}},
+		api.EndpointSpec{Method: http.MethodGet, Path: "/admin/users", Handler: h.ListUsers, Middlewares: []gin.HandlerFunc{middleware.AuthMiddleware(), middleware.AdminMiddleware()}},
 	)
 }
 
+// profileCache caches profiles per user until the user record is written.
+var profileCache = &api.CachePolicy{
+	VaryByUser: true,
+	Tags: func(c *gin.Context) []string {
+		return []string{cache.EntityTag("User", c.Value("user_id"))}
+	},
+}
+
 // Ping godoc
 // @Summary Ping endpoint
 // @Description Health check endpoint
@@ -47,18 +63,21 @@ func (h *UserHandler) RegisterRoutes(engine *gin.Engine) {
 // @Success 200 {object} map[string]string
 // @Router /ping [get]
 func (h *UserHandler) Ping(c *gin.Context) {
-	httpresp.JSON(c, http.StatusOK, "pong", gin.H{"message": "pong"})
+	httpresp.Respond(c, http.StatusOK, "pong", gin.H{"message": "pong"})
 }
 
 // Register godoc
 // @Summary Register a new user
 // @Description Register a new user with username, email, and password
 // @Tags Authentication
-// @Accept json
-// @Produce json
+// @Accept json,application/msgpack,application/x-protobuf
+// @Produce json,application/msgpack,application/x-protobuf
 // @Param request body dto.RegisterRequest true "Registration request"
+// @Param Idempotency-Key header string false "Client-generated key; retries with the same key replay the original response"
 // @Success 201 {object} httpresp.Response{data=dto.AuthResponse}
 // @Failure 400 {object} httpresp.Response
+// @Failure 409 {object} httpresp.Response
+// @Failure 422 {object} httpresp.Response
 // @Failure 500 {object} httpresp.Response
 // @Router /auth/register [post]
 func (h *UserHandler) Register(c *gin.Context, req *dto.RegisterRequest) error {
@@ -68,7 +87,7 @@ func (h *UserHandler) Register(c *gin.Context, req *dto.RegisterRequest) error {
 		return nil
 	}
 
-	httpresp.JSON(c, http.StatusCreated, "User registered successfully", result)
+	httpresp.Respond(c, http.StatusCreated, "User registered successfully", result)
 	return nil
 }
 
@@ -76,8 +95,8 @@ func (h *UserHandler) Register(c *gin.Context, req *dto.RegisterRequest) error {
 // @Summary User login
 // @Description Authenticate user and return JWT token
 // @Tags Authentication
-// @Accept json
-// @Produce json
+// @Accept json,application/msgpack,application/x-protobuf
+// @Produce json,application/msgpack,application/x-protobuf
 // @Param request body dto.LoginRequest true "Login request"
 // @Success 200 {object} httpresp.Response{data=dto.AuthResponse}
 // @Failure 400 {object} httpresp.Response
@@ -90,7 +109,7 @@ func (h *UserHandler) Login(c *gin.Context, req *dto.LoginRequest) error {
 		return nil
 	}
 
-	httpresp.JSON(c, http.StatusOK, "Login successful", result)
+	httpresp.Respond(c, http.StatusOK, "Login successful", result)
 	return nil
 }
 
@@ -98,10 +117,12 @@ func (h *UserHandler) Login(c *gin.Context, req *dto.LoginRequest) error {
 // @Summary Get user profile
 // @Description Get current user's profile information
 // @Tags Authentication
-// @Accept json
-// @Produce json
+// @Accept json,application/msgpack,application/x-protobuf
+// @Produce json,application/msgpack,application/x-protobuf
 // @Security BearerAuth
+// @Param If-None-Match header string false "ETag of a cached representation"
 // @Success 200 {object} httpresp.Response{data=entity.User}
+// @Success 304 "Not Modified"
 // @Failure 401 {object} httpresp.Response
 // @Failure 404 {object} httpresp.Response
 // @Router /auth/profile [get]
@@ -118,7 +139,8 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
-	httpresp.JSON(c, http.StatusOK, "Profile retrieved successfully", user)
+	api.SetLastModified(c, user.UpdatedAt)
+	httpresp.Respond(c, http.StatusOK, "Profile retrieved successfully", user)
 }
 
 // AdminOnly godoc
@@ -133,8 +155,40 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 // @Failure 403 {object} httpresp.Response
 // @Router /admin/test [get]
 func (h *UserHandler) AdminOnly(c *gin.Context) {
-	httpresp.JSON(c, http.StatusOK, "Admin access granted", gin.H{
+	httpresp.Respond(c, http.StatusOK, "Admin access granted", gin.H{
 		"message": "This is an admin-only endpoint",
 		"user":    c.GetString("username"),
 	})
 }
+
+// ListUsers godoc
+// @Summary List users
+// @Description Paginated list of users for admins. Use page for numbered pages or the returned next_cursor for keyset pagination.
+// @Tags Admin
+// @Accept json
+// @Produce json,application/msgpack
+// @Security BearerAuth
+// @Param page query int false "1-based page number; cannot be combined with cursor"
+// @Param cursor query string false "Opaque cursor from page_info.next_cursor"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param sort query string false "Comma-separated fields, prefixed with - for descending (id, username, created_at, updated_at)" default(-created_at)
+// @Param filter[role] query string false "Filter by role; operators via filter[field][op], e.g. filter[created_at][gte]"
+// @Success 200 {object} httpresp.Response{data=listquery.Page[dto.UserResponse]}
+// @Failure 400 {object} httpresp.Response
+// @Failure 401 {object} httpresp.Response
+// @Failure 403 {object} httpresp.Response
+// @Router /admin/users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	q, ok := api.ParseListQuery(c, usecase.UserListSchema)
+	if !ok {
+		return
+	}
+
+	page, err := h.UserUsecase.ListUsers(c.Request.Context(), q)
+	if err != nil {
+		apperr.Respond(c, apperr.InternalServer("Failed to list users").WithCause(err))
+		return
+	}
+
+	httpresp.Respond(c, http.StatusOK, "Users retrieved successfully", page)
+}
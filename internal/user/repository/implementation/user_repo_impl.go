@@ -3,11 +3,15 @@ package implementation
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/predicate"
 	"github.com/azahir21/go-backend-boilerplate/ent/user"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/entity"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
 	"github.com/azahir21/go-backend-boilerplate/internal/user/repository"
+	"github.com/azahir21/go-backend-boilerplate/pkg/listquery"
 )
 
 type userRepository struct {
@@ -18,8 +22,13 @@ func NewUserRepository(client *ent.Client) repository.UserRepository {
 	return &userRepository{client: client}
 }
 
+// clientFor returns the client of the transaction carried by ctx, if any.
+func (r *userRepository) clientFor(ctx context.Context) *ent.Client {
+	return unitofwork.ClientFromContext(ctx, r.client)
+}
+
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	entUser, err := r.client.User.
+	entUser, err := r.clientFor(ctx).User.
 		Create().
 		SetUsername(user.Username).
 		SetEmail(user.Email).
@@ -34,7 +43,7 @@ func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 }
 
 func (r *userRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
-	entUser, err := r.client.User.Query().Where(user.UsernameEQ(username)).Only(ctx)
+	entUser, err := r.clientFor(ctx).User.Query().Where(user.UsernameEQ(username), user.DeletedAtIsNil()).Only(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user by username %s: %w", username, err)
 	}
@@ -42,7 +51,7 @@ func (r *userRepository) FindByUsername(ctx context.Context, username string) (*
 }
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
-	entUser, err := r.client.User.Query().Where(user.EmailEQ(email)).Only(ctx)
+	entUser, err := r.clientFor(ctx).User.Query().Where(user.EmailEQ(email), user.DeletedAtIsNil()).Only(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user by email %s: %w", email, err)
 	}
@@ -50,34 +59,89 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id uint) (*entity.User, error) {
-	entUser, err := r.client.User.Get(ctx, int(id))
+	entUser, err := r.clientFor(ctx).User.Query().Where(user.ID(int(id)), user.DeletedAtIsNil()).Only(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user by ID %d: %w", id, err)
 	}
 	return toDomainUser(entUser), nil
 }
 
-func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
-	_, err := r.client.User.
-		UpdateOneID(int(user.ID)).
-		SetUsername(user.Username).
-		SetEmail(user.Email).
-		SetPassword(user.Password).
-		SetRole(user.Role).
+func (r *userRepository) UsernameTaken(ctx context.Context, username string) (bool, error) {
+	taken, err := r.clientFor(ctx).User.Query().Where(user.UsernameEQ(username)).Exist(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check username %s: %w", username, err)
+	}
+	return taken, nil
+}
+
+func (r *userRepository) EmailTaken(ctx context.Context, email string) (bool, error) {
+	taken, err := r.clientFor(ctx).User.Query().Where(user.EmailEQ(email)).Exist(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check email %s: %w", email, err)
+	}
+	return taken, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, u *entity.User) error {
+	_, err := r.clientFor(ctx).User.
+		UpdateOneID(int(u.ID)).
+		Where(user.DeletedAtIsNil()).
+		SetUsername(u.Username).
+		SetEmail(u.Email).
+		SetPassword(u.Password).
+		SetRole(u.Role).
 		Save(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to update user %d: %w", user.ID, err)
+		return fmt.Errorf("failed to update user %d: %w", u.ID, err)
 	}
 	return nil
 }
 
 func (r *userRepository) Delete(ctx context.Context, id uint) error {
-	if err := r.client.User.DeleteOneID(int(id)).Exec(ctx); err != nil {
+	err := r.clientFor(ctx).User.
+		UpdateOneID(int(id)).
+		Where(user.DeletedAtIsNil()).
+		SetDeletedAt(time.Now()).
+		Exec(ctx)
+	if err != nil {
 		return fmt.Errorf("failed to delete user %d: %w", id, err)
 	}
 	return nil
 }
 
+func (r *userRepository) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	n, err := r.clientFor(ctx).User.Delete().Where(user.DeletedAtLT(before)).Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+	return n, nil
+}
+
+func (r *userRepository) List(ctx context.Context, q *listquery.Query) ([]*entity.User, error) {
+	entUsers, err := r.clientFor(ctx).User.Query().
+		Where(predicate.User(listquery.EntWhere(q)), user.DeletedAtIsNil()).
+		Order(user.OrderOption(listquery.EntOrder(q))).
+		Offset(q.Offset()).
+		Limit(q.FetchLimit()).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	users := make([]*entity.User, len(entUsers))
+	for i, entUser := range entUsers {
+		users[i] = toDomainUser(entUser)
+	}
+	return users, nil
+}
+
+func (r *userRepository) Count(ctx context.Context, q *listquery.Query) (int64, error) {
+	count, err := r.clientFor(ctx).User.Query().Where(predicate.User(listquery.EntFilter(q)), user.DeletedAtIsNil()).Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return int64(count), nil
+}
+
 func toDomainUser(entUser *ent.User) *entity.User {
 	return &entity.User{
 		ID:        uint(entUser.ID),
@@ -0,0 +1,62 @@
+package implementation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/enttest"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/entity"
+	"github.com/azahir21/go-backend-boilerplate/pkg/listquery"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestUserRepository_SoftDelete(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	repo := NewUserRepository(client)
+
+	alice := &entity.User{Username: "alice", Email: "alice@example.com", Password: "x", Role: "user"}
+	bob := &entity.User{Username: "bob", Email: "bob@example.com", Password: "x", Role: "user"}
+	for _, u := range []*entity.User{alice, bob} {
+		if err := repo.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Delete(ctx, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.FindByID(ctx, alice.ID); !ent.IsNotFound(err) {
+		t.Errorf("FindByID() of a deleted user error = %v, want not found", err)
+	}
+	if _, err := repo.FindByUsername(ctx, "alice"); !ent.IsNotFound(err) {
+		t.Errorf("FindByUsername() of a deleted user error = %v, want not found", err)
+	}
+	if taken, err := repo.UsernameTaken(ctx, "alice"); err != nil || !taken {
+		t.Errorf("UsernameTaken() of a deleted user = %v, %v, want it kept until the purge", taken, err)
+	}
+	q := &listquery.Query{Limit: 10, Page: 1}
+	users, err := repo.List(ctx, q)
+	if err != nil || len(users) != 1 || users[0].Username != "bob" {
+		t.Errorf("List() = %v, %v, want only bob", users, err)
+	}
+	if n, err := repo.Count(ctx, q); err != nil || n != 1 {
+		t.Errorf("Count() = %d, %v, want 1", n, err)
+	}
+	if err := repo.Delete(ctx, alice.ID); !ent.IsNotFound(err) {
+		t.Errorf("Delete() of a deleted user error = %v, want not found", err)
+	}
+
+	if n, err := repo.PurgeDeleted(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("PurgeDeleted() before the deletion = %d, %v, want 0", n, err)
+	}
+	if n, err := repo.PurgeDeleted(ctx, time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("PurgeDeleted() = %d, %v, want 1", n, err)
+	}
+	if taken, _ := repo.UsernameTaken(ctx, "alice"); taken {
+		t.Error("the username of a purged user is still taken")
+	}
+}
@@ -2,15 +2,31 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/entity"
+	"github.com/azahir21/go-backend-boilerplate/pkg/listquery"
 )
 
+// UserRepository stores users. Deleted users are soft-deleted: they are left out of every
+// lookup but keep their username and email until they are purged.
 type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) error
 	FindByUsername(ctx context.Context, username string) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
 	FindByID(ctx context.Context, id uint) (*entity.User, error)
+	// UsernameTaken reports whether a user, deleted or not, has the username.
+	UsernameTaken(ctx context.Context, username string) (bool, error)
+	// EmailTaken reports whether a user, deleted or not, has the email.
+	EmailTaken(ctx context.Context, email string) (bool, error)
 	Update(ctx context.Context, user *entity.User) error
+	// Delete soft-deletes the user by setting its deleted_at.
 	Delete(ctx context.Context, id uint) error
+	// PurgeDeleted permanently deletes the users soft-deleted before the given time and
+	// returns their number.
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+	// List returns up to q.FetchLimit() users matching the query, in the query's order.
+	List(ctx context.Context, q *listquery.Query) ([]*entity.User, error)
+	// Count returns the number of users matching the query's filters.
+	Count(ctx context.Context, q *listquery.Query) (int64, error)
 }
@@ -0,0 +1,13 @@
+package usecase
+
+// EventUserRegistered is published to the outbox when a user is created, by registration or
+// as an admin.
+const EventUserRegistered = "user.registered"
+
+// UserRegistered is the payload of EventUserRegistered.
+type UserRegistered struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
@@ -0,0 +1,11 @@
+package usecase
+
+// JobSendWelcomeEmail is enqueued when a user is created, to send them a welcome email.
+const JobSendWelcomeEmail = "user.send_welcome_email"
+
+// WelcomeEmail is the payload of JobSendWelcomeEmail.
+type WelcomeEmail struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
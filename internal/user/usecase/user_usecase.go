@@ -3,13 +3,18 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strconv"
+	"time"
 
 	"github.com/azahir21/go-backend-boilerplate/ent"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/entity"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/helper"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/jobs"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/outbox"
 	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
 	"github.com/azahir21/go-backend-boilerplate/internal/user/delivery/http/dto"
 	"github.com/azahir21/go-backend-boilerplate/internal/user/repository"
+	"github.com/azahir21/go-backend-boilerplate/pkg/listquery"
 )
 
 var (
@@ -18,56 +23,108 @@ var (
 	errInvalidCredentials = errors.New("invalid credentials")
 )
 
+// UserListSchema is the allow-list of fields for listing users.
+var UserListSchema = &listquery.Schema{
+	Fields: []listquery.Field{
+		{Name: "id", Type: listquery.Int, Sortable: true, Filterable: true},
+		{Name: "username", Type: listquery.String, Sortable: true, Filterable: true},
+		{Name: "email", Type: listquery.String, Filterable: true},
+		{Name: "role", Type: listquery.String, Filterable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpNe, listquery.OpIn}},
+		{Name: "created_at", Type: listquery.Time, Sortable: true, Filterable: true},
+		{Name: "updated_at", Type: listquery.Time, Sortable: true, Filterable: true},
+	},
+	DefaultSort: "-created_at",
+	Tiebreaker:  "id",
+}
+
 type UserUsecase interface {
 	Register(ctx context.Context, req *dto.RegisterRequest) (*dto.AuthResponse, error)
 	Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error)
 	GetProfile(ctx context.Context, userID uint) (*entity.User, error)
+	ListUsers(ctx context.Context, q *listquery.Query) (*listquery.Page[dto.UserResponse], error)
+	// CreateAdmin creates a user with the admin role, e.g. from the create-admin command.
+	CreateAdmin(ctx context.Context, username, email, password string) (*entity.User, error)
+	// PurgeDeletedUsers permanently deletes the users soft-deleted more than
+	// DeletedUserRetention ago and returns their number.
+	PurgeDeletedUsers(ctx context.Context) (int, error)
 }
 
+// DeletedUserRetention is how long soft-deleted users are kept before they are purged.
+const DeletedUserRetention = 30 * 24 * time.Hour
+
 type userUsecase struct {
-	userRepo repository.UserRepository
-	uow      unitofwork.UnitOfWork
+	uow       unitofwork.UnitOfWork
+	publisher *outbox.Publisher
+	jobs      *jobs.Client
 }
 
-func NewUserUsecase(userRepo repository.UserRepository, uow unitofwork.UnitOfWork) UserUsecase {
-	return &userUsecase{userRepo: userRepo, uow: uow}
+// NewUserUsecase creates the user usecase. jobClient enqueues JobSendWelcomeEmail for every
+// created user; with a nil client no welcome emails are sent.
+func NewUserUsecase(uow unitofwork.UnitOfWork, publisher *outbox.Publisher, jobClient *jobs.Client) UserUsecase {
+	return &userUsecase{uow: uow, publisher: publisher, jobs: jobClient}
 }
 
 func (u *userUsecase) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.AuthResponse, error) {
-	if err := u.ensureUsernameAvailable(ctx, req.Username); err != nil {
+	user, err := u.createUser(ctx, req.Username, req.Email, req.Password, "user")
+	if err != nil {
+		return nil, err
+	}
+	return u.buildAuthResponse(user)
+}
+
+func (u *userUsecase) CreateAdmin(ctx context.Context, username, email, password string) (*entity.User, error) {
+	return u.createUser(ctx, username, email, password, "admin")
+}
+
+func (u *userUsecase) createUser(ctx context.Context, username, email, password, role string) (*entity.User, error) {
+	if err := u.ensureUsernameAvailable(ctx, username); err != nil {
 		return nil, err
 	}
-	if err := u.ensureEmailAvailable(ctx, req.Email); err != nil {
+	if err := u.ensureEmailAvailable(ctx, email); err != nil {
 		return nil, err
 	}
 
-	hashedPassword, err := helper.HashPassword(req.Password)
+	hashedPassword, err := helper.HashPassword(password)
 	if err != nil {
 		return nil, err
 	}
 
 	user := &entity.User{
-		Username: req.Username,
-		Email:    req.Email,
+		Username: username,
+		Email:    email,
 		Password: hashedPassword,
-		Role:     "user",
+		Role:     role,
 	}
 
-	err = u.uow.Do(ctx, func(txUow unitofwork.UnitOfWork) error {
-		if err := txUow.UserRepository().Create(ctx, user); err != nil {
+	err = u.uow.Do(ctx, func(ctx context.Context, txUow unitofwork.UnitOfWork) error {
+		if err := unitofwork.Repo[repository.UserRepository](txUow).Create(ctx, user); err != nil {
 			return err
 		}
-		return nil
+		if u.jobs != nil {
+			welcome := WelcomeEmail{UserID: user.ID, Username: user.Username, Email: user.Email}
+			if err := u.jobs.Enqueue(ctx, JobSendWelcomeEmail, welcome); err != nil {
+				return err
+			}
+		}
+		return u.publisher.Publish(ctx, outbox.Event{
+			AggregateType: "user",
+			AggregateID:   strconv.FormatUint(uint64(user.ID), 10),
+			Type:          EventUserRegistered,
+			Payload:       UserRegistered{ID: user.ID, Username: user.Username, Email: user.Email, Role: user.Role},
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
+	return user, nil
+}
 
-	return u.buildAuthResponse(user)
+func (u *userUsecase) PurgeDeletedUsers(ctx context.Context) (int, error) {
+	return unitofwork.Repo[repository.UserRepository](u.uow).PurgeDeleted(ctx, time.Now().Add(-DeletedUserRetention))
 }
 
 func (u *userUsecase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
-	user, err := u.uow.UserRepository().FindByUsername(ctx, req.Username)
+	user, err := unitofwork.Repo[repository.UserRepository](u.uow).FindByUsername(ctx, req.Username)
 	if err != nil {
 		if ent.IsNotFound(err) {
 			return nil, errInvalidCredentials
@@ -83,29 +140,65 @@ func (u *userUsecase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.Au
 }
 
 func (u *userUsecase) GetProfile(ctx context.Context, userID uint) (*entity.User, error) {
-	return u.uow.UserRepository().FindByID(ctx, userID)
+	return unitofwork.Repo[repository.UserRepository](u.uow).FindByID(ctx, userID)
+}
+
+// ListUsers returns a page of users. Totals are only counted for page-numbered requests.
+func (u *userUsecase) ListUsers(ctx context.Context, q *listquery.Query) (*listquery.Page[dto.UserResponse], error) {
+	users, err := unitofwork.Repo[repository.UserRepository](u.uow).List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.UserResponse, len(users))
+	for i, user := range users {
+		items[i] = dto.ToUserResponse(user)
+	}
+
+	page := listquery.Paginate(q, items, userListKey)
+	if !q.CursorMode() {
+		total, err := unitofwork.Repo[repository.UserRepository](u.uow).Count(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		page.WithTotal(total)
+	}
+	return page, nil
+}
+
+func userListKey(user dto.UserResponse, field string) interface{} {
+	switch field {
+	case "id":
+		return int64(user.ID)
+	case "username":
+		return user.Username
+	case "created_at":
+		return user.CreatedAt
+	case "updated_at":
+		return user.UpdatedAt
+	}
+	return nil
 }
 
 func (u *userUsecase) ensureUsernameAvailable(ctx context.Context, username string) error {
-	_, err := u.uow.UserRepository().FindByUsername(ctx, username)
-	if err == nil {
-		return errUsernameExists
+	taken, err := unitofwork.Repo[repository.UserRepository](u.uow).UsernameTaken(ctx, username)
+	if err != nil {
+		return err
 	}
-	if ent.IsNotFound(err) {
-		return nil
+	if taken {
+		return errUsernameExists
 	}
-	return err
+	return nil
 }
 
 func (u *userUsecase) ensureEmailAvailable(ctx context.Context, email string) error {
-	_, err := u.uow.UserRepository().FindByEmail(ctx, email)
-	if err == nil {
-		return errEmailExists
+	taken, err := unitofwork.Repo[repository.UserRepository](u.uow).EmailTaken(ctx, email)
+	if err != nil {
+		return err
 	}
-	if ent.IsNotFound(err) {
-		return nil
+	if taken {
+		return errEmailExists
 	}
-	return err
+	return nil
 }
 
 func (u *userUsecase) buildAuthResponse(user *entity.User) (*dto.AuthResponse, error) {
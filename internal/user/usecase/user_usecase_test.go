@@ -0,0 +1,65 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/azahir21/go-backend-boilerplate/ent"
+	"github.com/azahir21/go-backend-boilerplate/ent/enttest"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/helper"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/jobs"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/outbox"
+	"github.com/azahir21/go-backend-boilerplate/internal/shared/unitofwork"
+	"github.com/azahir21/go-backend-boilerplate/internal/user/delivery/http/dto"
+	"github.com/azahir21/go-backend-boilerplate/internal/user/repository/implementation"
+	"github.com/azahir21/go-backend-boilerplate/internal/user/usecase"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	unitofwork.RegisterRepository(implementation.NewUserRepository)
+}
+
+func newTestUsecase(t *testing.T) (usecase.UserUsecase, *ent.Client) {
+	t.Helper()
+	helper.InitJWT("test-secret", 1)
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	jobClient := jobs.NewClient(jobs.NewSQLQueue(client), 3)
+	jobs.Handle(jobClient, usecase.JobSendWelcomeEmail, func(context.Context, usecase.WelcomeEmail) error { return nil })
+	return usecase.NewUserUsecase(unitofwork.NewUnitOfWork(client, log), outbox.NewPublisher(true), jobClient), client
+}
+
+func TestUserUsecase_RegisterEnqueuesWelcomeEmail(t *testing.T) {
+	ctx := context.Background()
+	u, client := newTestUsecase(t)
+
+	if _, err := u.Register(ctx, &dto.RegisterRequest{Username: "alice", Email: "alice@example.com", Password: "secret123"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	j := client.Job.Query().OnlyX(ctx)
+	var welcome usecase.WelcomeEmail
+	if err := json.Unmarshal(j.Payload, &welcome); err != nil || j.Type != usecase.JobSendWelcomeEmail || welcome.Email != "alice@example.com" {
+		t.Errorf("job = %s %+v, %v, want a welcome email to alice", j.Type, welcome, err)
+	}
+
+	// Failing to publish the event rolls back the user and the job
+	if _, err := client.ExecContext(ctx, "DROP TABLE outbox_events"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Register(ctx, &dto.RegisterRequest{Username: "bob", Email: "bob@example.com", Password: "secret123"}); err == nil {
+		t.Fatal("Register() without an outbox succeeded")
+	}
+	if n := client.Job.Query().CountX(ctx); n != 1 {
+		t.Errorf("%d jobs enqueued, want none for the rolled back registration", n)
+	}
+	if n := client.User.Query().CountX(ctx); n != 1 {
+		t.Errorf("%d users stored, want none for the rolled back registration", n)
+	}
+}
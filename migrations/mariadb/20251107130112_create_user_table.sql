@@ -0,0 +1,20 @@
+-- +goose Up
+-- MariaDB adds DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP to NOT NULL timestamp
+-- columns without a default, so ent declares its timestamp columns NULL
+CREATE TABLE `users` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `username` varchar(255) NOT NULL,
+    `email` varchar(255) NOT NULL,
+    `password` varchar(255) NOT NULL,
+    `role` varchar(255) NOT NULL DEFAULT 'user',
+    `status` varchar(255) NOT NULL DEFAULT 'active',
+    `created_at` timestamp NULL,
+    `updated_at` timestamp NULL,
+    `deleted_at` timestamp NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `username` (`username`),
+    UNIQUE INDEX `email` (`email`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+
+-- +goose Down
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- MariaDB adds DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP to NOT NULL timestamp
+-- columns without a default, so ent declares its timestamp columns NULL
+CREATE TABLE `outbox_events` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `aggregate_type` varchar(255) NOT NULL,
+    `aggregate_id` varchar(255) NOT NULL,
+    `event_type` varchar(255) NOT NULL,
+    `payload` blob NOT NULL,
+    `status` varchar(255) NOT NULL DEFAULT 'pending',
+    `attempts` bigint NOT NULL DEFAULT 0,
+    `last_error` longtext NULL,
+    `available_at` timestamp NULL,
+    `created_at` timestamp NULL,
+    `delivered_at` timestamp NULL,
+    PRIMARY KEY (`id`),
+    INDEX `outboxevent_status_available_at` (`status`, `available_at`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+
+-- +goose Down
+DROP TABLE IF EXISTS `outbox_events`;
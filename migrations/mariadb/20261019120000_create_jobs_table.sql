@@ -0,0 +1,19 @@
+-- +goose Up
+-- MariaDB adds DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP to NOT NULL timestamp
+-- columns without a default, so ent declares its timestamp columns NULL
+CREATE TABLE `jobs` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `type` varchar(255) NOT NULL,
+    `payload` blob NOT NULL,
+    `status` varchar(255) NOT NULL DEFAULT 'pending',
+    `attempts` bigint NOT NULL DEFAULT 0,
+    `max_attempts` bigint NOT NULL,
+    `last_error` longtext NULL,
+    `run_at` timestamp NULL,
+    `created_at` timestamp NULL,
+    PRIMARY KEY (`id`),
+    INDEX `job_type_status_run_at` (`type`, `status`, `run_at`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+
+-- +goose Down
+DROP TABLE IF EXISTS `jobs`;
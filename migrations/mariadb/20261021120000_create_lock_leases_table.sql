@@ -0,0 +1,13 @@
+-- +goose Up
+-- MariaDB adds DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP to NOT NULL timestamp
+-- columns without a default, so ent declares its timestamp columns NULL
+CREATE TABLE `lock_leases` (
+    `id` varchar(255) NOT NULL,
+    `owner` varchar(255) NOT NULL,
+    `fence` bigint NOT NULL,
+    `expires_at` timestamp NULL,
+    PRIMARY KEY (`id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+
+-- +goose Down
+DROP TABLE IF EXISTS `lock_leases`;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- The scheduler takes its leases from the lock package now
+DROP TABLE IF EXISTS `scheduler_leases`;
+
+-- +goose Down
+-- MariaDB adds DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP to NOT NULL timestamp
+-- columns without a default, so ent declares its timestamp columns NULL
+CREATE TABLE `scheduler_leases` (
+    `id` varchar(255) NOT NULL,
+    `owner` varchar(255) NOT NULL,
+    `expires_at` timestamp NULL,
+    PRIMARY KEY (`id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
@@ -0,0 +1,30 @@
+// Package migrations embeds the goose SQL migrations, so that the binary can apply them
+// without access to the source tree.
+//
+// Each supported dialect has its own directory of migrations, written in the dialect's SQL
+// and producing the schema that ent expects, so that the drift check passes on every driver.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+// FS holds one directory of migration files per dialect.
+//
+//go:embed postgres/*.sql mysql/*.sql mariadb/*.sql sqlite3/*.sql
+var FS embed.FS
+
+// Dialects lists the migration directories, named after the database drivers.
+var Dialects = []string{"postgres", "mysql", "mariadb", "sqlite3"}
+
+// ForDriver returns the migrations for a database driver, with the files at the root.
+func ForDriver(driver string) (fs.FS, error) {
+	for _, dialect := range Dialects {
+		if dialect == driver {
+			return fs.Sub(FS, dialect)
+		}
+	}
+	return nil, fmt.Errorf("no migrations for database driver: %s", driver)
+}
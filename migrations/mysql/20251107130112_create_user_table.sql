@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE `users` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `username` varchar(255) NOT NULL,
+    `email` varchar(255) NOT NULL,
+    `password` varchar(255) NOT NULL,
+    `role` varchar(255) NOT NULL DEFAULT 'user',
+    `status` varchar(255) NOT NULL DEFAULT 'active',
+    `created_at` timestamp NOT NULL,
+    `updated_at` timestamp NOT NULL,
+    `deleted_at` timestamp NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `username` (`username`),
+    UNIQUE INDEX `email` (`email`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+
+-- +goose Down
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE `scheduled_runs` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `task` varchar(255) NOT NULL,
+    `trigger` varchar(255) NOT NULL,
+    `instance` varchar(255) NOT NULL,
+    `status` varchar(255) NOT NULL DEFAULT 'running',
+    `error` longtext NULL,
+    `started_at` timestamp NOT NULL,
+    `finished_at` timestamp NULL,
+    PRIMARY KEY (`id`),
+    INDEX `scheduledrun_task_started_at` (`task`, `started_at`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+
+CREATE TABLE `scheduler_leases` (
+    `id` varchar(255) NOT NULL,
+    `owner` varchar(255) NOT NULL,
+    `expires_at` timestamp NOT NULL,
+    PRIMARY KEY (`id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+
+-- +goose Down
+DROP TABLE IF EXISTS `scheduler_leases`;
+DROP TABLE IF EXISTS `scheduled_runs`;
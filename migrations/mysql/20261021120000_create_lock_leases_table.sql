@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE `lock_leases` (
+    `id` varchar(255) NOT NULL,
+    `owner` varchar(255) NOT NULL,
+    `fence` bigint NOT NULL,
+    `expires_at` timestamp NOT NULL,
+    PRIMARY KEY (`id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+
+-- +goose Down
+DROP TABLE IF EXISTS `lock_leases`;
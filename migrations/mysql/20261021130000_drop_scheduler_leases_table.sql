@@ -0,0 +1,11 @@
+-- +goose Up
+-- The scheduler takes its leases from the lock package now
+DROP TABLE IF EXISTS `scheduler_leases`;
+
+-- +goose Down
+CREATE TABLE `scheduler_leases` (
+    `id` varchar(255) NOT NULL,
+    `owner` varchar(255) NOT NULL,
+    `expires_at` timestamp NOT NULL,
+    PRIMARY KEY (`id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_bin;
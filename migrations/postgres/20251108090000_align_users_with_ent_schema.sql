@@ -0,0 +1,38 @@
+-- +goose Up
+-- Match the schema ent generates, so that the drift check passes: an identity primary key,
+-- character varying columns, timestamps without database defaults (ent sets them) and the
+-- "<table>_<column>_key" names of unique indexes.
+ALTER TABLE users ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE IF EXISTS users_id_seq;
+ALTER TABLE users ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('users', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM users;
+
+ALTER TABLE users
+    ALTER COLUMN username TYPE character varying,
+    ALTER COLUMN email TYPE character varying,
+    ALTER COLUMN password TYPE character varying,
+    ALTER COLUMN role TYPE character varying,
+    ALTER COLUMN status TYPE character varying,
+    ALTER COLUMN created_at DROP DEFAULT,
+    ALTER COLUMN updated_at DROP DEFAULT;
+
+ALTER INDEX users_username_idx RENAME TO users_username_key;
+ALTER INDEX users_email_idx RENAME TO users_email_key;
+
+-- +goose Down
+ALTER INDEX users_email_key RENAME TO users_email_idx;
+ALTER INDEX users_username_key RENAME TO users_username_idx;
+
+ALTER TABLE users
+    ALTER COLUMN username TYPE TEXT,
+    ALTER COLUMN email TYPE TEXT,
+    ALTER COLUMN password TYPE TEXT,
+    ALTER COLUMN role TYPE TEXT,
+    ALTER COLUMN status TYPE TEXT,
+    ALTER COLUMN created_at SET DEFAULT now(),
+    ALTER COLUMN updated_at SET DEFAULT now();
+
+ALTER TABLE users ALTER COLUMN id DROP IDENTITY IF EXISTS;
+CREATE SEQUENCE users_id_seq OWNED BY users.id;
+SELECT setval('users_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM users;
+ALTER TABLE users ALTER COLUMN id SET DEFAULT nextval('users_id_seq');
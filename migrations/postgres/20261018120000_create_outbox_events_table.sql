@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE outbox_events (
+    id bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    aggregate_type character varying NOT NULL,
+    aggregate_id character varying NOT NULL,
+    event_type character varying NOT NULL,
+    payload bytea NOT NULL,
+    status character varying NOT NULL DEFAULT 'pending',
+    attempts bigint NOT NULL DEFAULT 0,
+    last_error text NULL,
+    available_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL,
+    delivered_at timestamptz NULL
+);
+CREATE INDEX outboxevent_status_available_at ON outbox_events (status, available_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE jobs (
+    id bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    type character varying NOT NULL,
+    payload bytea NOT NULL,
+    status character varying NOT NULL DEFAULT 'pending',
+    attempts bigint NOT NULL DEFAULT 0,
+    max_attempts bigint NOT NULL,
+    last_error text NULL,
+    run_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL
+);
+CREATE INDEX job_type_status_run_at ON jobs (type, status, run_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS jobs;
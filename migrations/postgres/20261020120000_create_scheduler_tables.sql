@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE scheduled_runs (
+    id bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    task character varying NOT NULL,
+    trigger character varying NOT NULL,
+    instance character varying NOT NULL,
+    status character varying NOT NULL DEFAULT 'running',
+    error text NULL,
+    started_at timestamptz NOT NULL,
+    finished_at timestamptz NULL
+);
+CREATE INDEX scheduledrun_task_started_at ON scheduled_runs (task, started_at);
+
+CREATE TABLE scheduler_leases (
+    id character varying NOT NULL PRIMARY KEY,
+    owner character varying NOT NULL,
+    expires_at timestamptz NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS scheduler_leases;
+DROP TABLE IF EXISTS scheduled_runs;
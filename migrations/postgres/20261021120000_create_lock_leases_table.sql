@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE lock_leases (
+    id character varying NOT NULL PRIMARY KEY,
+    owner character varying NOT NULL,
+    fence bigint NOT NULL,
+    expires_at timestamptz NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS lock_leases;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- The scheduler takes its leases from the lock package now
+DROP TABLE IF EXISTS scheduler_leases;
+
+-- +goose Down
+CREATE TABLE scheduler_leases (
+    id character varying NOT NULL PRIMARY KEY,
+    owner character varying NOT NULL,
+    expires_at timestamptz NOT NULL
+);
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE `users` (
+    `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    `username` text NOT NULL,
+    `email` text NOT NULL,
+    `password` text NOT NULL,
+    `role` text NOT NULL DEFAULT ('user'),
+    `status` text NOT NULL DEFAULT ('active'),
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime NOT NULL,
+    `deleted_at` datetime NULL
+);
+CREATE UNIQUE INDEX `users_username_key` ON `users` (`username`);
+CREATE UNIQUE INDEX `users_email_key` ON `users` (`email`);
+
+-- +goose Down
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE `outbox_events` (
+    `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    `aggregate_type` text NOT NULL,
+    `aggregate_id` text NOT NULL,
+    `event_type` text NOT NULL,
+    `payload` blob NOT NULL,
+    `status` text NOT NULL DEFAULT ('pending'),
+    `attempts` integer NOT NULL DEFAULT (0),
+    `last_error` text NULL,
+    `available_at` datetime NOT NULL,
+    `created_at` datetime NOT NULL,
+    `delivered_at` datetime NULL
+);
+CREATE INDEX `outboxevent_status_available_at` ON `outbox_events` (`status`, `available_at`);
+
+-- +goose Down
+DROP TABLE IF EXISTS `outbox_events`;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE `jobs` (
+    `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    `type` text NOT NULL,
+    `payload` blob NOT NULL,
+    `status` text NOT NULL DEFAULT ('pending'),
+    `attempts` integer NOT NULL DEFAULT (0),
+    `max_attempts` integer NOT NULL,
+    `last_error` text NULL,
+    `run_at` datetime NOT NULL,
+    `created_at` datetime NOT NULL
+);
+CREATE INDEX `job_type_status_run_at` ON `jobs` (`type`, `status`, `run_at`);
+
+-- +goose Down
+DROP TABLE IF EXISTS `jobs`;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE `scheduled_runs` (
+    `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    `task` text NOT NULL,
+    `trigger` text NOT NULL,
+    `instance` text NOT NULL,
+    `status` text NOT NULL DEFAULT ('running'),
+    `error` text NULL,
+    `started_at` datetime NOT NULL,
+    `finished_at` datetime NULL
+);
+CREATE INDEX `scheduledrun_task_started_at` ON `scheduled_runs` (`task`, `started_at`);
+
+CREATE TABLE `scheduler_leases` (
+    `id` text NOT NULL,
+    `owner` text NOT NULL,
+    `expires_at` datetime NOT NULL,
+    PRIMARY KEY (`id`)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS `scheduler_leases`;
+DROP TABLE IF EXISTS `scheduled_runs`;
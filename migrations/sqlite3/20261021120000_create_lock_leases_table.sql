@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE `lock_leases` (
+    `id` text NOT NULL,
+    `owner` text NOT NULL,
+    `fence` integer NOT NULL,
+    `expires_at` datetime NOT NULL,
+    PRIMARY KEY (`id`)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS `lock_leases`;
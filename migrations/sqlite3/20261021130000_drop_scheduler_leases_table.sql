@@ -0,0 +1,11 @@
+-- +goose Up
+-- The scheduler takes its leases from the lock package now
+DROP TABLE IF EXISTS `scheduler_leases`;
+
+-- +goose Down
+CREATE TABLE `scheduler_leases` (
+    `id` text NOT NULL,
+    `owner` text NOT NULL,
+    `expires_at` datetime NOT NULL,
+    PRIMARY KEY (`id`)
+);
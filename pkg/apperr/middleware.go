@@ -27,7 +27,7 @@ func ErrorHandlerMiddleware(log *logrus.Logger, config Config) gin.HandlerFunc {
 				}
 
 				// Log the error with full details
-				log.WithFields(logrus.Fields{
+				log.WithContext(c.Request.Context()).WithFields(logrus.Fields{
 					"status":     appErr.Status,
 					"message":    appErr.Message,
 					"detail":     appErr.Detail,
@@ -48,7 +48,7 @@ func ErrorHandlerMiddleware(log *logrus.Logger, config Config) gin.HandlerFunc {
 			err := c.Errors.Last().Err
 			appErr := AsAppError(err)
 
-			log.WithFields(logrus.Fields{
+			log.WithContext(c.Request.Context()).WithFields(logrus.Fields{
 				"status":     appErr.Status,
 				"message":    appErr.Message,
 				"detail":     appErr.Detail,
@@ -75,7 +75,7 @@ func RecoveryMiddleware(log *logrus.Logger, config Config) gin.HandlerFunc {
 					appErr = appErr.WithCause(err)
 				}
 
-				log.WithFields(logrus.Fields{
+				log.WithContext(c.Request.Context()).WithFields(logrus.Fields{
 					"panic":      r,
 					"stacktrace": appErr.Stacktrace,
 					"path":       c.Request.URL.Path,
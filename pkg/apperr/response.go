@@ -1,7 +1,14 @@
 package apperr
 
 import (
+	"strings"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/httpresp"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 // ErrorResponse represents the JSON structure for error responses.
@@ -61,6 +68,8 @@ func NewResponder(config Config) *Responder {
 }
 
 // Respond sends an error response based on the AppError and configuration.
+// The format follows the Accept header: JSON (default), MessagePack, or a
+// google.rpc.Status message for protobuf clients.
 func (r *Responder) Respond(c *gin.Context, err *AppError) {
 	if err == nil {
 		return
@@ -79,7 +88,35 @@ func (r *Responder) Respond(c *gin.Context, err *AppError) {
 		response.Stacktrace = err.Stacktrace
 	}
 
-	c.JSON(err.Status.HTTPCode(), response)
+	code := err.Status.HTTPCode()
+	switch httpresp.Negotiate(c) {
+	case httpresp.FormatMsgPack:
+		c.Render(code, render.MsgPack{Data: response})
+	case httpresp.FormatProtobuf:
+		c.ProtoBuf(code, r.statusProto(err, response))
+	default:
+		c.JSON(code, response)
+	}
+}
+
+// statusProto converts an error response to google.rpc.Status, the error model shared with gRPC.
+// Detail and stacktrace, when enabled, are attached as google.rpc.DebugInfo.
+func (r *Responder) statusProto(err *AppError, response ErrorResponse) *spb.Status {
+	st := &spb.Status{
+		Code:    int32(err.Status.GRPCCode()),
+		Message: response.Message,
+	}
+	if response.Detail == "" && response.Stacktrace == "" {
+		return st
+	}
+	info := &errdetails.DebugInfo{Detail: response.Detail}
+	if response.Stacktrace != "" {
+		info.StackEntries = strings.Split(response.Stacktrace, "\n")
+	}
+	if detail, marshalErr := anypb.New(info); marshalErr == nil {
+		st.Details = append(st.Details, detail)
+	}
+	return st
 }
 
 // RespondError converts a generic error to AppError and sends the response.
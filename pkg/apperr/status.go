@@ -1,6 +1,10 @@
 package apperr
 
-import "net/http"
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
 
 // Status represents a standardized error status code.
 type Status string
@@ -39,6 +43,31 @@ var statusHTTPMap = map[Status]int{
 	StatusUnprocessableEntity: http.StatusUnprocessableEntity,
 }
 
+// statusGRPCMap maps Status to gRPC status codes.
+var statusGRPCMap = map[Status]codes.Code{
+	StatusBadGateway:          codes.Unavailable,
+	StatusBadRequest:          codes.InvalidArgument,
+	StatusConflict:            codes.AlreadyExists,
+	StatusForbidden:           codes.PermissionDenied,
+	StatusInternalServer:      codes.Internal,
+	StatusMethodNotAllowed:    codes.Unimplemented,
+	StatusNotFound:            codes.NotFound,
+	StatusNotImplemented:      codes.Unimplemented,
+	StatusServiceUnavailable:  codes.Unavailable,
+	StatusTimeout:             codes.DeadlineExceeded,
+	StatusTooManyRequests:     codes.ResourceExhausted,
+	StatusUnauthorized:        codes.Unauthenticated,
+	StatusUnprocessableEntity: codes.FailedPrecondition,
+}
+
+// GRPCCode returns the gRPC status code for the given status.
+func (s Status) GRPCCode() codes.Code {
+	if code, ok := statusGRPCMap[s]; ok {
+		return code
+	}
+	return codes.Internal
+}
+
 // HTTPCode returns the HTTP status code for the given status.
 func (s Status) HTTPCode() int {
 	if code, ok := statusHTTPMap[s]; ok {
@@ -14,19 +14,98 @@ import (
 // The values are read by viper from a config file or environment variables.
 
 type Config struct {
-	DB      Database      `mapstructure:"database"`
-	Mongo   MongoConfig   `mapstructure:"mongo"`
-	Server  Server        `mapstructure:"server"`
-	JWT     JWT           `mapstructure:"jwt"`
-	Admin   Admin         `mapstructure:"default_admin"`
-	Cache   Cache         `mapstructure:"cache"`
-	Storage StorageConfig `mapstructure:"storage"`
-	Email   EmailConfig   `mapstructure:"email"`
+	DB        Database        `mapstructure:"database"`
+	Mongo     MongoConfig     `mapstructure:"mongo"`
+	Server    Server          `mapstructure:"server"`
+	JWT       JWT             `mapstructure:"jwt"`
+	Admin     Admin           `mapstructure:"default_admin"`
+	Cache     Cache           `mapstructure:"cache"`
+	Storage   StorageConfig   `mapstructure:"storage"`
+	Email     EmailConfig     `mapstructure:"email"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Outbox    OutboxConfig    `mapstructure:"outbox"`
+	Jobs      JobsConfig      `mapstructure:"jobs"`
+	Scheduler SchedulerConfig `mapstructure:"scheduler"`
+	// Modules enables or disables feature modules by name; unlisted modules are enabled.
+	Modules map[string]ModuleConfig `mapstructure:"modules"`
+}
+
+// OutboxConfig holds the settings of the dispatcher delivering the domain events written to the
+// outbox table.
+type OutboxConfig struct {
+	// Enable turns the outbox on; when off, published events are discarded.
+	Enable bool `mapstructure:"enable"`
+	// DisableDispatcher keeps this process from delivering events while it still writes them,
+	// for replicas that leave delivery to the others.
+	DisableDispatcher bool `mapstructure:"disable_dispatcher"`
+	// PollInterval is how often the outbox is checked for pending events.
+	PollInterval string `mapstructure:"poll_interval"`
+	// BatchSize is the number of pending events read per poll.
+	BatchSize int `mapstructure:"batch_size"`
+	// MaxAttempts is how many deliveries of an event fail before it is dead-lettered.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryBackoff is the wait before the first retry of an event; it doubles with each attempt.
+	RetryBackoff string `mapstructure:"retry_backoff"`
+	// ClaimTimeout is how long an event claimed by a dispatcher is hidden from the others,
+	// after which an unfinished delivery is retried.
+	ClaimTimeout string `mapstructure:"claim_timeout"`
+	// Retention is how long delivered events are kept; empty keeps them.
+	Retention string              `mapstructure:"retention"`
+	Webhook   OutboxWebhookConfig `mapstructure:"webhook"`
+}
+
+// OutboxWebhookConfig holds the settings of the sink posting every event to an HTTP endpoint.
+type OutboxWebhookConfig struct {
+	// URL receives the events as JSON; empty disables the sink.
+	URL     string `mapstructure:"url"`
+	Timeout string `mapstructure:"timeout"`
+}
+
+// JobsConfig holds the settings of the background job queue and its workers. Jobs are queued
+// in redis when cache.type is redis, and in the SQL database otherwise.
+type JobsConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// EmbeddedWorker runs a worker in the serve process as well; otherwise only the worker
+	// command runs jobs.
+	EmbeddedWorker bool `mapstructure:"embedded_worker"`
+	// Concurrency is the number of jobs a worker runs at the same time.
+	Concurrency int `mapstructure:"concurrency"`
+	// PollInterval is how often an idle worker checks for due jobs.
+	PollInterval string `mapstructure:"poll_interval"`
+	// MaxAttempts is how many runs of a job fail before it is dead, unless set when enqueuing.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryBackoff is the wait before the first retry of a job; it doubles with each attempt.
+	RetryBackoff string `mapstructure:"retry_backoff"`
+	// Timeout cancels the context of a job running longer.
+	Timeout string `mapstructure:"timeout"`
+}
+
+// SchedulerConfig holds the settings of the scheduler running recurring tasks. Every replica
+// runs a scheduler and the one holding the leader lease runs the tasks.
+type SchedulerConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// Timezone is the IANA name of the location in which schedules are evaluated; empty is UTC.
+	Timezone string `mapstructure:"timezone"`
+	// LeaseTTL is how long the leader lease lasts without renewal, i.e. how long the tasks
+	// pause when the leader dies.
+	LeaseTTL string `mapstructure:"lease_ttl"`
+	// Timeout cancels the context of a task running longer, unless set when registering it.
+	Timeout string `mapstructure:"timeout"`
+	// HistoryRetention is how long the run history is kept; empty keeps it.
+	HistoryRetention string `mapstructure:"history_retention"`
+}
+
+// ModuleConfig holds the settings of a feature module.
+type ModuleConfig struct {
+	Enable bool `mapstructure:"enable"`
 }
 
 type Cache struct {
-	Enable    bool            `mapstructure:"enable"`
-	Type      string          `mapstructure:"type"`
+	Enable bool   `mapstructure:"enable"`
+	Type   string `mapstructure:"type"`
+	// KeyPrefix is prepended to the key of every cache entry, e.g. to share a redis
+	// database between applications.
+	KeyPrefix string          `mapstructure:"key_prefix"`
 	Redis     RedisConfig     `mapstructure:"redis"`
 	Ristretto RistrettoConfig `mapstructure:"ristretto"`
 }
@@ -55,44 +134,261 @@ type Database struct {
 	Name        string `mapstructure:"name"`
 	SSLMode     string `mapstructure:"sslmode"`
 	AutoMigrate bool   `mapstructure:"auto_migrate"`
+
+	Migrations  MigrationsConfig  `mapstructure:"migrations"`
+	Transaction TransactionConfig `mapstructure:"transaction"`
+}
+
+// TransactionConfig holds the defaults of the transactions run by the unit of work.
+type TransactionConfig struct {
+	// Isolation is read_uncommitted, read_committed, repeatable_read or serializable; empty
+	// uses the default of the database.
+	Isolation string `mapstructure:"isolation"`
+	// MaxRetries is how many times a transaction failing on a serialization failure or a
+	// deadlock is run again.
+	MaxRetries int `mapstructure:"max_retries"`
+}
+
+// MigrationsConfig holds configuration for the SQL migrations embedded in the binary.
+type MigrationsConfig struct {
+	// ApplyOnStart applies pending migrations before the servers start. Replicas starting at
+	// the same time wait for each other on a database lock (an advisory lock on PostgreSQL).
+	ApplyOnStart bool `mapstructure:"apply_on_start"`
+	// CheckDrift fails startup when the ent schema differs from the migrated database.
+	CheckDrift bool `mapstructure:"check_drift"`
+	// LockTimeout bounds the wait for the migration lock held by another replica.
+	LockTimeout string `mapstructure:"lock_timeout"`
 }
 
 type Server struct {
-	Env     string              `mapstructure:"env"`
-	HTTP    HTTPServerConfig    `mapstructure:"http_server"`
-	GRPC    GRPCServerConfig    `mapstructure:"grpc_server"`
-	GraphQL GraphQLServerConfig `mapstructure:"graphql_server"`
+	Env        string              `mapstructure:"env"`
+	HTTP       HTTPServerConfig    `mapstructure:"http_server"`
+	GRPC       GRPCServerConfig    `mapstructure:"grpc_server"`
+	GraphQL    GraphQLServerConfig `mapstructure:"graphql_server"`
+	SinglePort SinglePortConfig    `mapstructure:"single_port"`
+	AccessLog  AccessLogConfig     `mapstructure:"access_log"`
+	Health     HealthConfig        `mapstructure:"health"`
+	Metrics    MetricsConfig       `mapstructure:"metrics"`
+	Tracing    TracingConfig       `mapstructure:"tracing"`
+	Shutdown   ShutdownConfig      `mapstructure:"shutdown"`
+}
+
+// SinglePortConfig holds configuration for serving REST, GraphQL and gRPC on one listener.
+// Connections are routed by protocol: HTTP/2 requests with an application/grpc content type
+// are handled by the gRPC server, HTTP/1.1 and other h2c requests by REST or, for the
+// /graphql paths, GraphQL. The ports of the individual servers are then ignored.
+type SinglePortConfig struct {
+	Enable bool      `mapstructure:"enable"`
+	Port   string    `mapstructure:"port"`
+	TLS    TLSConfig `mapstructure:"tls"`
+}
+
+// TLSConfig holds TLS configuration for a server. The certificate, key and client CA bundle
+// are reloaded when the files change, e.g. after a certificate rotation.
+type TLSConfig struct {
+	Enable   bool   `mapstructure:"enable"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// ClientAuth is "none", "optional" (client certificates are verified when presented) or
+	// "require" (mutual TLS).
+	ClientAuth string `mapstructure:"client_auth"`
+	// ClientCAFile is the PEM bundle client certificates are verified against.
+	ClientCAFile string `mapstructure:"client_ca_file"`
+}
+
+// ShutdownConfig holds configuration for graceful shutdown on SIGINT/SIGTERM.
+type ShutdownConfig struct {
+	// DrainPeriod is how long the service keeps serving while reporting not ready, so that load
+	// balancers stop routing new requests to it before the servers stop.
+	DrainPeriod string `mapstructure:"drain_period"`
+	// Timeout bounds the shutdown of the servers; remaining connections are then closed.
+	Timeout string `mapstructure:"timeout"`
+}
+
+// TracingConfig holds configuration for OpenTelemetry tracing.
+type TracingConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// Exporter is "otlp", "stdout" or "none". With "none" spans are created (and trace IDs
+	// logged) but not exported.
+	Exporter    string `mapstructure:"exporter"`
+	ServiceName string `mapstructure:"service_name"`
+	// SampleRate is the fraction of new traces that are sampled; incoming sampled traces are always kept.
+	SampleRate float64    `mapstructure:"sample_rate"`
+	OTLP       OTLPConfig `mapstructure:"otlp"`
+}
+
+// OTLPConfig holds configuration for the OTLP/gRPC trace exporter.
+type OTLPConfig struct {
+	// Endpoint is the collector address, e.g. "localhost:4317".
+	Endpoint string            `mapstructure:"endpoint"`
+	Insecure bool              `mapstructure:"insecure"`
+	Headers  map[string]string `mapstructure:"headers"`
+}
+
+// MetricsConfig holds configuration for Prometheus metrics.
+type MetricsConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// Path is where metrics are served, e.g. "/metrics".
+	Path string `mapstructure:"path"`
+	// Port serves metrics on a separate admin server. When empty, metrics are served on the
+	// REST and GraphQL servers.
+	Port string `mapstructure:"port"`
+}
+
+// HealthConfig holds configuration for the liveness, readiness and dependency health endpoints
+// (/livez, /readyz, /healthz on the HTTP server) and the grpc.health.v1 service.
+type HealthConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// Timeout bounds each dependency check.
+	Timeout string `mapstructure:"timeout"`
+	// CacheTTL is how long a check result is reused before the dependency is checked again.
+	CacheTTL string `mapstructure:"cache_ttl"`
+}
+
+// AccessLogConfig holds configuration for request access logging on all transports.
+type AccessLogConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// SampleRate is the fraction (0.0-1.0) of successful requests that are logged.
+	// Requests that end with a 4xx/5xx status (or a non-OK gRPC code) are always logged.
+	SampleRate float64 `mapstructure:"sample_rate"`
+	// SkipPaths lists REST paths and gRPC full method names that are never logged.
+	SkipPaths []string `mapstructure:"skip_paths"`
+	// LogHeaders includes request headers (or gRPC metadata) in each log entry.
+	LogHeaders bool `mapstructure:"log_headers"`
+	// MaskHeaders lists header names whose values are replaced before logging.
+	MaskHeaders []string `mapstructure:"mask_headers"`
+	// MaskQueryParams lists query parameter names whose values are replaced before logging.
+	MaskQueryParams []string `mapstructure:"mask_query_params"`
 }
 
 type GraphQLServerConfig struct {
-	Port          string   `mapstructure:"port"`
-	Enable        bool     `mapstructure:"enable"`
-	CorsOrigins   []string `mapstructure:"cors_origins"`
-	ReadTimeout   string   `mapstructure:"read_timeout"`
-	WriteTimeout  string   `mapstructure:"write_timeout"`
-	IdleTimeout   string   `mapstructure:"idle_timeout"`
-	StartupBanner bool     `mapstructure:"startup_banner"`
+	Port          string    `mapstructure:"port"`
+	Enable        bool      `mapstructure:"enable"`
+	CorsOrigins   []string  `mapstructure:"cors_origins"`
+	ReadTimeout   string    `mapstructure:"read_timeout"`
+	WriteTimeout  string    `mapstructure:"write_timeout"`
+	IdleTimeout   string    `mapstructure:"idle_timeout"`
+	StartupBanner bool      `mapstructure:"startup_banner"`
+	TLS           TLSConfig `mapstructure:"tls"`
 }
 
 type HTTPServerConfig struct {
-	Port          string   `mapstructure:"port"`
-	Enable        bool     `mapstructure:"enable"`
-	CorsOrigins   []string `mapstructure:"cors_origins"`
-	ReadTimeout   string   `mapstructure:"read_timeout"`
-	WriteTimeout  string   `mapstructure:"write_timeout"`
-	IdleTimeout   string   `mapstructure:"idle_timeout"`
-	StartupBanner bool     `mapstructure:"startup_banner"`
+	Port          string            `mapstructure:"port"`
+	Enable        bool              `mapstructure:"enable"`
+	CorsOrigins   []string          `mapstructure:"cors_origins"`
+	ReadTimeout   string            `mapstructure:"read_timeout"`
+	WriteTimeout  string            `mapstructure:"write_timeout"`
+	IdleTimeout   string            `mapstructure:"idle_timeout"`
+	StartupBanner bool              `mapstructure:"startup_banner"`
+	Idempotency   IdempotencyConfig `mapstructure:"idempotency"`
+	Caching       HTTPCacheConfig   `mapstructure:"caching"`
+	Compression   CompressionConfig `mapstructure:"compression"`
+	Versioning    VersioningConfig  `mapstructure:"versioning"`
+	TLS           TLSConfig         `mapstructure:"tls"`
+}
+
+// VersioningConfig holds configuration for versioned REST routes.
+type VersioningConfig struct {
+	// Prefix is the path prefix of versioned routes, e.g. "/api" serves version v1 under "/api/v1".
+	Prefix string `mapstructure:"prefix"`
+	// DefaultVersion serves requests without a version in the path and without an Accept-Version header.
+	DefaultVersion string `mapstructure:"default_version"`
+	// Versions holds lifecycle metadata. Versions registered by modules but not listed here are active.
+	Versions []APIVersionConfig `mapstructure:"versions"`
+}
+
+// APIVersionConfig describes the lifecycle of one API version.
+type APIVersionConfig struct {
+	Name string `mapstructure:"name"`
+	// DeprecatedAt (RFC 3339) marks the version as deprecated and is sent in the Deprecation header.
+	DeprecatedAt string `mapstructure:"deprecated_at"`
+	// Sunset (RFC 3339) is when the version will be removed, sent in the Sunset header.
+	Sunset string `mapstructure:"sunset"`
+	// Link points to migration documentation, sent as Link with rel="deprecation".
+	Link string `mapstructure:"link"`
+}
+
+// CompressionConfig holds configuration for REST response compression.
+type CompressionConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// MinSize is the response size in bytes from which responses are compressed.
+	MinSize int `mapstructure:"min_size"`
+	// Algorithms lists the supported encodings ("zstd", "gzip") in order of preference.
+	Algorithms []string `mapstructure:"algorithms"`
+	// Level is one of "fastest", "default" or "best".
+	Level string `mapstructure:"level"`
+}
+
+// HTTPCacheConfig holds configuration for conditional requests and the server-side response cache.
+type HTTPCacheConfig struct {
+	// ETag enables ETag generation and If-None-Match/If-Modified-Since handling for GET and HEAD.
+	ETag bool `mapstructure:"etag"`
+	// WeakETag generates weak (W/"...") instead of strong ETags.
+	WeakETag bool `mapstructure:"weak_etag"`
+	// ResponseCache enables the server-side cache for endpoints that opt in via EndpointSpec.Cache.
+	// It requires the cache subsystem to be enabled.
+	ResponseCache bool `mapstructure:"response_cache"`
+	// DefaultTTL is used when an endpoint's CachePolicy does not set a TTL.
+	DefaultTTL string `mapstructure:"default_ttl"`
+}
+
+// IdempotencyConfig holds configuration for Idempotency-Key handling on REST endpoints
+// that opt in via EndpointSpec.Idempotent.
+type IdempotencyConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// TTL is how long a completed response is kept for replay.
+	TTL string `mapstructure:"ttl"`
+	// LockTimeout is how long an in-flight request holds its key before it may be retried.
+	LockTimeout string `mapstructure:"lock_timeout"`
 }
 
 type GRPCServerConfig struct {
-	Port                   string `mapstructure:"port"`
-	Enable                 bool   `mapstructure:"enable"`
-	MaxConnectionIdle      string `mapstructure:"max_connection_idle"`
-	Timeout                string `mapstructure:"timeout"`
-	MaxConnectionAge       string `mapstructure:"max_connection_age"`
-	MaxConnectionAgeGrace  string `mapstructure:"max_connection_age_grace"`
-	Time                   string `mapstructure:"time"`
-	ForceTransportSecurity bool   `mapstructure:"force_transport_security"`
+	Port                   string    `mapstructure:"port"`
+	Enable                 bool      `mapstructure:"enable"`
+	MaxConnectionIdle      string    `mapstructure:"max_connection_idle"`
+	Timeout                string    `mapstructure:"timeout"`
+	MaxConnectionAge       string    `mapstructure:"max_connection_age"`
+	MaxConnectionAgeGrace  string    `mapstructure:"max_connection_age_grace"`
+	Time                   string    `mapstructure:"time"`
+	ForceTransportSecurity bool      `mapstructure:"force_transport_security"`
+	TLS                    TLSConfig `mapstructure:"tls"`
+}
+
+// RateLimitConfig holds rate limiting configuration.
+// Counters live in redis when cache.type is "redis", otherwise in process memory.
+type RateLimitConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// APIKeyHeader is the header (or gRPC metadata key) used when key_by is "api_key".
+	APIKeyHeader string                   `mapstructure:"api_key_header"`
+	HTTP         RateLimitTransportConfig `mapstructure:"http"`
+	GRPC         RateLimitTransportConfig `mapstructure:"grpc"`
+	GraphQL      RateLimitTransportConfig `mapstructure:"graphql"`
+}
+
+// RateLimitTransportConfig holds the default limit and per-route overrides for one transport.
+type RateLimitTransportConfig struct {
+	Enable  bool            `mapstructure:"enable"`
+	Default RateLimitRule   `mapstructure:"default"`
+	Routes  []RateLimitRule `mapstructure:"routes"`
+}
+
+// RateLimitRule describes a single limit.
+type RateLimitRule struct {
+	// Match selects the route the rule applies to: "METHOD /route/template" for REST,
+	// the full method name ("/package.Service/Method") for gRPC, or the operation type and
+	// root field ("mutation login") for GraphQL.
+	// It is ignored for the transport default.
+	Match string `mapstructure:"match"`
+	// Algorithm is "token_bucket" or "sliding_window".
+	Algorithm string `mapstructure:"algorithm"`
+	// KeyBy is "ip", "user" or "api_key".
+	KeyBy    string `mapstructure:"key_by"`
+	Requests int    `mapstructure:"requests"`
+	Period   string `mapstructure:"period"`
+	// Burst is the bucket capacity for token_bucket (defaults to requests).
+	Burst int `mapstructure:"burst"`
+	// Disable turns off limiting for the matched route.
+	Disable bool `mapstructure:"disable"`
 }
 
 type JWT struct {
@@ -158,15 +454,15 @@ type EmailConfig struct {
 
 // MongoConfig holds configuration for MongoDB connection.
 type MongoConfig struct {
-	Enable              bool              `mapstructure:"enable"`
-	URI                 string            `mapstructure:"uri"`
-	Database            string            `mapstructure:"database"`
-	Username            string            `mapstructure:"username"`
-	Password            string            `mapstructure:"password"`
-	AuthSource          string            `mapstructure:"auth_source"`
-	ConnectTimeoutMS    int               `mapstructure:"connect_timeout_ms"`
-	MaxPoolSize         uint64            `mapstructure:"max_pool_size"`
-	MinPoolSize         uint64            `mapstructure:"min_pool_size"`
+	Enable           bool   `mapstructure:"enable"`
+	URI              string `mapstructure:"uri"`
+	Database         string `mapstructure:"database"`
+	Username         string `mapstructure:"username"`
+	Password         string `mapstructure:"password"`
+	AuthSource       string `mapstructure:"auth_source"`
+	ConnectTimeoutMS int    `mapstructure:"connect_timeout_ms"`
+	MaxPoolSize      uint64 `mapstructure:"max_pool_size"`
+	MinPoolSize      uint64 `mapstructure:"min_pool_size"`
 }
 
 // LoadConfig loads configuration from file or environment variables.
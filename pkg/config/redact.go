@@ -0,0 +1,50 @@
+package config
+
+import (
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// RedactedValue replaces secret values in redacted configurations.
+const RedactedValue = "[REDACTED]"
+
+// Redacted returns a copy of the configuration with passwords, keys, tokens and connection
+// strings (which may embed credentials) replaced, so that it can be printed or logged.
+// Empty values are kept to show that a secret is not set.
+func (c Config) Redacted() Config {
+	redact := func(s *string) {
+		if *s != "" {
+			*s = RedactedValue
+		}
+	}
+	redact(&c.DB.DSN)
+	redact(&c.DB.Password)
+	redact(&c.Mongo.URI)
+	redact(&c.Mongo.Password)
+	redact(&c.JWT.Secret)
+	redact(&c.Admin.Password)
+	redact(&c.Cache.Redis.Password)
+	redact(&c.Storage.S3.AccessKeyID)
+	redact(&c.Storage.S3.SecretAccessKey)
+	redact(&c.Email.SMTP.Password)
+	redact(&c.Email.SendGrid.APIKey)
+
+	// Exporter headers usually carry API keys
+	if len(c.Server.Tracing.OTLP.Headers) > 0 {
+		headers := make(map[string]string, len(c.Server.Tracing.OTLP.Headers))
+		for k := range c.Server.Tracing.OTLP.Headers {
+			headers[k] = RedactedValue
+		}
+		c.Server.Tracing.OTLP.Headers = headers
+	}
+	return c
+}
+
+// ToMap converts the configuration to nested maps keyed by the configuration file names,
+// e.g. for printing it in the configuration file format.
+func (c Config) ToMap() (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := mapstructure.Decode(c, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
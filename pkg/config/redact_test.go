@@ -0,0 +1,37 @@
+package config
+
+import "testing"
+
+func TestRedacted(t *testing.T) {
+	var cfg Config
+	cfg.DB.Password = "db-secret"
+	cfg.DB.User = "postgres"
+	cfg.JWT.Secret = "jwt-secret"
+	cfg.Server.Tracing.OTLP.Headers = map[string]string{"api-key": "otlp-secret"}
+
+	redacted := cfg.Redacted()
+	if redacted.DB.Password != RedactedValue || redacted.JWT.Secret != RedactedValue {
+		t.Errorf("secrets not redacted: %+v", redacted.DB)
+	}
+	if redacted.DB.User != "postgres" {
+		t.Errorf("DB.User = %q, want postgres", redacted.DB.User)
+	}
+	if redacted.Mongo.Password != "" {
+		t.Errorf("unset Mongo.Password = %q, want empty", redacted.Mongo.Password)
+	}
+	if redacted.Server.Tracing.OTLP.Headers["api-key"] != RedactedValue {
+		t.Errorf("OTLP header not redacted: %v", redacted.Server.Tracing.OTLP.Headers)
+	}
+	if cfg.DB.Password != "db-secret" || cfg.Server.Tracing.OTLP.Headers["api-key"] != "otlp-secret" {
+		t.Error("Redacted modified the original configuration")
+	}
+
+	m, err := redacted.ToMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, ok := m["database"].(map[string]interface{})
+	if !ok || db["password"] != RedactedValue {
+		t.Errorf("ToMap()[database] = %v", m["database"])
+	}
+}
@@ -0,0 +1,203 @@
+// Package health aggregates dependency health checks for liveness, readiness and detailed
+// health reporting. Infrastructure components implement Checker and are registered on a
+// Registry, which runs the checks concurrently and caches their results.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Checker is implemented by components whose health can be checked, e.g. database clients.
+type Checker interface {
+	// HealthCheck returns nil if the component is healthy.
+	HealthCheck(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// HealthCheck calls f(ctx).
+func (f CheckerFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Status is the health status of a dependency or of the whole service.
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+	// StatusDegraded reports a service whose critical dependencies are up but some other is down.
+	StatusDegraded Status = "degraded"
+)
+
+// Result is the outcome of one dependency check.
+type Result struct {
+	Status Status `json:"status"`
+	// Critical dependencies make the service unready when down.
+	Critical  bool      `json:"critical"`
+	LatencyMS float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the aggregated health of the service.
+type Report struct {
+	Status Status `json:"status"`
+	// Draining is set once shutdown has begun; the service is then no longer ready.
+	Draining bool              `json:"draining,omitempty"`
+	Checks   map[string]Result `json:"checks"`
+}
+
+// Ready reports whether all critical dependencies are up and the service is not shutting down.
+func (r Report) Ready() bool {
+	return r.Status != StatusDown && !r.Draining
+}
+
+type registration struct {
+	name     string
+	checker  Checker
+	critical bool
+
+	mu     sync.Mutex
+	result Result
+	valid  bool
+}
+
+// Registry holds the registered checkers.
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	draining atomic.Bool
+
+	mu     sync.RWMutex
+	checks []*registration
+}
+
+// NewRegistry creates a registry. Each check is bounded by timeout, and its result is reused
+// for cacheTTL so that frequent probes do not load the dependencies.
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	return &Registry{timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// CacheTTL returns how long check results are reused.
+func (r *Registry) CacheTTL() time.Duration {
+	return r.cacheTTL
+}
+
+// Drain marks the service as shutting down: from now on reports are not ready, so that load
+// balancers stop routing new requests while in-flight ones complete. Liveness is unaffected.
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// Draining reports whether Drain has been called.
+func (r *Registry) Draining() bool {
+	return r.draining.Load()
+}
+
+// Register adds a checker under a unique name. Critical dependencies make the service unready when down.
+func (r *Registry) Register(name string, checker Checker, critical bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, &registration{name: name, checker: checker, critical: critical})
+}
+
+// Names returns the names of the registered checkers in registration order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.checks))
+	for i, reg := range r.checks {
+		names[i] = reg.name
+	}
+	return names
+}
+
+// Check runs all checks concurrently, reusing cached results, and aggregates them.
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := append([]*registration(nil), r.checks...)
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, reg := range checks {
+		wg.Add(1)
+		go func(i int, reg *registration) {
+			defer wg.Done()
+			results[i] = r.run(ctx, reg)
+		}(i, reg)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Draining: r.Draining(), Checks: make(map[string]Result, len(checks))}
+	for i, reg := range checks {
+		report.Checks[reg.name] = results[i]
+		if results[i].Status == StatusUp {
+			continue
+		}
+		if reg.critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// CheckOne runs the named check. ok is false if no checker is registered under name.
+func (r *Registry) CheckOne(ctx context.Context, name string) (Result, bool) {
+	r.mu.RLock()
+	var found *registration
+	for _, reg := range r.checks {
+		if reg.name == name {
+			found = reg
+			break
+		}
+	}
+	r.mu.RUnlock()
+	if found == nil {
+		return Result{}, false
+	}
+	return r.run(ctx, found), true
+}
+
+// run returns the cached result of a check or runs it. Concurrent callers share one run.
+func (r *Registry) run(ctx context.Context, reg *registration) Result {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if reg.valid && time.Since(reg.result.CheckedAt) < r.cacheTTL {
+		return reg.result
+	}
+
+	checkCtx := ctx
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		checkCtx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := reg.checker.HealthCheck(checkCtx)
+	result := Result{
+		Status:    StatusUp,
+		Critical:  reg.critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	// A check cut short by the caller says nothing about the dependency
+	if ctx.Err() == nil {
+		reg.result, reg.valid = result, true
+	}
+	return result
+}
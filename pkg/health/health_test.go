@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type countingChecker struct {
+	calls int
+	err   error
+}
+
+func (c *countingChecker) HealthCheck(ctx context.Context) error {
+	c.calls++
+	return c.err
+}
+
+func TestRegistry_Status(t *testing.T) {
+	db := &countingChecker{}
+	email := &countingChecker{err: errors.New("connection refused")}
+
+	registry := NewRegistry(time.Second, 0)
+	registry.Register("database", db, true)
+	registry.Register("email", email, false)
+
+	report := registry.Check(context.Background())
+	if report.Status != StatusDegraded || !report.Ready() {
+		t.Errorf("status = %s, want degraded and ready", report.Status)
+	}
+	if got := report.Checks["email"]; got.Status != StatusDown || got.Error != "connection refused" || got.Critical {
+		t.Errorf("email = %+v", got)
+	}
+
+	db.err = errors.New("timeout")
+	if report := registry.Check(context.Background()); report.Status != StatusDown || report.Ready() {
+		t.Errorf("status = %s, want down and not ready", report.Status)
+	}
+
+	db.err = nil
+	registry.Drain()
+	if report := registry.Check(context.Background()); !report.Draining || report.Ready() {
+		t.Errorf("report = %+v, want draining and not ready", report)
+	}
+}
+
+func TestRegistry_CachesResults(t *testing.T) {
+	db := &countingChecker{}
+	registry := NewRegistry(time.Second, time.Minute)
+	registry.Register("database", db, true)
+
+	registry.Check(context.Background())
+	registry.Check(context.Background())
+	if _, ok := registry.CheckOne(context.Background(), "database"); !ok {
+		t.Fatal("database check not found")
+	}
+	if db.calls != 1 {
+		t.Errorf("checker called %d times, want 1", db.calls)
+	}
+	if _, ok := registry.CheckOne(context.Background(), "unknown"); ok {
+		t.Error("unknown check found")
+	}
+}
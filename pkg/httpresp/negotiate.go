@@ -0,0 +1,136 @@
+package httpresp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Media types understood by the content negotiation.
+const (
+	MIMEJSON      = binding.MIMEJSON
+	MIMEMsgPack   = binding.MIMEMSGPACK2
+	MIMEXMsgPack  = binding.MIMEMSGPACK
+	MIMEProtobuf  = "application/protobuf"
+	MIMEXProtobuf = binding.MIMEPROTOBUF
+)
+
+// Format is a negotiated response representation.
+type Format int
+
+const (
+	FormatJSON Format = iota
+	FormatMsgPack
+	FormatProtobuf
+)
+
+var offers = []string{MIMEJSON, MIMEMsgPack, MIMEXMsgPack, MIMEProtobuf, MIMEXProtobuf}
+
+// Negotiate picks the response format from the Accept header. JSON is the default.
+func Negotiate(c *gin.Context) Format {
+	if c.Request == nil {
+		return FormatJSON
+	}
+	return FormatOf(c.NegotiateFormat(offers...))
+}
+
+// FormatOf maps a media type (without parameters) to its Format. Unknown types map to JSON.
+func FormatOf(mediaType string) Format {
+	switch mediaType {
+	case MIMEMsgPack, MIMEXMsgPack:
+		return FormatMsgPack
+	case MIMEProtobuf, MIMEXProtobuf:
+		return FormatProtobuf
+	default:
+		return FormatJSON
+	}
+}
+
+// Render writes obj in the format negotiated from the Accept header.
+// Protobuf is only used when ProtoOf can map obj; otherwise the response falls back to JSON.
+func Render(c *gin.Context, status int, obj interface{}) {
+	switch Negotiate(c) {
+	case FormatMsgPack:
+		c.Render(status, render.MsgPack{Data: obj})
+		return
+	case FormatProtobuf:
+		if msg, ok := ProtoOf(obj); ok {
+			c.ProtoBuf(status, msg)
+			return
+		}
+	}
+	c.JSON(status, obj)
+}
+
+// protoTypes maps Go types to the protobuf message representing them.
+var protoTypes sync.Map // reflect.Type -> proto.Message
+
+// RegisterProto declares prototype as the protobuf representation of T, so that
+// responses carrying a T and protobuf request bodies bound into a T can reuse existing
+// generated messages. Fields are mapped by name through their JSON form: the json tags
+// of T must match the proto field names (e.g. created_at).
+func RegisterProto[T any](prototype proto.Message) {
+	protoTypes.Store(reflect.TypeOf((*T)(nil)).Elem(), prototype)
+}
+
+func protoFor(v interface{}) (proto.Message, bool) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, false
+	}
+	prototype, ok := protoTypes.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(prototype.(proto.Message)), true
+}
+
+// ProtoOf returns the protobuf message for v: v itself when it is a proto.Message,
+// or the message registered for its type via RegisterProto.
+func ProtoOf(v interface{}) (proto.Message, bool) {
+	if msg, ok := v.(proto.Message); ok {
+		return msg, true
+	}
+	msg, ok := protoFor(v)
+	if !ok {
+		return nil, false
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, false
+	}
+	return msg, true
+}
+
+// DecodeProto decodes a protobuf request body into dest, which must be a proto.Message
+// or a pointer to a type registered with RegisterProto.
+func DecodeProto(body []byte, dest interface{}) error {
+	if msg, ok := dest.(proto.Message); ok {
+		return proto.Unmarshal(body, msg)
+	}
+	msg, ok := protoFor(dest)
+	if !ok {
+		return fmt.Errorf("no protobuf message registered for %T", dest)
+	}
+	if err := proto.Unmarshal(body, msg); err != nil {
+		return err
+	}
+	data, err := (protojson.MarshalOptions{UseProtoNames: true}).Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
@@ -0,0 +1,95 @@
+package httpresp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+type method struct {
+	Name             string `json:"name"`
+	RequestStreaming bool   `json:"request_streaming"`
+}
+
+func init() {
+	RegisterProto[method](&apipb.Method{})
+}
+
+func respond(accept string, data interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+	Respond(c, http.StatusOK, "ok", data)
+	return w
+}
+
+func TestRespond_DefaultsToJSON(t *testing.T) {
+	w := respond("", method{Name: "Get"})
+
+	var body Response
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if body.Message != "ok" {
+		t.Errorf("message = %q, want ok", body.Message)
+	}
+}
+
+func TestRespond_MsgPack(t *testing.T) {
+	w := respond(MIMEMsgPack, method{Name: "Get"})
+
+	var body map[string]interface{}
+	handle := &codec.MsgpackHandle{}
+	handle.RawToString = true
+	if err := codec.NewDecoderBytes(w.Body.Bytes(), handle).Decode(&body); err != nil {
+		t.Fatalf("body is not MessagePack: %v", err)
+	}
+	if body["message"] != "ok" {
+		t.Errorf("message = %v, want ok", body["message"])
+	}
+}
+
+func TestRespond_ProtobufUsesRegisteredMessage(t *testing.T) {
+	w := respond(MIMEXProtobuf, &method{Name: "Get", RequestStreaming: true})
+
+	var msg apipb.Method
+	if err := proto.Unmarshal(w.Body.Bytes(), &msg); err != nil {
+		t.Fatalf("body is not protobuf: %v", err)
+	}
+	if msg.GetName() != "Get" || !msg.GetRequestStreaming() {
+		t.Errorf("message = %v, want name Get with request streaming", &msg)
+	}
+}
+
+func TestRespond_ProtobufFallsBackToJSON(t *testing.T) {
+	w := respond(MIMEXProtobuf, gin.H{"unregistered": true})
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("content type = %q, want JSON", ct)
+	}
+}
+
+func TestDecodeProto(t *testing.T) {
+	body, err := proto.Marshal(&apipb.Method{Name: "List", RequestStreaming: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dest method
+	if err := DecodeProto(body, &dest); err != nil {
+		t.Fatalf("DecodeProto: %v", err)
+	}
+	if dest != (method{Name: "List", RequestStreaming: true}) {
+		t.Errorf("decoded = %+v", dest)
+	}
+}
@@ -13,7 +13,24 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`    // Response payload (optional)
 }
 
-// JSON sends a JSON response with a standard structure.
+// Respond sends a response with the standard structure in the format negotiated from
+// the Accept header: JSON (default), MessagePack, or protobuf. Protobuf responses carry
+// only the data message (see RegisterProto) and fall back to JSON when data has none.
+func Respond(c *gin.Context, status int, message string, data interface{}) {
+	if Negotiate(c) == FormatProtobuf {
+		if msg, ok := ProtoOf(data); ok {
+			c.ProtoBuf(status, msg)
+			return
+		}
+	}
+	Render(c, status, Response{
+		Status:  http.StatusText(status),
+		Message: message,
+		Data:    data,
+	})
+}
+
+// JSON sends a JSON response with a standard structure, regardless of the Accept header.
 // It automatically sets the HTTP status text based on the status code.
 func JSON(c *gin.Context, status int, message string, data interface{}) {
 	c.JSON(status, Response{
@@ -25,35 +42,35 @@ func JSON(c *gin.Context, status int, message string, data interface{}) {
 
 // Success sends a 200 OK response with the given data
 func Success(c *gin.Context, message string, data interface{}) {
-	JSON(c, http.StatusOK, message, data)
+	Respond(c, http.StatusOK, message, data)
 }
 
 // Created sends a 201 Created response with the given data
 func Created(c *gin.Context, message string, data interface{}) {
-	JSON(c, http.StatusCreated, message, data)
+	Respond(c, http.StatusCreated, message, data)
 }
 
 // BadRequest sends a 400 Bad Request response
 func BadRequest(c *gin.Context, message string) {
-	JSON(c, http.StatusBadRequest, message, nil)
+	Respond(c, http.StatusBadRequest, message, nil)
 }
 
 // Unauthorized sends a 401 Unauthorized response
 func Unauthorized(c *gin.Context, message string) {
-	JSON(c, http.StatusUnauthorized, message, nil)
+	Respond(c, http.StatusUnauthorized, message, nil)
 }
 
 // Forbidden sends a 403 Forbidden response
 func Forbidden(c *gin.Context, message string) {
-	JSON(c, http.StatusForbidden, message, nil)
+	Respond(c, http.StatusForbidden, message, nil)
 }
 
 // NotFound sends a 404 Not Found response
 func NotFound(c *gin.Context, message string) {
-	JSON(c, http.StatusNotFound, message, nil)
+	Respond(c, http.StatusNotFound, message, nil)
 }
 
 // InternalServerError sends a 500 Internal Server Error response
 func InternalServerError(c *gin.Context, message string) {
-	JSON(c, http.StatusInternalServerError, message, nil)
+	Respond(c, http.StatusInternalServerError, message, nil)
 }
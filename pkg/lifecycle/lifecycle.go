@@ -0,0 +1,106 @@
+// Package lifecycle starts and stops application components in order. Components register
+// hooks as they are created; servers are started after the infrastructure they depend on
+// and everything is stopped in reverse registration order.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Hook is a component's start and stop callbacks. Either may be nil.
+type Hook struct {
+	Name string
+	// OnStart starts the component, e.g. binds a listener. It must not block.
+	OnStart func(ctx context.Context) error
+	// OnStop releases the component. It should return once ctx is done.
+	OnStop func(ctx context.Context) error
+}
+
+// CloseHook returns a hook that closes c on stop, e.g. a database client.
+func CloseHook(name string, c io.Closer) Hook {
+	return Hook{Name: name, OnStop: func(context.Context) error { return c.Close() }}
+}
+
+type entry struct {
+	Hook
+	running bool
+}
+
+// Manager runs the registered hooks.
+type Manager struct {
+	log *logrus.Logger
+
+	mu      sync.Mutex
+	entries []*entry
+}
+
+// NewManager creates an empty manager.
+func NewManager(log *logrus.Logger) *Manager {
+	return &Manager{log: log}
+}
+
+// Append registers a hook. A hook without OnStart is considered running as soon as it is
+// registered, so that components created before a startup failure are still released.
+func (m *Manager) Append(h Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, &entry{Hook: h, running: h.OnStart == nil})
+}
+
+// Start runs the OnStart hooks in registration order. If one fails, the running components
+// are stopped and the error is returned.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	entries := append([]*entry(nil), m.entries...)
+	m.mu.Unlock()
+
+	for _, e := range entries {
+		if e.running {
+			continue
+		}
+		if err := e.OnStart(ctx); err != nil {
+			err = fmt.Errorf("failed to start %s: %w", e.Name, err)
+			if stopErr := m.Stop(ctx); stopErr != nil {
+				m.log.WithError(stopErr).Warn("Failed to stop components after startup failure")
+			}
+			return err
+		}
+		m.mu.Lock()
+		e.running = true
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// Stop runs the OnStop hooks of the running components in reverse registration order. Every
+// hook is called even when ctx is done, so that infrastructure is released after a server
+// failed to stop in time. The errors of all hooks are joined.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	var running []*entry
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if e := m.entries[i]; e.running {
+			e.running = false
+			running = append(running, e)
+		}
+	}
+	m.mu.Unlock()
+
+	var errs []error
+	for _, e := range running {
+		if e.OnStop == nil {
+			continue
+		}
+		m.log.Infof("Stopping %s", e.Name)
+		if err := e.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", e.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
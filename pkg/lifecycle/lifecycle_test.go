@@ -0,0 +1,75 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func recordingHook(name string, calls *[]string, startErr error) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			*calls = append(*calls, "start "+name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			*calls = append(*calls, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestManager_StopsInReverseOrder(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	m := NewManager(log)
+
+	var calls []string
+	m.Append(Hook{Name: "database", OnStop: func(context.Context) error {
+		calls = append(calls, "stop database")
+		return errors.New("close failed")
+	}})
+	m.Append(recordingHook("http", &calls, nil))
+	m.Append(recordingHook("grpc", &calls, nil))
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	err := m.Stop(context.Background())
+	if err == nil || err.Error() != "failed to stop database: close failed" {
+		t.Errorf("Stop() error = %v", err)
+	}
+
+	want := []string{"start http", "start grpc", "stop grpc", "stop http", "stop database"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	// Stopping again is a no-op
+	calls = nil
+	if err := m.Stop(context.Background()); err != nil || len(calls) != 0 {
+		t.Errorf("second Stop() = %v, calls = %v", err, calls)
+	}
+}
+
+func TestManager_StartFailureStopsRunning(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	m := NewManager(log)
+
+	var calls []string
+	m.Append(recordingHook("cache", &calls, nil))
+	m.Append(recordingHook("http", &calls, errors.New("address in use")))
+	m.Append(recordingHook("grpc", &calls, nil))
+
+	err := m.Start(context.Background())
+	if err == nil || err.Error() != "failed to start http: address in use" {
+		t.Errorf("Start() error = %v", err)
+	}
+	want := []string{"start cache", "start http", "stop cache"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
@@ -0,0 +1,82 @@
+package listquery
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// cursor is the payload of an opaque cursor: the sort it was issued for and the sort values
+// of the last item of a page.
+type cursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// sortSignature identifies a sort so that a cursor cannot be replayed against another one.
+func sortSignature(sorts []Sort) string {
+	terms := make([]string, len(sorts))
+	for i, s := range sorts {
+		terms[i] = s.Field.Name
+		if s.Desc {
+			terms[i] = "-" + terms[i]
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+// EncodeCursor returns the opaque cursor pointing after an item with the given sort values.
+func EncodeCursor(sorts []Sort, values []interface{}) string {
+	encoded := make([]interface{}, len(values))
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			v = t.UTC().Format(time.RFC3339Nano)
+		}
+		encoded[i] = v
+	}
+	data, _ := json.Marshal(cursor{Sort: sortSignature(sorts), Values: encoded})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor validates a cursor against the query's sort and returns its typed values.
+func decodeCursor(raw string, sorts []Sort) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, invalid("cursor", "malformed cursor")
+	}
+	var c cursor
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return nil, invalid("cursor", "malformed cursor")
+	}
+	if c.Sort != sortSignature(sorts) || len(c.Values) != len(sorts) {
+		return nil, invalid("cursor", "cursor does not match the requested sort")
+	}
+
+	values := make([]interface{}, len(sorts))
+	for i, s := range sorts {
+		v, err := cursorValue(s.Field.Type, c.Values[i])
+		if err != nil {
+			return nil, invalid("cursor", "malformed cursor")
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func cursorValue(typ FieldType, v interface{}) (interface{}, error) {
+	switch raw := v.(type) {
+	case json.Number:
+		return parseValue(typ, raw.String())
+	case string:
+		return parseValue(typ, raw)
+	case bool:
+		if typ == Bool {
+			return raw, nil
+		}
+	}
+	return nil, invalid("cursor", "unexpected value")
+}
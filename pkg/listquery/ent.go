@@ -0,0 +1,101 @@
+package listquery
+
+import (
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// EntWhere returns a selector modifier applying the query's filters and, in cursor mode, the
+// keyset condition. Wrap it in the generated predicate type, e.g. predicate.User(EntWhere(q)).
+func EntWhere(q *Query) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		preds := entFilters(s, q.Filters)
+		if len(q.After) == len(q.Sorts) && len(q.After) > 0 {
+			preds = append(preds, entKeyset(s, q.Sorts, q.After))
+		}
+		if len(preds) > 0 {
+			s.Where(sql.And(preds...))
+		}
+	}
+}
+
+// EntFilter returns a selector modifier applying only the query's filters, for counting the
+// total number of matching items.
+func EntFilter(q *Query) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		if preds := entFilters(s, q.Filters); len(preds) > 0 {
+			s.Where(sql.And(preds...))
+		}
+	}
+}
+
+// EntOrder returns a selector modifier applying the query's sort. Wrap it in the generated
+// order type, e.g. user.OrderOption(EntOrder(q)).
+func EntOrder(q *Query) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		for _, sort := range q.Sorts {
+			if sort.Desc {
+				s.OrderBy(sql.Desc(s.C(sort.Field.Key())))
+			} else {
+				s.OrderBy(sql.Asc(s.C(sort.Field.Key())))
+			}
+		}
+	}
+}
+
+func entFilters(s *sql.Selector, filters []Filter) []*sql.Predicate {
+	preds := make([]*sql.Predicate, 0, len(filters))
+	for _, f := range filters {
+		col := s.C(f.Field.Key())
+		switch f.Op {
+		case OpEq:
+			preds = append(preds, sql.EQ(col, f.Value()))
+		case OpNe:
+			preds = append(preds, sql.NEQ(col, f.Value()))
+		case OpGt:
+			preds = append(preds, sql.GT(col, f.Value()))
+		case OpGte:
+			preds = append(preds, sql.GTE(col, f.Value()))
+		case OpLt:
+			preds = append(preds, sql.LT(col, f.Value()))
+		case OpLte:
+			preds = append(preds, sql.LTE(col, f.Value()))
+		case OpIn:
+			preds = append(preds, sql.In(col, f.Values...))
+		case OpNin:
+			preds = append(preds, sql.NotIn(col, f.Values...))
+		case OpContains:
+			preds = append(preds, sql.Contains(col, fmt.Sprint(f.Value())))
+		case OpPrefix:
+			preds = append(preds, sql.HasPrefix(col, fmt.Sprint(f.Value())))
+		case OpNull:
+			if isNull, _ := f.Value().(bool); isNull {
+				preds = append(preds, sql.IsNull(col))
+			} else {
+				preds = append(preds, sql.NotNull(col))
+			}
+		}
+	}
+	return preds
+}
+
+// entKeyset builds (a > x) OR (a = x AND b > y) OR ... for the sort values of the last item,
+// using < for descending terms.
+func entKeyset(s *sql.Selector, sorts []Sort, after []interface{}) *sql.Predicate {
+	ors := make([]*sql.Predicate, 0, len(sorts))
+	for i, sort := range sorts {
+		ands := make([]*sql.Predicate, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, sql.EQ(s.C(sorts[j].Field.Key()), after[j]))
+		}
+		col := s.C(sort.Field.Key())
+		if sort.Desc {
+			ands = append(ands, sql.LT(col, after[i]))
+		} else {
+			ands = append(ands, sql.GT(col, after[i]))
+		}
+		ors = append(ors, sql.And(ands...))
+	}
+	return sql.Or(ors...)
+}
@@ -0,0 +1,130 @@
+package listquery
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ListFilterInput is the GraphQL input of one filter condition; op defaults to "eq".
+var ListFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ListFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"field": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"op":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"value": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+// PageInfoType is the Relay PageInfo object shared by all connections.
+var PageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"hasNextPage":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"startCursor":     &graphql.Field{Type: graphql.String},
+		"endCursor":       &graphql.Field{Type: graphql.String},
+	},
+})
+
+// ConnectionArgs returns the arguments of a Relay connection field: first, after, and the
+// sort and filter of the list, using the same syntax as the REST query parameters.
+func ConnectionArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"first":  &graphql.ArgumentConfig{Type: graphql.Int},
+		"after":  &graphql.ArgumentConfig{Type: graphql.String},
+		"sort":   &graphql.ArgumentConfig{Type: graphql.String},
+		"filter": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(ListFilterInput))},
+	}
+}
+
+// ConnectionType returns the <Node>Connection object type for a node type. Call it once per
+// node type: GraphQL type names must be unique within a schema.
+func ConnectionType(node *graphql.Object) *graphql.Object {
+	edge := graphql.NewObject(graphql.ObjectConfig{
+		Name: node.Name() + "Edge",
+		Fields: graphql.Fields{
+			"node":   &graphql.Field{Type: node},
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: node.Name() + "Connection",
+		Fields: graphql.Fields{
+			"edges":      &graphql.Field{Type: graphql.NewList(edge)},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(PageInfoType)},
+			"totalCount": &graphql.Field{Type: graphql.Int},
+		},
+	})
+}
+
+// FromGraphQL builds a Query from the arguments declared by ConnectionArgs.
+func FromGraphQL(schema *Schema, args map[string]interface{}) (*Query, error) {
+	values := url.Values{}
+	if first, ok := args["first"].(int); ok {
+		values.Set("limit", strconv.Itoa(first))
+	}
+	if after, ok := args["after"].(string); ok && after != "" {
+		values.Set("cursor", after)
+	}
+	if sort, ok := args["sort"].(string); ok && sort != "" {
+		values.Set("sort", sort)
+	}
+	filters, _ := args["filter"].([]interface{})
+	for _, raw := range filters {
+		filter, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field, _ := filter["field"].(string)
+		value, _ := filter["value"].(string)
+		op, _ := filter["op"].(string)
+		if op == "" {
+			op = string(OpEq)
+		}
+		values.Add("filter["+field+"]["+op+"]", value)
+	}
+	return Parse(schema, values)
+}
+
+// Connection is the resolved value of a ConnectionType field.
+type Connection struct {
+	Edges      []Edge        `json:"edges"`
+	PageInfo   RelayPageInfo `json:"pageInfo"`
+	TotalCount *int64        `json:"totalCount,omitempty"`
+}
+
+// Edge is an item of a Connection with its cursor.
+type Edge struct {
+	Node   interface{} `json:"node"`
+	Cursor string      `json:"cursor"`
+}
+
+// RelayPageInfo is the resolved value of PageInfoType.
+type RelayPageInfo struct {
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor,omitempty"`
+	EndCursor       string `json:"endCursor,omitempty"`
+}
+
+// ToConnection converts a page to a Relay connection.
+func ToConnection[T any](page *Page[T]) *Connection {
+	conn := &Connection{
+		Edges: make([]Edge, len(page.Items)),
+		PageInfo: RelayPageInfo{
+			HasNextPage:     page.PageInfo.HasNext,
+			HasPreviousPage: page.PageInfo.HasPrevious,
+		},
+		TotalCount: page.PageInfo.TotalItems,
+	}
+	for i, item := range page.Items {
+		conn.Edges[i] = Edge{Node: item, Cursor: page.Cursor(i)}
+	}
+	if n := len(conn.Edges); n > 0 {
+		conn.PageInfo.StartCursor = conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = conn.Edges[n-1].Cursor
+	}
+	return conn
+}
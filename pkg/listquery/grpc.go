@@ -0,0 +1,102 @@
+package listquery
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// grpcOps maps the comparison operators of a page-token filter expression to Ops.
+// Longer operators come first so that ">=" is not read as ">" at the same position.
+var grpcOps = []struct {
+	token string
+	op    Op
+}{
+	{"!=", OpNe},
+	{">=", OpGte},
+	{"<=", OpLte},
+	{"=", OpEq},
+	{">", OpGt},
+	{"<", OpLt},
+	{":", OpContains},
+}
+
+// FromPageToken builds a Query from the page_size, page_token, order_by and filter fields of
+// an AIP-158 style gRPC list request. order_by is "field [desc], ..." and filter is a list of
+// "field op value" comparisons joined by AND, with op one of = != > >= < <= and : (contains).
+// The next page_token of a response is the page's PageInfo.NextCursor.
+func FromPageToken(schema *Schema, pageSize int32, pageToken, orderBy, filter string) (*Query, error) {
+	values := url.Values{}
+	if pageSize > 0 {
+		values.Set("limit", strconv.Itoa(int(pageSize)))
+	}
+	if pageToken != "" {
+		values.Set("cursor", pageToken)
+	}
+
+	var sorts []string
+	for _, term := range strings.Split(orderBy, ",") {
+		fields := strings.Fields(term)
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) == 2 && strings.EqualFold(fields[1], "desc"):
+			sorts = append(sorts, "-"+fields[0])
+		case len(fields) == 1 || (len(fields) == 2 && strings.EqualFold(fields[1], "asc")):
+			sorts = append(sorts, fields[0])
+		default:
+			return nil, invalid("order_by", "malformed term %q", strings.TrimSpace(term))
+		}
+	}
+	if len(sorts) > 0 {
+		values.Set("sort", strings.Join(sorts, ","))
+	}
+
+	if strings.TrimSpace(filter) != "" {
+		for _, expr := range splitAnd(filter) {
+			name, op, value, ok := parseComparison(expr)
+			if !ok {
+				return nil, invalid("filter", "malformed expression %q", expr)
+			}
+			values.Add("filter["+name+"]["+string(op)+"]", value)
+		}
+	}
+	return Parse(schema, values)
+}
+
+// splitAnd splits a filter expression on the AND keyword.
+func splitAnd(filter string) []string {
+	var exprs []string
+	var current []string
+	for _, word := range strings.Fields(filter) {
+		if word == "AND" {
+			exprs = append(exprs, strings.Join(current, " "))
+			current = nil
+			continue
+		}
+		current = append(current, word)
+	}
+	return append(exprs, strings.Join(current, " "))
+}
+
+// parseComparison splits "field op value" on the first operator of the expression.
+func parseComparison(expr string) (string, Op, string, bool) {
+	at, token, op := -1, "", Op("")
+	for _, candidate := range grpcOps {
+		if i := strings.Index(expr, candidate.token); i >= 0 && (at < 0 || i < at) {
+			at, token, op = i, candidate.token, candidate.op
+		}
+	}
+	if at < 0 {
+		return "", "", "", false
+	}
+
+	name, value := strings.TrimSpace(expr[:at]), strings.TrimSpace(expr[at+len(token):])
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	if name == "" || value == "" {
+		return "", "", "", false
+	}
+	return name, op, value, true
+}
@@ -0,0 +1,202 @@
+// Package listquery parses list requests (pagination, sorting and filtering) into a typed
+// Query checked against an allow-list of fields, and translates it for the storage and
+// transport layers: ent predicates, Mongo filters, paged REST envelopes with opaque cursors,
+// gRPC page tokens and GraphQL Relay connections.
+//
+// REST query syntax:
+//
+//	?page=2&limit=20                  offset pagination (page is 1-based)
+//	?cursor=<next_cursor>&limit=20    keyset pagination
+//	?sort=-created_at,username        "-" sorts descending
+//	?filter[role]=admin               equality
+//	?filter[created_at][gte]=2024-01-01T00:00:00Z
+//	?filter[role][in]=admin,user
+package listquery
+
+import (
+	"fmt"
+	"time"
+)
+
+// Op is a filter operator.
+type Op string
+
+// Supported filter operators.
+const (
+	OpEq       Op = "eq"
+	OpNe       Op = "ne"
+	OpGt       Op = "gt"
+	OpGte      Op = "gte"
+	OpLt       Op = "lt"
+	OpLte      Op = "lte"
+	OpIn       Op = "in"
+	OpNin      Op = "nin"
+	OpContains Op = "contains"
+	OpPrefix   Op = "prefix"
+	// OpNull filters on NULL (value "true") or NOT NULL (value "false").
+	OpNull Op = "null"
+)
+
+// FieldType is the type of a field's values.
+type FieldType int
+
+const (
+	String FieldType = iota
+	Int
+	Float
+	Bool
+	Time
+)
+
+// defaultOps are the operators allowed when Field.Ops is empty.
+var defaultOps = map[FieldType][]Op{
+	String: {OpEq, OpNe, OpIn, OpNin, OpContains, OpPrefix, OpNull},
+	Int:    {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNin, OpNull},
+	Float:  {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNin, OpNull},
+	Bool:   {OpEq, OpNe, OpNull},
+	Time:   {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpNull},
+}
+
+// Field is an allow-listed field of a Schema.
+type Field struct {
+	// Name is the field name used by clients.
+	Name string
+	// Column is the SQL column or Mongo key. Defaults to Name.
+	Column string
+	Type   FieldType
+	// Sortable allows the field in sort expressions. Sort fields should be non-null.
+	Sortable bool
+	// Filterable allows the field in filters.
+	Filterable bool
+	// Ops restricts the filter operators; empty allows the defaults of the field type.
+	Ops []Op
+}
+
+// Key returns the SQL column or Mongo key of the field.
+func (f Field) Key() string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return f.Name
+}
+
+func (f Field) allows(op Op) bool {
+	ops := f.Ops
+	if len(ops) == 0 {
+		ops = defaultOps[f.Type]
+	}
+	for _, allowed := range ops {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema is the allow-list and defaults of a list endpoint.
+type Schema struct {
+	Fields []Field
+	// DefaultSort is used when the request has no sort, e.g. "-created_at".
+	DefaultSort string
+	// Tiebreaker is a unique, sortable field appended to every sort so that cursors are stable.
+	Tiebreaker string
+	// DefaultLimit and MaxLimit bound the page size (defaults 20 and 100).
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// Field returns the allow-listed field with the given name.
+func (s *Schema) Field(name string) (Field, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+func (s *Schema) limits() (int, int) {
+	def, max := s.DefaultLimit, s.MaxLimit
+	if max <= 0 {
+		max = 100
+	}
+	if def <= 0 || def > max {
+		def = min(20, max)
+	}
+	return def, max
+}
+
+// Filter is a parsed filter condition. Values hold typed values (string, int64, float64,
+// bool or time.Time); OpIn and OpNin may have several, OpNull holds one bool.
+type Filter struct {
+	Field  Field
+	Op     Op
+	Values []interface{}
+}
+
+// Value returns the first value of the filter.
+func (f Filter) Value() interface{} {
+	if len(f.Values) == 0 {
+		return nil
+	}
+	return f.Values[0]
+}
+
+// Sort is a parsed sort term.
+type Sort struct {
+	Field Field
+	Desc  bool
+}
+
+// Query is a parsed, validated list request.
+type Query struct {
+	// Limit is the page size.
+	Limit int
+	// Page is the 1-based page in offset mode, and 0 in cursor mode.
+	Page int
+	// After holds the sort values of the last item of the previous page in cursor mode.
+	After []interface{}
+	// Sorts always ends with the schema's tiebreaker.
+	Sorts   []Sort
+	Filters []Filter
+}
+
+// CursorMode reports whether the query paginates by cursor instead of by page.
+func (q *Query) CursorMode() bool {
+	return q.Page == 0
+}
+
+// Offset returns the number of rows to skip.
+func (q *Query) Offset() int {
+	if q.Page <= 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Limit
+}
+
+// FetchLimit returns the number of rows to fetch: one more than Limit to detect a next page.
+func (q *Query) FetchLimit() int {
+	return q.Limit + 1
+}
+
+// Error is returned for list requests that violate the schema. Its message is safe to show to clients.
+type Error struct {
+	Param   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Param, e.Message)
+}
+
+func invalid(param, format string, args ...interface{}) error {
+	return &Error{Param: param, Message: fmt.Sprintf(format, args...)}
+}
+
+// normalize converts typed values to the representation used in cursors and comparisons.
+func normalize(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC()
+	}
+	return v
+}
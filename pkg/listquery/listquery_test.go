@@ -0,0 +1,186 @@
+package listquery
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var testSchema = &Schema{
+	Fields: []Field{
+		{Name: "id", Type: Int, Sortable: true, Filterable: true},
+		{Name: "name", Type: String, Sortable: true, Filterable: true},
+		{Name: "created_at", Type: Time, Sortable: true, Filterable: true},
+		{Name: "secret", Type: String},
+	},
+	DefaultSort:  "-created_at",
+	Tiebreaker:   "id",
+	DefaultLimit: 2,
+	MaxLimit:     10,
+}
+
+type item struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+}
+
+func itemKey(it item, field string) interface{} {
+	switch field {
+	case "id":
+		return it.ID
+	case "name":
+		return it.Name
+	case "created_at":
+		return it.CreatedAt
+	}
+	return nil
+}
+
+func mustParse(t *testing.T, query string) *Query {
+	t.Helper()
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := Parse(testSchema, values)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", query, err)
+	}
+	return q
+}
+
+func TestParse(t *testing.T) {
+	q := mustParse(t, "page=3&limit=50&sort=name,-created_at&filter[name][in]=a,b&filter[created_at][gte]=2024-01-01T00:00:00Z")
+
+	if q.Page != 3 || q.Limit != 10 || q.Offset() != 20 {
+		t.Errorf("page = %d limit = %d offset = %d, want 3 10 20", q.Page, q.Limit, q.Offset())
+	}
+	if got := sortSignature(q.Sorts); got != "name,-created_at,-id" {
+		t.Errorf("sort = %q, want name,-created_at,-id", got)
+	}
+	if len(q.Filters) != 2 {
+		t.Fatalf("filters = %+v", q.Filters)
+	}
+	created := q.Filters[0]
+	if created.Op != OpGte || created.Value() != time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("created_at filter = %+v", created)
+	}
+	if name := q.Filters[1]; name.Op != OpIn || !reflect.DeepEqual(name.Values, []interface{}{"a", "b"}) {
+		t.Errorf("name filter = %+v", name)
+	}
+
+	if q := mustParse(t, ""); q.Page != 1 || q.Limit != 2 || sortSignature(q.Sorts) != "-created_at,-id" {
+		t.Errorf("defaults = page %d limit %d sort %q", q.Page, q.Limit, sortSignature(q.Sorts))
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	for _, query := range []string{
+		"sort=secret",
+		"filter[secret]=x",
+		"filter[id][contains]=1",
+		"filter[id]=abc",
+		"limit=0",
+		"page=1&cursor=abc",
+		"cursor=not-a-cursor",
+	} {
+		values, _ := url.ParseQuery(query)
+		_, err := Parse(testSchema, values)
+		var invalid *Error
+		if !errors.As(err, &invalid) {
+			t.Errorf("Parse(%q) error = %v, want *Error", query, err)
+		}
+	}
+}
+
+func TestPaginate_CursorRoundTrip(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := mustParse(t, "")
+	rows := []item{{3, "c", base.Add(2 * time.Hour)}, {2, "b", base.Add(time.Hour)}, {1, "a", base}}
+
+	page := Paginate(q, rows, itemKey).WithTotal(3)
+	if len(page.Items) != 2 || !page.PageInfo.HasNext || page.PageInfo.NextCursor == "" {
+		t.Fatalf("page = %+v", page.PageInfo)
+	}
+	if *page.PageInfo.TotalPages != 2 {
+		t.Errorf("total pages = %d, want 2", *page.PageInfo.TotalPages)
+	}
+
+	next := mustParse(t, "cursor="+page.PageInfo.NextCursor)
+	if !next.CursorMode() || !reflect.DeepEqual(next.After, []interface{}{base.Add(time.Hour), int64(2)}) {
+		t.Errorf("after = %#v", next.After)
+	}
+
+	// A cursor only applies to the sort it was issued for
+	values := url.Values{"cursor": {page.PageInfo.NextCursor}, "sort": {"name"}}
+	if _, err := Parse(testSchema, values); err == nil {
+		t.Error("cursor accepted for another sort")
+	}
+}
+
+func TestEntWhere(t *testing.T) {
+	q := mustParse(t, "filter[name][prefix]=jo")
+	q.After = []interface{}{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), int64(7)}
+
+	s := sql.Dialect(dialect.Postgres).Select("*").From(sql.Table("items"))
+	EntWhere(q)(s)
+	EntOrder(q)(s)
+	query, args := s.Query()
+
+	want := `SELECT * FROM "items" WHERE "items"."name" LIKE $1 AND ("items"."created_at" < $2 OR ("items"."created_at" = $3 AND "items"."id" < $4)) ORDER BY "items"."created_at" DESC, "items"."id" DESC`
+	if query != want {
+		t.Errorf("query =\n%s\nwant\n%s", query, want)
+	}
+	if len(args) != 4 || args[0] != "jo%" {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestMongoFilter(t *testing.T) {
+	q := mustParse(t, "sort=id&filter[name]=jo")
+	q.After = []interface{}{int64(7)}
+
+	got := MongoFilter(q)
+	want := bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "name", Value: "jo"}},
+		bson.D{{Key: "$or", Value: bson.A{bson.D{{Key: "id", Value: bson.M{"$gt": int64(7)}}}}}},
+	}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MongoFilter = %v, want %v", got, want)
+	}
+}
+
+func TestFromPageToken(t *testing.T) {
+	q, err := FromPageToken(testSchema, 5, "", "name desc, id", `name != "a b" AND created_at >= 2024-01-01T00:00:00Z`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Limit != 5 || sortSignature(q.Sorts) != "-name,id" {
+		t.Errorf("limit = %d sort = %q", q.Limit, sortSignature(q.Sorts))
+	}
+	if len(q.Filters) != 2 || q.Filters[0].Op != OpGte || q.Filters[1].Op != OpNe || q.Filters[1].Value() != "a b" {
+		t.Errorf("filters = %+v", q.Filters)
+	}
+
+	if _, err := FromPageToken(testSchema, 0, "", "", "name"); err == nil {
+		t.Error("malformed filter accepted")
+	}
+}
+
+func TestToConnection(t *testing.T) {
+	q, err := FromGraphQL(testSchema, map[string]interface{}{"first": 1, "sort": "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := ToConnection(Paginate(q, []item{{1, "a", time.Now()}, {2, "b", time.Now()}}, itemKey))
+	if len(conn.Edges) != 1 || !conn.PageInfo.HasNextPage || conn.PageInfo.EndCursor != conn.Edges[0].Cursor {
+		t.Errorf("connection = %+v", conn)
+	}
+}
@@ -0,0 +1,105 @@
+package listquery
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoFilter returns the query's filters and, in cursor mode, the keyset condition as a
+// Mongo filter document.
+func MongoFilter(q *Query) bson.D {
+	filter := mongoFilters(q.Filters)
+	if len(q.After) == len(q.Sorts) && len(q.After) > 0 {
+		filter = append(filter, mongoKeyset(q.Sorts, q.After))
+	}
+	if len(filter) == 0 {
+		return bson.D{}
+	}
+	return bson.D{{Key: "$and", Value: filter}}
+}
+
+// MongoCountFilter returns only the query's filters, for counting the total number of matching documents.
+func MongoCountFilter(q *Query) bson.D {
+	filter := mongoFilters(q.Filters)
+	if len(filter) == 0 {
+		return bson.D{}
+	}
+	return bson.D{{Key: "$and", Value: filter}}
+}
+
+// MongoFindOptions returns the sort, skip and limit of the query.
+func MongoFindOptions(q *Query) *options.FindOptions {
+	sort := make(bson.D, 0, len(q.Sorts))
+	for _, s := range q.Sorts {
+		dir := 1
+		if s.Desc {
+			dir = -1
+		}
+		sort = append(sort, bson.E{Key: s.Field.Key(), Value: dir})
+	}
+	opts := options.Find().SetSort(sort).SetLimit(int64(q.FetchLimit()))
+	if offset := q.Offset(); offset > 0 {
+		opts.SetSkip(int64(offset))
+	}
+	return opts
+}
+
+func mongoFilters(filters []Filter) bson.A {
+	conds := make(bson.A, 0, len(filters))
+	for _, f := range filters {
+		key := f.Field.Key()
+		var cond interface{}
+		switch f.Op {
+		case OpEq:
+			cond = f.Value()
+		case OpNe:
+			cond = bson.M{"$ne": f.Value()}
+		case OpGt:
+			cond = bson.M{"$gt": f.Value()}
+		case OpGte:
+			cond = bson.M{"$gte": f.Value()}
+		case OpLt:
+			cond = bson.M{"$lt": f.Value()}
+		case OpLte:
+			cond = bson.M{"$lte": f.Value()}
+		case OpIn:
+			cond = bson.M{"$in": bson.A(f.Values)}
+		case OpNin:
+			cond = bson.M{"$nin": bson.A(f.Values)}
+		case OpContains:
+			cond = bson.M{"$regex": regexp.QuoteMeta(fmt.Sprint(f.Value()))}
+		case OpPrefix:
+			cond = bson.M{"$regex": "^" + regexp.QuoteMeta(fmt.Sprint(f.Value()))}
+		case OpNull:
+			if isNull, _ := f.Value().(bool); isNull {
+				cond = nil
+			} else {
+				cond = bson.M{"$ne": nil}
+			}
+		default:
+			continue
+		}
+		conds = append(conds, bson.D{{Key: key, Value: cond}})
+	}
+	return conds
+}
+
+func mongoKeyset(sorts []Sort, after []interface{}) bson.D {
+	ors := make(bson.A, 0, len(sorts))
+	for i, s := range sorts {
+		and := make(bson.D, 0, i+1)
+		for j := 0; j < i; j++ {
+			and = append(and, bson.E{Key: sorts[j].Field.Key(), Value: after[j]})
+		}
+		op := "$gt"
+		if s.Desc {
+			op = "$lt"
+		}
+		and = append(and, bson.E{Key: s.Field.Key(), Value: bson.M{op: after[i]}})
+		ors = append(ors, and)
+	}
+	return bson.D{{Key: "$or", Value: ors}}
+}
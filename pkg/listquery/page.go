@@ -0,0 +1,80 @@
+package listquery
+
+// KeyFunc returns the value of a schema field of an item. It is used to build cursors and
+// is called with the names of the query's sort fields.
+type KeyFunc[T any] func(item T, field string) interface{}
+
+// PageInfo describes a page of a list response.
+type PageInfo struct {
+	Limit int `json:"limit"`
+	// Page is the 1-based page number in offset mode, omitted in cursor mode.
+	Page        int  `json:"page,omitempty"`
+	HasNext     bool `json:"has_next"`
+	HasPrevious bool `json:"has_previous"`
+	// NextCursor fetches the next page with ?cursor=; empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// TotalItems and TotalPages are only set when the total was counted (see WithTotal).
+	TotalItems *int64 `json:"total_items,omitempty"`
+	TotalPages *int64 `json:"total_pages,omitempty"`
+}
+
+// Page is the standard paged envelope returned as the data of list responses.
+type Page[T any] struct {
+	Items    []T      `json:"items"`
+	PageInfo PageInfo `json:"page_info"`
+
+	cursors []string
+}
+
+// Paginate builds a page from the rows fetched with q.FetchLimit(): the extra row, if any,
+// only signals that a next page exists and is dropped.
+func Paginate[T any](q *Query, rows []T, key KeyFunc[T]) *Page[T] {
+	hasNext := len(rows) > q.Limit
+	if hasNext {
+		rows = rows[:q.Limit]
+	}
+	if rows == nil {
+		rows = []T{}
+	}
+
+	page := &Page[T]{
+		Items: rows,
+		PageInfo: PageInfo{
+			Limit:       q.Limit,
+			Page:        q.Page,
+			HasNext:     hasNext,
+			HasPrevious: q.Page > 1 || len(q.After) > 0,
+		},
+		cursors: make([]string, len(rows)),
+	}
+	for i, item := range rows {
+		values := make([]interface{}, len(q.Sorts))
+		for j, s := range q.Sorts {
+			values[j] = normalize(key(item, s.Field.Name))
+		}
+		page.cursors[i] = EncodeCursor(q.Sorts, values)
+	}
+	if hasNext && len(rows) > 0 {
+		page.PageInfo.NextCursor = page.cursors[len(rows)-1]
+	}
+	return page
+}
+
+// WithTotal records the total number of items matching the query's filters.
+func (p *Page[T]) WithTotal(total int64) *Page[T] {
+	pages := int64(0)
+	if p.PageInfo.Limit > 0 {
+		pages = (total + int64(p.PageInfo.Limit) - 1) / int64(p.PageInfo.Limit)
+	}
+	p.PageInfo.TotalItems = &total
+	p.PageInfo.TotalPages = &pages
+	return p
+}
+
+// Cursor returns the opaque cursor pointing after the i-th item of the page.
+func (p *Page[T]) Cursor(i int) string {
+	if i < 0 || i >= len(p.cursors) {
+		return ""
+	}
+	return p.cursors[i]
+}
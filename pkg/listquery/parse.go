@@ -0,0 +1,203 @@
+package listquery
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse builds a Query from REST query parameters, validating every field against the schema.
+// Unknown parameters are ignored; invalid ones return an *Error.
+func Parse(schema *Schema, values url.Values) (*Query, error) {
+	defLimit, maxLimit := schema.limits()
+	q := &Query{Limit: defLimit}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return nil, invalid("limit", "must be a positive integer")
+		}
+		q.Limit = min(limit, maxLimit)
+	}
+
+	sorts, err := parseSort(schema, values.Get("sort"))
+	if err != nil {
+		return nil, err
+	}
+	q.Sorts = sorts
+
+	filters, err := parseFilters(schema, values)
+	if err != nil {
+		return nil, err
+	}
+	q.Filters = filters
+
+	rawPage, rawCursor := values.Get("page"), values.Get("cursor")
+	switch {
+	case rawPage != "" && rawCursor != "":
+		return nil, invalid("page", "page and cursor cannot be combined")
+	case rawCursor != "":
+		after, err := decodeCursor(rawCursor, q.Sorts)
+		if err != nil {
+			return nil, err
+		}
+		q.After = after
+	case rawPage != "":
+		page, err := strconv.Atoi(rawPage)
+		if err != nil || page < 1 {
+			return nil, invalid("page", "must be a positive integer")
+		}
+		q.Page = page
+	default:
+		q.Page = 1
+	}
+	return q, nil
+}
+
+// parseSort parses a comma-separated sort expression and appends the schema's tiebreaker.
+func parseSort(schema *Schema, raw string) ([]Sort, error) {
+	if raw == "" {
+		raw = schema.DefaultSort
+	}
+
+	var sorts []Sort
+	seen := make(map[string]bool)
+	for _, term := range strings.Split(raw, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		desc := strings.HasPrefix(term, "-")
+		name := strings.TrimPrefix(strings.TrimPrefix(term, "-"), "+")
+		field, ok := schema.Field(name)
+		if !ok || !field.Sortable {
+			return nil, invalid("sort", "field %q is not sortable", name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		sorts = append(sorts, Sort{Field: field, Desc: desc})
+	}
+
+	if schema.Tiebreaker != "" && !seen[schema.Tiebreaker] {
+		field, ok := schema.Field(schema.Tiebreaker)
+		if !ok {
+			return nil, invalid("sort", "tiebreaker %q is not a schema field", schema.Tiebreaker)
+		}
+		desc := len(sorts) > 0 && sorts[len(sorts)-1].Desc
+		sorts = append(sorts, Sort{Field: field, Desc: desc})
+	}
+	return sorts, nil
+}
+
+// parseFilters parses filter[field]=value and filter[field][op]=value parameters.
+func parseFilters(schema *Schema, values url.Values) ([]Filter, error) {
+	params := make([]string, 0, len(values))
+	for param := range values {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	var filters []Filter
+	for _, param := range params {
+		name, op, ok := filterParam(param)
+		if !ok {
+			continue
+		}
+		field, ok := schema.Field(name)
+		if !ok || !field.Filterable {
+			return nil, invalid(param, "field %q is not filterable", name)
+		}
+		if !field.allows(op) {
+			return nil, invalid(param, "operator %q is not allowed on %q", op, name)
+		}
+		for _, raw := range values[param] {
+			filter, err := buildFilter(param, field, op, raw)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, filter)
+		}
+	}
+	return filters, nil
+}
+
+// filterParam splits "filter[name]" and "filter[name][op]".
+func filterParam(param string) (string, Op, bool) {
+	rest, ok := strings.CutPrefix(param, "filter[")
+	if !ok {
+		return "", "", false
+	}
+	name, rest, ok := strings.Cut(rest, "]")
+	if !ok || name == "" {
+		return "", "", false
+	}
+	if rest == "" {
+		return name, OpEq, true
+	}
+	op, ok := strings.CutPrefix(rest, "[")
+	if !ok || !strings.HasSuffix(op, "]") {
+		return "", "", false
+	}
+	return name, Op(strings.TrimSuffix(op, "]")), true
+}
+
+func buildFilter(param string, field Field, op Op, raw string) (Filter, error) {
+	if op == OpNull {
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Filter{}, invalid(param, "must be true or false")
+		}
+		return Filter{Field: field, Op: op, Values: []interface{}{isNull}}, nil
+	}
+
+	raws := []string{raw}
+	if op == OpIn || op == OpNin {
+		raws = strings.Split(raw, ",")
+	}
+	values := make([]interface{}, 0, len(raws))
+	for _, r := range raws {
+		v, err := parseValue(field.Type, strings.TrimSpace(r))
+		if err != nil {
+			return Filter{}, invalid(param, "%s", err.Error())
+		}
+		values = append(values, v)
+	}
+	return Filter{Field: field, Op: op, Values: values}, nil
+}
+
+// parseValue converts a raw string to the Go type of a field.
+func parseValue(typ FieldType, raw string) (interface{}, error) {
+	switch typ {
+	case Int:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, errors.New("expected an integer")
+		}
+		return v, nil
+	case Float:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, errors.New("expected a number")
+		}
+		return v, nil
+	case Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("expected true or false")
+		}
+		return v, nil
+	case Time:
+		v, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, errors.New("expected an RFC 3339 timestamp")
+		}
+		return v.UTC(), nil
+	default:
+		return raw, nil
+	}
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"math/rand/v2"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MaskedValue replaces sensitive values in access log entries.
+const MaskedValue = "***"
+
+// ShouldSample reports whether an entry should be logged for the given sample rate.
+// A rate >= 1 always logs and a rate <= 0 never logs.
+func ShouldSample(rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	return rand.Float64() < rate
+}
+
+// MaskValues flattens multi-valued headers or metadata into a map suitable for
+// structured logging, replacing the values of sensitive keys (case-insensitive).
+func MaskValues(values map[string][]string, sensitive []string) map[string]string {
+	result := make(map[string]string, len(values))
+	for key, vals := range values {
+		if containsFold(sensitive, key) {
+			result[key] = MaskedValue
+			continue
+		}
+		result[key] = strings.Join(vals, ",")
+	}
+	return result
+}
+
+// MaskQuery encodes the query parameters with the values of sensitive
+// parameters (case-insensitive) replaced. Keys are sorted for stable output.
+func MaskQuery(query url.Values, sensitive []string) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		for _, v := range query[key] {
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(url.QueryEscape(key))
+			sb.WriteByte('=')
+			if containsFold(sensitive, key) {
+				sb.WriteString(MaskedValue)
+			} else {
+				sb.WriteString(url.QueryEscape(v))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestShouldSample(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if !ShouldSample(1) {
+			t.Fatal("ShouldSample(1) = false, want every entry logged")
+		}
+		if ShouldSample(0) {
+			t.Fatal("ShouldSample(0) = true, want no entry logged")
+		}
+	}
+}
+
+func TestMaskValues(t *testing.T) {
+	got := MaskValues(map[string][]string{
+		"Authorization": {"Bearer secret"},
+		"Accept":        {"text/html", "application/json"},
+	}, []string{"authorization"})
+
+	if got["Authorization"] != MaskedValue {
+		t.Errorf("Authorization = %q, want it masked", got["Authorization"])
+	}
+	if got["Accept"] != "text/html,application/json" {
+		t.Errorf("Accept = %q, want the values joined", got["Accept"])
+	}
+}
+
+func TestMaskQuery(t *testing.T) {
+	query := url.Values{"token": {"secret"}, "q": {"a b", "c"}, "Page": {"2"}}
+	if got, want := MaskQuery(query, []string{"TOKEN"}), "Page=2&q=a+b&q=c&token=***"; got != want {
+		t.Errorf("MaskQuery() = %q, want %q", got, want)
+	}
+	if got := MaskQuery(nil, []string{"token"}); got != "" {
+		t.Errorf("MaskQuery() of no parameters = %q, want empty", got)
+	}
+}
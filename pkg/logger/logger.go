@@ -6,7 +6,8 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// NewLogger creates a new logger instance with JSON formatting
+// NewLogger creates a new logger instance with JSON formatting.
+// Entries logged with WithContext carry the trace ID of the active span.
 func NewLogger() *logrus.Logger {
 	log := logrus.New()
 	log.SetFormatter(&logrus.TextFormatter{
@@ -14,6 +15,7 @@ func NewLogger() *logrus.Logger {
 	})
 	log.SetOutput(os.Stdout)
 	log.SetLevel(logrus.InfoLevel)
+	log.AddHook(TraceHook{})
 	return log
 }
 
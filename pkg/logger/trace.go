@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceHook adds the trace_id and span_id of the active span to entries logged with
+// WithContext, so that logs can be correlated with traces.
+type TraceHook struct{}
+
+// Levels returns all levels.
+func (TraceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the trace fields when the entry's context carries a valid span context.
+func (TraceHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	sc := trace.SpanContextFromContext(entry.Context)
+	if !sc.IsValid() {
+		return nil
+	}
+	entry.Data["trace_id"] = sc.TraceID().String()
+	entry.Data["span_id"] = sc.SpanID().String()
+	return nil
+}
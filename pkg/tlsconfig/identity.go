@@ -0,0 +1,51 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// Identity describes the client of a mutually authenticated connection, taken from its
+// verified certificate.
+type Identity struct {
+	CommonName   string
+	Organization []string
+	DNSNames     []string
+	// URIs holds the URI SANs, e.g. SPIFFE IDs.
+	URIs        []string
+	Certificate *x509.Certificate
+}
+
+type identityCtxKey struct{}
+
+// IdentityFromState returns the identity of the client's verified certificate. It returns
+// false when the client did not present a certificate or it was not verified.
+func IdentityFromState(state *tls.ConnectionState) (Identity, bool) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return Identity{}, false
+	}
+	cert := state.VerifiedChains[0][0]
+	id := Identity{
+		CommonName:   cert.Subject.CommonName,
+		Organization: cert.Subject.Organization,
+		DNSNames:     cert.DNSNames,
+		Certificate:  cert,
+	}
+	for _, u := range cert.URIs {
+		id.URIs = append(id.URIs, u.String())
+	}
+	return id, true
+}
+
+// ContextWithIdentity returns a copy of ctx carrying id.
+func ContextWithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityCtxKey{}, id)
+}
+
+// IdentityFromContext returns the client identity stored by the transport middleware. It
+// returns false for requests without a verified client certificate.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityCtxKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,178 @@
+// Package tlsconfig builds server TLS configurations from config.TLSConfig. Certificates, keys
+// and client CA bundles are reloaded when their files change, so that rotated certificates are
+// picked up without a restart, and verified client certificates are exposed as an Identity.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// reloadInterval bounds how often the files are checked for changes during handshakes.
+var reloadInterval = 5 * time.Second
+
+// New returns the server TLS configuration for cfg, or nil when TLS is disabled. The files are
+// loaded once up front, so that a missing or invalid certificate prevents startup.
+func New(log *logrus.Logger, cfg config.TLSConfig) (*tls.Config, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("tls cert_file and key_file are required when TLS is enabled")
+	}
+	clientAuth, err := parseClientAuth(cfg.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth != tls.NoClientCert && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("tls client_ca_file is required when client_auth is %q", cfg.ClientAuth)
+	}
+
+	r := &reloader{log: log, cfg: cfg, clientAuth: clientAuth}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.current().Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current(), nil
+		},
+	}, nil
+}
+
+func parseClientAuth(s string) (tls.ClientAuthType, error) {
+	switch s {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "optional":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("unsupported tls client_auth: %s", s)
+	}
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// reloader holds the configuration built from the current files.
+type reloader struct {
+	log        *logrus.Logger
+	cfg        config.TLSConfig
+	clientAuth tls.ClientAuthType
+
+	mu      sync.Mutex
+	config  *tls.Config
+	stamps  []fileStamp
+	checked time.Time
+}
+
+func (r *reloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+// current returns the configuration for a handshake, reloading it first when the files have
+// changed. A failed reload keeps the previous certificate.
+func (r *reloader) current() *tls.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) >= reloadInterval {
+		r.checked = time.Now()
+		if stamps, err := stat(r.files()); err == nil && !equalStamps(stamps, r.stamps) {
+			if err := r.loadLocked(); err != nil {
+				r.log.WithError(err).Warn("Failed to reload TLS certificate, keeping the previous one")
+			} else {
+				r.log.Infof("Reloaded TLS certificate %s", r.cfg.CertFile)
+			}
+		}
+	}
+	return r.config
+}
+
+func (r *reloader) load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checked = time.Now()
+	return r.loadLocked()
+}
+
+func (r *reloader) loadLocked() error {
+	// Stat before reading so that a change during the read is picked up by the next check
+	stamps, err := stat(r.files())
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   r.clientAuth,
+		// Set explicitly because this configuration replaces the server's own per handshake
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read TLS client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in TLS client CA file %s", r.cfg.ClientCAFile)
+		}
+		cfg.ClientCAs = pool
+	}
+
+	r.config = cfg
+	r.stamps = stamps
+	return nil
+}
+
+func stat(files []string) ([]fileStamp, error) {
+	stamps := make([]fileStamp, len(files))
+	for i, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat TLS file: %w", err)
+		}
+		stamps[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps, nil
+}
+
+func equalStamps(a, b []fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
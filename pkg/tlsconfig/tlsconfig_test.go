@@ -0,0 +1,199 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azahir21/go-backend-boilerplate/pkg/config"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newCert(t *testing.T, cn string, serial int64, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"test"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+func (c *testCert) write(t *testing.T, certFile, keyFile string) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, certFile, "CERTIFICATE", c.cert.Raw)
+	if keyFile != "" {
+		writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	}
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake connects a client to a server using serverCfg and returns the server's view of
+// the connection and the certificate presented by the server.
+func handshake(t *testing.T, serverCfg *tls.Config, roots *x509.CertPool, clientCert *testCert) (tls.ConnectionState, *x509.Certificate, error) {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	type result struct {
+		state tls.ConnectionState
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		err = tlsConn.Handshake()
+		done <- result{state: tlsConn.ConnectionState(), err: err}
+	}()
+
+	clientCfg := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+	if clientCert != nil {
+		clientCfg.Certificates = []tls.Certificate{clientCert.tlsCertificate()}
+	}
+	conn, err := tls.Dial("tcp", lis.Addr().String(), clientCfg)
+	var serverCert *x509.Certificate
+	if err == nil {
+		serverCert = conn.ConnectionState().PeerCertificates[0]
+		// TLS 1.3 client certificates are verified after the client's handshake completes
+		_, _ = conn.Read(make([]byte, 1))
+		conn.Close()
+	}
+	res := <-done
+	return res.state, serverCert, res.err
+}
+
+func TestNew_MutualTLSAndReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	ca := newCert(t, "test-ca", 1, nil)
+	ca.write(t, caFile, "")
+	newCert(t, "server", 2, ca).write(t, certFile, keyFile)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	log, _ := test.NewNullLogger()
+	cfg, err := New(log, config.TLSConfig{
+		Enable:       true,
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		ClientAuth:   "require",
+		ClientCAFile: caFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, serverCert, err := handshake(t, cfg, roots, newCert(t, "billing-service", 3, ca))
+	if err != nil {
+		t.Fatalf("handshake with client certificate: %v", err)
+	}
+	if serverCert.SerialNumber.Int64() != 2 {
+		t.Errorf("server certificate serial = %d, want 2", serverCert.SerialNumber)
+	}
+	id, ok := IdentityFromState(&state)
+	if !ok || id.CommonName != "billing-service" || id.Organization[0] != "test" {
+		t.Errorf("IdentityFromState() = %+v, %v", id, ok)
+	}
+
+	if _, _, err := handshake(t, cfg, roots, nil); err == nil {
+		t.Error("handshake without client certificate succeeded, want error")
+	}
+
+	// A rotated certificate is served without rebuilding the configuration
+	reloadInterval = 0
+	defer func() { reloadInterval = 5 * time.Second }()
+	newCert(t, "server", 4, ca).write(t, certFile, keyFile)
+	later := time.Now().Add(time.Minute)
+	for _, f := range []string{certFile, keyFile} {
+		if err := os.Chtimes(f, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, serverCert, err = handshake(t, cfg, roots, newCert(t, "billing-service", 5, ca)); err != nil {
+		t.Fatal(err)
+	}
+	if serverCert.SerialNumber.Int64() != 4 {
+		t.Errorf("server certificate serial after rotation = %d, want 4", serverCert.SerialNumber)
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	if cfg, err := New(log, config.TLSConfig{}); cfg != nil || err != nil {
+		t.Errorf("New(disabled) = %v, %v, want nil, nil", cfg, err)
+	}
+	tests := map[string]config.TLSConfig{
+		"missing key":       {Enable: true, CertFile: "tls.crt"},
+		"unknown auth":      {Enable: true, CertFile: "tls.crt", KeyFile: "tls.key", ClientAuth: "sometimes"},
+		"missing client CA": {Enable: true, CertFile: "tls.crt", KeyFile: "tls.key", ClientAuth: "require"},
+		"missing files":     {Enable: true, CertFile: "missing.crt", KeyFile: "missing.key"},
+	}
+	for name, tc := range tests {
+		if _, err := New(log, tc); err == nil {
+			t.Errorf("%s: New() error = nil", name)
+		}
+	}
+}